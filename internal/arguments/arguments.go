@@ -0,0 +1,620 @@
+package arguments
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/laytan/php-parser/pkg/ast"
+	"github.com/laytan/php-parser/pkg/visitor"
+	"github.com/laytan/php-parser/pkg/visitor/traverser"
+	"github.com/laytan/phpls/internal/expr"
+	"github.com/laytan/phpls/internal/index"
+	"github.com/laytan/phpls/internal/symbol"
+	"github.com/laytan/phpls/internal/wrkspc"
+	"github.com/laytan/phpls/pkg/fqn"
+	"github.com/laytan/phpls/pkg/nodeident"
+	"github.com/laytan/phpls/pkg/nodescopes"
+	"github.com/laytan/phpls/pkg/phpdoxer"
+)
+
+type rooter interface {
+	Root() *ast.Root
+	Path() string
+}
+
+type Kind int
+
+const (
+	KindTooFew Kind = iota
+	KindTooMany
+	KindUnknownNamed
+	KindType
+)
+
+type Violation struct {
+	// The node of the called name for count violations, or the argument.
+	Node ast.Vertex
+	Kind Kind
+	// The called function or method, like "function foo()".
+	Callee string
+	// The amount of arguments passed and the expected amount, like "at least 2".
+	Passed   int
+	Expected string
+	// The parameter, for unknown named arguments and type mismatches.
+	Param string
+	// The position of the argument, starting at 1, for type mismatches.
+	Position int
+	// The type the parameter expects and the type given, for type mismatches.
+	Type  string
+	Given string
+}
+
+func (v *Violation) Message() string {
+	switch v.Kind {
+	case KindTooFew:
+		return fmt.Sprintf(
+			"Too few arguments to %s, %d passed and %s expected.",
+			v.Callee,
+			v.Passed,
+			v.Expected,
+		)
+	case KindTooMany:
+		return fmt.Sprintf(
+			"Too many arguments to %s, %d passed and %s expected.",
+			v.Callee,
+			v.Passed,
+			v.Expected,
+		)
+	case KindUnknownNamed:
+		return fmt.Sprintf("Unknown named parameter %s in call to %s.", v.Param, v.Callee)
+	default:
+		return fmt.Sprintf(
+			"Argument #%d (%s) of %s must be of type %s, %s given.",
+			v.Position,
+			v.Param,
+			v.Callee,
+			v.Type,
+			v.Given,
+		)
+	}
+}
+
+func (v *Violation) Code() string {
+	switch v.Kind {
+	case KindTooFew, KindTooMany:
+		return "argument-count"
+	case KindUnknownNamed:
+		return "unknown-named-argument"
+	default:
+		return "argument-type"
+	}
+}
+
+func (v *Violation) Line() int {
+	return v.Node.GetPosition().StartLine
+}
+
+// Diagnose checks the arguments of calls to functions, methods and
+// constructors that can be resolved, against the parameters they declare.
+//
+// It reports too few or too many arguments, named arguments that don't match
+// a parameter and literal arguments that can't be passed to the native type
+// hint of their parameter, following the strict_types declaration of the file.
+func Diagnose(root rooter) []*Violation {
+	fqnt := fqn.NewTraverser()
+	root.Root().Accept(traverser.NewTraverser(fqnt))
+
+	t := &argumentsTraverser{
+		rooter:  root,
+		fqnt:    fqnt,
+		strict:  isStrict(root.Root()),
+		classes: []ast.Vertex{root.Root()},
+		blocks:  []ast.Vertex{root.Root()},
+	}
+	root.Root().Accept(traverser.NewTraverser(t))
+
+	return t.violations
+}
+
+// callee is a resolved function or method.
+type callee struct {
+	// Like "function foo()" or "method bar()".
+	name   string
+	params []*ast.Parameter
+	// The function accesses its arguments with func_get_args, any amount of
+	// arguments can be passed.
+	dynamic bool
+}
+
+type argumentsTraverser struct {
+	visitor.Null
+
+	rooter rooter
+	fqnt   *fqn.Traverser
+	strict bool
+
+	classes []ast.Vertex
+	blocks  []ast.Vertex
+
+	violations []*Violation
+}
+
+func (t *argumentsTraverser) EnterNode(node ast.Vertex) bool {
+	if nodescopes.IsScope(node.GetType()) {
+		t.blocks = append(t.blocks, node)
+	}
+
+	if nodescopes.IsClassLike(node.GetType()) {
+		t.classes = append(t.classes, node)
+	}
+
+	switch typedNode := node.(type) {
+	case *ast.ExprFunctionCall:
+		if nodescopes.IsName(typedNode.Function.GetType()) {
+			t.call(node, typedNode.Function, typedNode.Args)
+		}
+
+	case *ast.ExprMethodCall:
+		if _, ok := typedNode.Method.(*ast.Identifier); ok {
+			t.call(node, typedNode.Method, typedNode.Args)
+		}
+
+	case *ast.ExprStaticCall:
+		if _, ok := typedNode.Call.(*ast.Identifier); ok {
+			t.call(node, typedNode.Call, typedNode.Args)
+		}
+
+	case *ast.ExprNew:
+		t.construct(typedNode)
+	}
+
+	return true
+}
+
+func (t *argumentsTraverser) LeaveNode(node ast.Vertex) {
+	if nodescopes.IsScope(node.GetType()) {
+		t.blocks = t.blocks[:len(t.blocks)-1]
+	}
+
+	if nodescopes.IsClassLike(node.GetType()) {
+		t.classes = t.classes[:len(t.classes)-1]
+	}
+}
+
+func (t *argumentsTraverser) call(node ast.Vertex, name ast.Vertex, args []ast.Vertex) {
+	res, _, left := expr.Resolve(node, &expr.Scopes{
+		Path:  t.rooter.Path(),
+		Root:  t.rooter.Root(),
+		Class: t.classes[len(t.classes)-1],
+		Block: t.blocks[len(t.blocks)-1],
+	})
+	if left != 0 || res == nil || res.Node == nil {
+		return
+	}
+
+	if c := newCallee(res.Node); c != nil {
+		t.check(name, args, c)
+	}
+}
+
+// construct checks the arguments of new Foo() against the constructor of Foo.
+func (t *argumentsTraverser) construct(node *ast.ExprNew) {
+	if !nodescopes.IsName(node.Class.GetType()) {
+		return
+	}
+
+	// The class of static and parent depends on the context, self is left out
+	// for consistency.
+	switch strings.ToLower(nodeident.Get(node.Class)) {
+	case "self", "static", "parent":
+		return
+	}
+
+	qualified := t.fqnt.ResultFor(node.Class)
+	if qualified == nil {
+		return
+	}
+
+	iNode, ok := index.Current.Find(qualified)
+	if !ok || !iNode.MatchesKind(ast.TypeStmtClass) {
+		return
+	}
+
+	var clsRooter rooter = wrkspc.NewRooter(iNode.Path)
+	if iNode.Path == t.rooter.Path() {
+		clsRooter = t.rooter
+	}
+
+	cls, err := symbol.NewClassLikeFromFQN(clsRooter, qualified)
+	if err != nil {
+		return
+	}
+
+	constructor := findConstructor(cls)
+	if constructor == nil {
+		return
+	}
+
+	c := newCallee(constructor.Node())
+	c.name = fmt.Sprintf("constructor of %s", qualified)
+	t.check(node.Class, node.Args, c)
+}
+
+func findConstructor(cls *symbol.ClassLike) *symbol.Method {
+	isConstructor := func(m *symbol.Method) bool {
+		return strings.EqualFold(m.Name(), "__construct")
+	}
+
+	if m := cls.FindMethod(isConstructor); m != nil {
+		return m
+	}
+
+	iter := cls.InheritsIter()
+	for inhCls, done, err := iter(); !done; inhCls, done, err = iter() {
+		if err != nil {
+			return nil
+		}
+
+		if m := inhCls.FindMethod(isConstructor); m != nil {
+			return m
+		}
+	}
+
+	return nil
+}
+
+func newCallee(node ast.Vertex) *callee {
+	var c *callee
+	var params []ast.Vertex
+	switch typedNode := node.(type) {
+	case *ast.StmtFunction:
+		c = &callee{name: fmt.Sprintf("function %s()", nodeident.Get(typedNode.Name))}
+		params = typedNode.Params
+	case *ast.StmtClassMethod:
+		c = &callee{name: fmt.Sprintf("method %s()", nodeident.Get(typedNode.Name))}
+		params = typedNode.Params
+	default:
+		return nil
+	}
+
+	for _, param := range params {
+		c.params = append(c.params, param.(*ast.Parameter))
+	}
+
+	dt := &dynamicTraverser{function: node}
+	node.Accept(traverser.NewTraverser(dt))
+	c.dynamic = dt.dynamic
+
+	return c
+}
+
+func (t *argumentsTraverser) check(name ast.Vertex, args []ast.Vertex, c *callee) {
+	variadic := len(c.params) > 0 && c.params[len(c.params)-1].VariadicTkn != nil
+	provided := make([]bool, len(c.params))
+
+	positional, passed := 0, 0
+	unknown := false
+	for _, arg := range args {
+		argument, ok := arg.(*ast.Argument)
+		// Unpacked arguments, or a first-class callable, can't be counted.
+		if !ok || argument.VariadicTkn != nil {
+			return
+		}
+
+		if argument.Name != nil {
+			paramName := "$" + nodeident.Get(argument.Name)
+			i := paramIndex(c.params, paramName)
+			if i == -1 || c.params[i].VariadicTkn != nil {
+				// A variadic parameter collects unknown named arguments.
+				if !variadic {
+					unknown = true
+					t.violations = append(t.violations, &Violation{
+						Node:   argument.Name,
+						Kind:   KindUnknownNamed,
+						Callee: c.name,
+						Param:  paramName,
+					})
+				}
+
+				continue
+			}
+
+			passed++
+			provided[i] = true
+			t.checkType(argument, i+1, c.params[i], c)
+			continue
+		}
+
+		switch {
+		case positional < len(c.params):
+			provided[positional] = true
+			t.checkType(argument, positional+1, c.params[positional], c)
+		case variadic:
+			t.checkType(argument, positional+1, c.params[len(c.params)-1], c)
+		}
+
+		positional++
+		passed++
+	}
+
+	// PHP errors on the unknown named argument before counting.
+	if unknown {
+		return
+	}
+
+	required := requiredCount(c.params)
+
+	if !variadic && !c.dynamic && positional > len(c.params) {
+		expected := "at most " + strconv.Itoa(len(c.params))
+		if required == len(c.params) {
+			expected = "exactly " + strconv.Itoa(required)
+		}
+
+		t.violations = append(t.violations, &Violation{
+			Node:     name,
+			Kind:     KindTooMany,
+			Callee:   c.name,
+			Passed:   passed,
+			Expected: expected,
+		})
+		return
+	}
+
+	for i := 0; i < required; i++ {
+		if provided[i] {
+			continue
+		}
+
+		expected := "at least " + strconv.Itoa(required)
+		if !variadic && required == len(c.params) {
+			expected = "exactly " + strconv.Itoa(required)
+		}
+
+		t.violations = append(t.violations, &Violation{
+			Node:     name,
+			Kind:     KindTooFew,
+			Callee:   c.name,
+			Passed:   passed,
+			Expected: expected,
+		})
+		return
+	}
+}
+
+// checkType checks literal arguments against the native type hint of the parameter.
+func (t *argumentsTraverser) checkType(
+	argument *ast.Argument,
+	position int,
+	param *ast.Parameter,
+	c *callee,
+) {
+	if param.Type == nil || param.AmpersandTkn != nil {
+		return
+	}
+
+	given, numeric, ok := literalType(argument.Expr)
+	if !ok {
+		return
+	}
+
+	typ, err := phpdoxer.ParseType(hintString(param.Type))
+	if err != nil {
+		return
+	}
+
+	// A default of null makes the parameter implicitly nullable.
+	if given == "null" && isNull(param.DefaultValue) {
+		return
+	}
+
+	if accepts(typ, given, numeric, t.strict) {
+		return
+	}
+
+	t.violations = append(t.violations, &Violation{
+		Node:     argument,
+		Kind:     KindType,
+		Callee:   c.name,
+		Param:    nodeident.Get(param.Var),
+		Position: position,
+		Type:     typ.String(),
+		Given:    given,
+	})
+}
+
+// accepts returns whether a literal of the given type can be passed to a
+// parameter of the type, scalars are coerced when not strict.
+func accepts(typ phpdoxer.Type, given string, numeric bool, strict bool) bool {
+	switch typedType := typ.(type) {
+	case *phpdoxer.TypeUnion:
+		return accepts(typedType.Left, given, numeric, strict) ||
+			accepts(typedType.Right, given, numeric, strict)
+
+	case *phpdoxer.TypePrecedence:
+		return accepts(typedType.Type, given, numeric, strict)
+
+	case *phpdoxer.TypeIntersection, *phpdoxer.TypeClassLike, *phpdoxer.TypeObject:
+		return given == "Closure"
+
+	case *phpdoxer.TypeNull:
+		return given == "null"
+
+	case *phpdoxer.TypeInt:
+		return given == "int" ||
+			(!strict && (given == "float" || given == "bool" || (given == "string" && numeric)))
+
+	case *phpdoxer.TypeFloat:
+		return given == "int" || given == "float" ||
+			(!strict && (given == "bool" || (given == "string" && numeric)))
+
+	case *phpdoxer.TypeString:
+		return given == "string" ||
+			(!strict && (given == "int" || given == "float" || given == "bool"))
+
+	case *phpdoxer.TypeBool:
+		return given == "bool" ||
+			(!strict && (given == "int" || given == "float" || given == "string"))
+
+	case *phpdoxer.TypeArray, *phpdoxer.TypeIterable:
+		return given == "array"
+
+	case *phpdoxer.TypeCallable:
+		return given == "string" || given == "array" || given == "Closure"
+
+	default:
+		// Mixed, and types that are not native hints.
+		return true
+	}
+}
+
+// literalType returns the type of a literal expression, numeric is whether a
+// string could be a number.
+func literalType(node ast.Vertex) (typ string, numeric bool, ok bool) {
+	switch typedNode := node.(type) {
+	case *ast.ScalarLnumber:
+		return "int", false, true
+
+	case *ast.ScalarDnumber:
+		return "float", false, true
+
+	case *ast.ExprUnaryMinus:
+		return literalType(typedNode.Expr)
+
+	case *ast.ExprUnaryPlus:
+		return literalType(typedNode.Expr)
+
+	case *ast.ScalarString:
+		value := string(typedNode.Value)
+		if len(value) >= 2 {
+			value = value[1 : len(value)-1]
+		}
+
+		_, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		return "string", err == nil, true
+
+	case *ast.ScalarEncapsed, *ast.ScalarHeredoc:
+		// The interpolated value could be numeric.
+		return "string", true, true
+
+	case *ast.ExprArray:
+		return "array", false, true
+
+	case *ast.ExprClosure, *ast.ExprArrowFunction:
+		return "Closure", false, true
+
+	case *ast.ExprConstFetch:
+		switch strings.ToLower(nodeident.Get(typedNode.Const)) {
+		case "true", "false":
+			return "bool", false, true
+		case "null":
+			return "null", false, true
+		}
+	}
+
+	return "", false, false
+}
+
+func hintString(hint ast.Vertex) string {
+	switch typedHint := hint.(type) {
+	case *ast.Nullable:
+		return "null|" + hintString(typedHint.Expr)
+
+	case *ast.Union:
+		parts := make([]string, 0, len(typedHint.Types))
+		for _, typ := range typedHint.Types {
+			parts = append(parts, hintString(typ))
+		}
+
+		return strings.Join(parts, "|")
+
+	case *ast.Intersection:
+		parts := make([]string, 0, len(typedHint.Types))
+		for _, typ := range typedHint.Types {
+			parts = append(parts, hintString(typ))
+		}
+
+		return strings.Join(parts, "&")
+
+	default:
+		return nodeident.Get(hint)
+	}
+}
+
+// requiredCount returns the amount of arguments that have to be passed,
+// optional parameters before a required one are required too.
+func requiredCount(params []*ast.Parameter) int {
+	for i := len(params) - 1; i >= 0; i-- {
+		if params[i].DefaultValue == nil && params[i].VariadicTkn == nil {
+			return i + 1
+		}
+	}
+
+	return 0
+}
+
+func paramIndex(params []*ast.Parameter, name string) int {
+	for i, param := range params {
+		if nodeident.Get(param.Var) == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func isNull(node ast.Vertex) bool {
+	constant, ok := node.(*ast.ExprConstFetch)
+	return ok && strings.EqualFold(nodeident.Get(constant.Const), "null")
+}
+
+// isStrict returns whether the file declares strict_types=1.
+func isStrict(root *ast.Root) bool {
+	for _, stmt := range root.Stmts {
+		declare, ok := stmt.(*ast.StmtDeclare)
+		if !ok {
+			continue
+		}
+
+		for _, constant := range declare.Consts {
+			constant, ok := constant.(*ast.StmtConstant)
+			if !ok || !strings.EqualFold(nodeident.Get(constant.Name), "strict_types") {
+				continue
+			}
+
+			value, ok := constant.Expr.(*ast.ScalarLnumber)
+			return ok && string(value.Value) == "1"
+		}
+	}
+
+	return false
+}
+
+// dynamicTraverser finds calls to the functions that access the arguments of
+// the current function.
+type dynamicTraverser struct {
+	visitor.Null
+
+	function ast.Vertex
+	dynamic  bool
+}
+
+func (t *dynamicTraverser) EnterNode(node ast.Vertex) bool {
+	if t.dynamic {
+		return false
+	}
+
+	switch typedNode := node.(type) {
+	case *ast.ExprClosure, *ast.ExprArrowFunction, *ast.StmtFunction, *ast.StmtClass:
+		// Nested functions have their own arguments.
+		return node == t.function
+
+	case *ast.ExprFunctionCall:
+		switch strings.ToLower(nodeident.Get(typedNode.Function)) {
+		case "func_get_args", "func_get_arg", "func_num_args":
+			t.dynamic = true
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,92 @@
+package arguments_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/laytan/phpls/internal/arguments"
+	"github.com/laytan/phpls/internal/config"
+	"github.com/laytan/phpls/internal/index"
+	"github.com/laytan/phpls/internal/project"
+	"github.com/laytan/phpls/internal/wrkspc"
+	"github.com/laytan/phpls/pkg/functional"
+	"github.com/laytan/phpls/pkg/pathutils"
+	"github.com/laytan/phpls/pkg/phpversion"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(
+		m,
+		// The cache size logger.
+		goleak.IgnoreTopFunction("github.com/laytan/phpls/internal/wrkspc.New.func1"),
+	)
+}
+
+func TestDiagnose(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.Join(pathutils.Root(), "internal", "arguments", "testdata")
+
+	err := setup(root, phpversion.EightOne())
+	require.NoError(t, err)
+
+	format := func(v *arguments.Violation) string {
+		return fmt.Sprintf("%d %s %s", v.Line(), v.Code(), v.Message())
+	}
+
+	tests := map[string][]string{
+		"arguments.php": {
+			`48 argument-count Too few arguments to function exact(), 1 passed and exactly 2 expected.`,
+			`49 argument-count Too many arguments to function exact(), 3 passed and exactly 2 expected.`,
+			`51 unknown-named-argument Unknown named parameter $c in call to function exact().`,
+			`54 argument-count Too few arguments to function optional(), 0 passed and at least 1 expected.`,
+			`55 argument-count Too many arguments to function optional(), 4 passed and at most 3 expected.`,
+			`57 argument-count Too few arguments to function variadic(), 0 passed and at least 1 expected.`,
+			`58 argument-count Too few arguments to function variadic(), 0 passed and at least 1 expected.`,
+			`63 argument-type Argument #1 ($int) of function typed() must be of type int, string given.`,
+			`63 argument-type Argument #2 ($float) of function typed() must be of type float, string given.`,
+			`63 argument-type Argument #3 ($string) of function typed() must be of type string, array given.`,
+			`63 argument-type Argument #4 ($bool) of function typed() must be of type bool, array given.`,
+			`63 argument-type Argument #5 ($array) of function typed() must be of type array, string given.`,
+			`63 argument-type Argument #7 ($greeter) of function typed() must be of type Greeter, string given.`,
+			`63 argument-type Argument #8 ($union) of function typed() must be of type int|string, array given.`,
+			`68 argument-count Too many arguments to method greet(), 3 passed and at most 2 expected.`,
+			`69 argument-count Too few arguments to method greet(), 0 passed and at least 1 expected.`,
+			`70 argument-count Too few arguments to method create(), 0 passed and exactly 1 expected.`,
+			`71 argument-count Too few arguments to constructor of \Arguments\TestData\Greeter, 0 passed and exactly 1 expected.`,
+			`72 argument-count Too few arguments to constructor of \Arguments\TestData\LoudGreeter, 0 passed and exactly 1 expected.`,
+			`73 argument-count Too many arguments to constructor of \Arguments\TestData\LoudGreeter, 2 passed and exactly 1 expected.`,
+		},
+		"strict.php": {
+			`7 argument-type Argument #3 ($string) of function typed() must be of type string, float given.`,
+			`7 argument-type Argument #4 ($bool) of function typed() must be of type bool, int given.`,
+			`8 argument-type Argument #1 ($a) of function exact() must be of type int, string given.`,
+			`8 argument-type Argument #2 ($b) of function exact() must be of type string, int given.`,
+		},
+	}
+
+	for file, expected := range tests {
+		violations := arguments.Diagnose(wrkspc.NewRooter(filepath.Join(root, file)))
+		require.Equal(t, expected, functional.Map(violations, format), file)
+	}
+}
+
+func setup(root string, phpv *phpversion.PHPVersion) error {
+	config.Current = config.Default()
+	index.Current = index.New(phpv)
+	wrkspc.Current = wrkspc.New(
+		phpv,
+		root,
+		filepath.Join(pathutils.Root(), "third_party", "phpstorm-stubs"),
+	)
+
+	p := project.New()
+	if err := p.ParseWithoutProgress(); err != nil {
+		return fmt.Errorf("[arguments_test.setup]: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+<?php
+
+namespace Arguments\TestData;
+
+function exact(int $a, string $b): void
+{
+}
+
+function optional(int $a, int $b = 1, ?string $c = null): void
+{
+}
+
+function variadic(string $format, mixed ...$values): void
+{
+}
+
+function dynamic(): void
+{
+    $args = func_get_args();
+}
+
+function typed(int $int, float $float, string $string, bool $bool, array $array, callable $callable, Greeter $greeter, int|string $union, Greeter $nullable = null): void
+{
+}
+
+class Greeter
+{
+    public function __construct(private string $name)
+    {
+    }
+
+    public function greet(string $greeting, bool $loud = false): string
+    {
+        return $greeting . $this->name;
+    }
+
+    public static function create(string $name): static
+    {
+        return new static($name);
+    }
+}
+
+class LoudGreeter extends Greeter
+{
+}
+
+exact(1, 'b');
+exact(1);
+exact(1, 'b', 3);
+exact(b: 'b', a: 1);
+exact(1, c: 'c');
+optional(1);
+optional(1, c: 'c');
+optional();
+optional(1, 2, 'c', 4);
+variadic('%s %s', 1, 2, 3);
+variadic(values: 1);
+variadic();
+dynamic(1, 2, 3);
+exact(...[1, 'b']);
+
+typed('1', 1, 1.5, 0, [], 'strlen', fn () => null, 'a', null);
+typed('a', 'b', [], [], 'array', [], 'greeter', [], null);
+
+function greeters(): void
+{
+    $greeter = new Greeter('Laytan');
+    $greeter->greet('Hello', true, 'extra');
+    $greeter->greet();
+    Greeter::create();
+    new Greeter();
+    new LoudGreeter();
+    new LoudGreeter('a', 'b');
+}
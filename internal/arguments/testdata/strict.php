@@ -0,0 +1,8 @@
+<?php
+
+declare(strict_types=1);
+
+namespace Arguments\TestData;
+
+typed(1, 1, 1.5, 0, [], 'strlen', fn () => null, 'a', null);
+exact('1', 2);
@@ -0,0 +1,413 @@
+package codeactions
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/laytan/go-lsp-protocol/pkg/lsp/protocol"
+	"github.com/laytan/php-parser/pkg/ast"
+	"github.com/laytan/php-parser/pkg/token"
+	"github.com/laytan/phpls/internal/config"
+	"github.com/laytan/phpls/internal/symbol"
+	"github.com/laytan/phpls/internal/wrkspc"
+	"github.com/laytan/phpls/pkg/nodeident"
+	"github.com/laytan/phpls/pkg/phpdoxer"
+	"github.com/laytan/phpls/pkg/phprivacy"
+	"golang.org/x/exp/slices"
+)
+
+var docLinePrefixRgx = regexp.MustCompile(`^\s*\*?\s*$`)
+
+type AddTypeHintsOptions struct {
+	// Remove the doc tags that have the same type as the added hint and no description.
+	RemoveRedundantDocs bool
+}
+
+type AddTypeHintsProvider struct{}
+
+func NewAddTypeHints() *AddTypeHintsProvider {
+	return &AddTypeHintsProvider{}
+}
+
+func (a *AddTypeHintsProvider) Kind() protocol.CodeActionKind {
+	return protocol.QuickFix
+}
+
+func (a *AddTypeHintsProvider) Provide(params *Params) ([]protocol.CodeAction, error) {
+	edits := AddTypeHints(params, &AddTypeHintsOptions{
+		RemoveRedundantDocs: config.Current.CodeActions.TypeHints.RemoveRedundantDocs,
+	})
+	if len(edits) == 0 {
+		return nil, nil
+	}
+
+	return []protocol.CodeAction{{
+		Title: "Add type hints from PHPDoc",
+		Kind:  protocol.QuickFix,
+		Edit:  params.edit(edits...),
+	}}, nil
+}
+
+// AddTypeHints returns the edits that add native type hints, based on the
+// PHPDoc, to the parameters, return type or property at the start of the range.
+//
+// Only types that can be expressed natively in the configured PHP version
+// are added, types that are more specific in the doc are widened.
+func AddTypeHints(params *Params, opts *AddTypeHintsOptions) []protocol.TextEdit {
+	cursor, _ := params.selection()
+	path := enclosing(params.Root, cursor, cursor)
+
+	for i := len(path) - 1; i >= 0; i-- {
+		hinter := &typeHinter{params: params, opts: opts, node: path[i], parents: path[:i]}
+		switch typed := path[i].(type) {
+		case *ast.StmtPropertyList:
+			return hinter.property(typed)
+
+		case *ast.StmtFunction, *ast.StmtClassMethod, *ast.ExprClosure, *ast.ExprArrowFunction:
+			// Only when the cursor is on the signature, not inside the body.
+			if cursor >= functionBodyStart(typed) {
+				return nil
+			}
+
+			return hinter.function()
+		}
+	}
+
+	return nil
+}
+
+type typeHinter struct {
+	params *Params
+	opts   *AddTypeHintsOptions
+	node   ast.Vertex
+	// The nodes node is in, outermost first.
+	parents []ast.Vertex
+
+	docs  []*docComment
+	edits []protocol.TextEdit
+}
+
+type docComment struct {
+	token *token.Token
+	top   string
+	tags  []phpdoxer.Node
+	// The tags that are redundant now.
+	removed []phpdoxer.Node
+}
+
+func (t *typeHinter) function() []protocol.TextEdit {
+	t.parseDocs()
+	templates := t.templates()
+
+	fParams, closeParen, returnType := functionSignature(t.node)
+
+	// Parameter types can't be added to overriding methods, they would not
+	// accept everything the overridden method accepts anymore.
+	if t.overrides() {
+		fParams = nil
+	}
+
+	for _, param := range fParams {
+		param := param.(*ast.Parameter)
+		if param.Type != nil {
+			continue
+		}
+
+		tag := t.paramTag(nodeident.Get(param))
+		if tag == nil || tag.Type == nil || usesTemplate(tag.Type, templates) {
+			continue
+		}
+
+		hint, ok := typeHint(tag.Type, t.params.PHPVersion, hintParam)
+		if !ok {
+			continue
+		}
+
+		var offset int
+		switch {
+		case param.AmpersandTkn != nil:
+			offset = param.AmpersandTkn.Position.StartPos
+		case param.VariadicTkn != nil:
+			offset = param.VariadicTkn.Position.StartPos
+		default:
+			offset = param.Var.GetPosition().StartPos
+		}
+
+		t.edits = append(t.edits, t.params.replace(offset, offset, hint+" "))
+		t.redundant(tag, tag.Type, hint, tag.Description)
+	}
+
+	switch strings.ToLower(nodeident.Get(t.node)) {
+	case "__construct", "__destruct":
+		// Can't have a return type.
+	default:
+		if returnType != nil {
+			break
+		}
+
+		tag, ok := t.findTag(phpdoxer.KindReturn).(*phpdoxer.NodeReturn)
+		if !ok || usesTemplate(tag.Type, templates) {
+			break
+		}
+
+		if hint, ok := typeHint(tag.Type, t.params.PHPVersion, hintReturn); ok {
+			offset := closeParen.Position.EndPos
+			t.edits = append(t.edits, t.params.replace(offset, offset, ": "+hint))
+			t.redundant(tag, tag.Type, hint, tag.Description)
+		}
+	}
+
+	return t.finish()
+}
+
+func (t *typeHinter) property(list *ast.StmtPropertyList) []protocol.TextEdit {
+	// Typed properties are available since 7.4.
+	if list.Type != nil || t.params.PHPVersion == nil || !atLeast(t.params.PHPVersion, 7, 4) {
+		return nil
+	}
+
+	t.parseDocs()
+
+	tag, ok := t.findTag(phpdoxer.KindVar).(*phpdoxer.NodeVar)
+	if !ok || usesTemplate(tag.Type, t.templates()) {
+		return nil
+	}
+
+	hint, ok := typeHint(tag.Type, t.params.PHPVersion, hintProperty)
+	if !ok {
+		return nil
+	}
+
+	offset := list.Props[0].GetPosition().StartPos
+	t.edits = append(t.edits, t.params.replace(offset, offset, hint+" "))
+
+	// Untyped properties are implicitly null by default, typed properties are
+	// uninitialized, keep the old behaviour by explicitly defaulting to null.
+	if strings.HasPrefix(hint, "?") || hint == "mixed" || strings.Contains(hint, "null") {
+		for _, prop := range list.Props {
+			prop := prop.(*ast.StmtProperty)
+			if prop.Expr == nil {
+				end := prop.Var.GetPosition().EndPos
+				t.edits = append(t.edits, t.params.replace(end, end, " = null"))
+			}
+		}
+	}
+
+	t.redundant(tag, tag.Type, hint, tag.Description)
+
+	return t.finish()
+}
+
+func (t *typeHinter) parseDocs() {
+	for _, tkn := range symbol.NodeCommentTokens(t.node) {
+		if tkn.ID != token.T_DOC_COMMENT {
+			continue
+		}
+
+		doc, err := phpdoxer.ParseFullDoc(string(tkn.Value))
+		if err != nil {
+			log.Println(fmt.Errorf("[codeactions.typeHinter.parseDocs]: %w", err))
+			continue
+		}
+
+		t.docs = append(t.docs, &docComment{token: tkn, top: doc.Top, tags: doc.Nodes})
+	}
+}
+
+// templates returns the names of the templates declared on the node and the
+// function-likes and class-likes it is in.
+func (t *typeHinter) templates() []string {
+	var names []string
+	for _, node := range append([]ast.Vertex{t.node}, t.parents...) {
+		if !isFunctionLike(node) && !isClassLike(node) {
+			continue
+		}
+
+		for _, doc := range symbol.NewDoxed(node).Docs() {
+			if template, ok := doc.(*phpdoxer.NodeTemplate); ok {
+				names = append(names, template.Name)
+			}
+		}
+	}
+
+	return names
+}
+
+// overrides returns whether the node is a method that overrides or implements
+// a method of a parent class, interface or used trait.
+func (t *typeHinter) overrides() bool {
+	if _, ok := t.node.(*ast.StmtClassMethod); !ok || len(t.parents) == 0 {
+		return false
+	}
+
+	clsNode := t.parents[len(t.parents)-1]
+	if !isClassLike(clsNode) {
+		return false
+	}
+
+	name := nodeident.Get(t.node)
+	cls := symbol.NewClassLike(wrkspc.NewRooter(t.params.Path, t.params.Root), clsNode)
+	iter := cls.InheritsIter()
+	for inhCls, done, err := iter(); !done; inhCls, done, err = iter() {
+		// The method might be declared in the class that can't be found.
+		if err != nil {
+			return true
+		}
+
+		if inhCls.FindMethod(func(m *symbol.Method) bool {
+			return strings.EqualFold(m.Name(), name) && m.Privacy() != phprivacy.PrivacyPrivate
+		}) != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (t *typeHinter) findTag(kind phpdoxer.NodeKind) phpdoxer.Node {
+	for _, doc := range t.docs {
+		for _, tag := range doc.tags {
+			if tag.Kind() == kind {
+				return tag
+			}
+		}
+	}
+
+	return nil
+}
+
+func (t *typeHinter) paramTag(name string) *phpdoxer.NodeParam {
+	for _, doc := range t.docs {
+		for _, tag := range doc.tags {
+			if param, ok := tag.(*phpdoxer.NodeParam); ok && strings.TrimPrefix(param.Name, "...") == name {
+				return param
+			}
+		}
+	}
+
+	return nil
+}
+
+// redundant marks the tag as redundant if it does not add any information
+// over the added hint.
+func (t *typeHinter) redundant(tag phpdoxer.Node, typ phpdoxer.Type, hint string, description string) {
+	if !t.opts.RemoveRedundantDocs || description != "" {
+		return
+	}
+
+	hintType, err := phpdoxer.ParseType(hint)
+	if err != nil || hintType.String() != typ.String() {
+		return
+	}
+
+	for _, doc := range t.docs {
+		for _, docTag := range doc.tags {
+			if docTag == tag {
+				doc.removed = append(doc.removed, tag)
+				return
+			}
+		}
+	}
+}
+
+func (t *typeHinter) finish() []protocol.TextEdit {
+	for _, doc := range t.docs {
+		if len(doc.removed) == 0 {
+			continue
+		}
+
+		pos := doc.token.Position
+		if doc.top == "" && len(doc.removed) == len(doc.tags) {
+			start, end := t.params.lineRange(pos.StartPos, pos.EndPos)
+			t.edits = append(t.edits, t.params.replace(start, end, ""))
+			continue
+		}
+
+		for _, tag := range doc.removed {
+			start, end := tag.Range()
+			start, end = t.tagRange(pos.StartPos+start, pos.StartPos+end)
+			t.edits = append(t.edits, t.params.replace(start, end, ""))
+		}
+	}
+
+	return t.edits
+}
+
+// tagRange returns the range to remove for a tag, the tag range from phpdoxer
+// includes everything up to the next tag, so it is narrowed to the content,
+// and expanded to the whole lines if the tag is on lines of its own.
+func (t *typeHinter) tagRange(start, end int) (int, int) {
+	content := strings.TrimRight(t.params.Content[start:end], " \t\r\n")
+	content = strings.TrimRight(strings.TrimSuffix(content, "*/"), " \t\r\n*")
+	end = start + len(content)
+
+	lineStart := strings.LastIndexByte(t.params.Content[:start], '\n') + 1
+	if !docLinePrefixRgx.MatchString(t.params.Content[lineStart:start]) {
+		return start, end
+	}
+
+	lineEnd := strings.IndexByte(t.params.Content[end:], '\n')
+	if lineEnd == -1 || strings.TrimSpace(t.params.Content[end:end+lineEnd]) != "" {
+		return start, end
+	}
+
+	return lineStart, end + lineEnd + 1
+}
+
+// usesTemplate returns whether the hint of the type would refer to one of the
+// templates, they are not actual classes. The generics of classes are not part
+// of the hint, so they are not checked.
+func usesTemplate(typ phpdoxer.Type, templates []string) (uses bool) {
+	phpdoxer.Walk(typ, func(t phpdoxer.Type) bool {
+		switch typed := t.(type) {
+		case *phpdoxer.TypeUnion, *phpdoxer.TypeIntersection, *phpdoxer.TypePrecedence:
+			return true
+		case *phpdoxer.TypeClassLike:
+			if !typed.FullyQualified && slices.Contains(templates, typed.Name) {
+				uses = true
+			}
+		}
+
+		return false
+	})
+
+	return uses
+}
+
+// functionSignature returns the parameters, the token after which a return
+// type is added and the return type of the function-like node.
+func functionSignature(node ast.Vertex) (params []ast.Vertex, closeParen *token.Token, returnType ast.Vertex) {
+	switch typed := node.(type) {
+	case *ast.StmtFunction:
+		return typed.Params, typed.CloseParenthesisTkn, typed.ReturnType
+	case *ast.StmtClassMethod:
+		return typed.Params, typed.CloseParenthesisTkn, typed.ReturnType
+	case *ast.ExprClosure:
+		if typed.UseCloseParenthesisTkn != nil {
+			return typed.Params, typed.UseCloseParenthesisTkn, typed.ReturnType
+		}
+
+		return typed.Params, typed.CloseParenthesisTkn, typed.ReturnType
+	case *ast.ExprArrowFunction:
+		return typed.Params, typed.CloseParenthesisTkn, typed.ReturnType
+	default:
+		return nil, nil, nil
+	}
+}
+
+func functionBodyStart(node ast.Vertex) int {
+	switch typed := node.(type) {
+	case *ast.StmtFunction:
+		return typed.OpenCurlyBracketTkn.Position.StartPos
+	case *ast.StmtClassMethod:
+		return typed.Stmt.GetPosition().StartPos
+	case *ast.ExprClosure:
+		return typed.OpenCurlyBracketTkn.Position.StartPos
+	case *ast.ExprArrowFunction:
+		return typed.DoubleArrowTkn.Position.StartPos
+	default:
+		return node.GetPosition().EndPos
+	}
+}
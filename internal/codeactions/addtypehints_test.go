@@ -0,0 +1,294 @@
+package codeactions_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/laytan/phpls/internal/codeactions"
+	"github.com/laytan/phpls/internal/config"
+	"github.com/laytan/phpls/internal/index"
+	"github.com/laytan/phpls/internal/project"
+	"github.com/laytan/phpls/internal/wrkspc"
+	"github.com/laytan/phpls/pkg/pathutils"
+	"github.com/laytan/phpls/pkg/phpversion"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddTypeHints(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name    string
+		input   string
+		cursor  string
+		version *phpversion.PHPVersion
+		remove  bool
+		// Empty if no hints can be added.
+		expect string
+	}{
+		{
+			name: "params and return",
+			input: `<?php
+
+/**
+ * @param int[] $a
+ * @param ?Foo $b
+ * @param int ...$c
+ * @return ?int
+ */
+function test($a, $b, &...$c) {
+}
+`,
+			cursor: "function test",
+			expect: `<?php
+
+/**
+ * @param int[] $a
+ * @param ?Foo $b
+ * @param int ...$c
+ * @return ?int
+ */
+function test(array $a, ?Foo $b, int &...$c): ?int {
+}
+`,
+		},
+		{
+			name: "unions are gated",
+			input: `<?php
+
+class Foo
+{
+    /**
+     * @param int|string $a
+     * @return mixed
+     */
+    public function test($a)
+    {
+    }
+}
+`,
+			cursor:  "public function",
+			version: &phpversion.PHPVersion{Major: 7, Minor: 4},
+		},
+		{
+			name: "unions on php 8",
+			input: `<?php
+
+class Foo
+{
+    /**
+     * @param int|string $a
+     * @return mixed
+     */
+    public function test($a)
+    {
+    }
+}
+`,
+			cursor: "public function",
+			expect: `<?php
+
+class Foo
+{
+    /**
+     * @param int|string $a
+     * @return mixed
+     */
+    public function test(int|string $a): mixed
+    {
+    }
+}
+`,
+		},
+		{
+			name: "remove redundant tags",
+			input: `<?php
+
+class Foo
+{
+    /**
+     * Does a thing.
+     *
+     * @param int $a
+     * @param string $b The description.
+     * @param int[] $c
+     * @return void
+     */
+    public function test($a, $b, $c)
+    {
+    }
+
+    /** @var bool */
+    private $bar;
+}
+`,
+			cursor: "test(",
+			remove: true,
+			expect: `<?php
+
+class Foo
+{
+    /**
+     * Does a thing.
+     *
+     * @param string $b The description.
+     * @param int[] $c
+     */
+    public function test(int $a, string $b, array $c): void
+    {
+    }
+
+    /** @var bool */
+    private $bar;
+}
+`,
+		},
+		{
+			name: "property",
+			input: `<?php
+
+class Foo
+{
+    /** @var ?Foo */
+    private $bar;
+}
+`,
+			cursor: "$bar",
+			remove: true,
+			expect: `<?php
+
+class Foo
+{
+    private ?Foo $bar = null;
+}
+`,
+		},
+		{
+			name: "property before 7.4",
+			input: `<?php
+
+class Foo
+{
+    /** @var int */
+    private $bar;
+}
+`,
+			cursor:  "$bar",
+			version: &phpversion.PHPVersion{Major: 7, Minor: 3},
+		},
+		{
+			name: "templates",
+			input: `<?php
+
+/**
+ * @template TValue
+ */
+class Foo
+{
+    /**
+     * @template TKey
+     * @param TKey $key
+     * @param TValue $value
+     * @param int $count
+     * @return TValue|null
+     */
+    public function test($key, $value, $count)
+    {
+    }
+}
+`,
+			cursor: "public function test",
+			expect: `<?php
+
+/**
+ * @template TValue
+ */
+class Foo
+{
+    /**
+     * @template TKey
+     * @param TKey $key
+     * @param TValue $value
+     * @param int $count
+     * @return TValue|null
+     */
+    public function test($key, $value, int $count)
+    {
+    }
+}
+`,
+		},
+		{
+			name: "inside the body",
+			input: `<?php
+
+/** @param int $a */
+function test($a) {
+    echo $a;
+}
+`,
+			cursor: "echo",
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			p := params(t, scenario.input)
+			p.Range = rangeOf(t, scenario.input, scenario.cursor)
+			if scenario.version != nil {
+				p.PHPVersion = scenario.version
+			}
+
+			edits := codeactions.AddTypeHints(p, &codeactions.AddTypeHintsOptions{
+				RemoveRedundantDocs: scenario.remove,
+			})
+			if scenario.expect == "" {
+				require.Empty(t, edits)
+				return
+			}
+
+			require.Equal(t, scenario.expect, applyEdits(scenario.input, edits))
+		})
+	}
+}
+
+func TestAddTypeHintsOverride(t *testing.T) {
+	root := filepath.Join(pathutils.Root(), "internal", "codeactions", "testdata", "override")
+	require.NoError(t, setup(root, phpversion.EightOne()))
+
+	path := filepath.Join(root, "override.php")
+	content := wrkspc.Current.FContentOf(path)
+
+	hint := func(cursor string) string {
+		p := params(t, content)
+		p.Path = path
+		p.Range = rangeOf(t, content, cursor)
+
+		return applyEdits(content, codeactions.AddTypeHints(p, &codeactions.AddTypeHintsOptions{}))
+	}
+
+	// Only the return type, the interface method accepts any $factor.
+	require.Contains(t, hint("public function scale($factor)\n    {"), "public function scale($factor): static\n")
+	require.Contains(t, hint("public function resize"), "public function resize(float $size)\n")
+}
+
+func setup(root string, phpv *phpversion.PHPVersion) error {
+	config.Current = config.Default()
+	index.Current = index.New(phpv)
+	wrkspc.Current = wrkspc.New(
+		phpv,
+		root,
+		filepath.Join(pathutils.Root(), "third_party", "phpstorm-stubs"),
+	)
+
+	p := project.New()
+	if err := p.ParseWithoutProgress(); err != nil {
+		return fmt.Errorf("[codeactions_test.setup]: %w", err)
+	}
+
+	return nil
+}
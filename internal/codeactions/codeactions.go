@@ -0,0 +1,112 @@
+package codeactions
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/laytan/go-lsp-protocol/pkg/lsp/protocol"
+	"github.com/laytan/php-parser/pkg/ast"
+	"github.com/laytan/phpls/pkg/phpversion"
+	"github.com/laytan/phpls/pkg/position"
+	"golang.org/x/exp/slices"
+)
+
+// Params is the information about the request given to each provider.
+type Params struct {
+	Path    string
+	Content string
+	Root    *ast.Root
+	// The range (selection or cursor) the code actions are requested for.
+	Range protocol.Range
+	// The diagnostics the client has at the range.
+	Diagnostics []protocol.Diagnostic
+	// The PHP version the code actions should generate code for.
+	PHPVersion *phpversion.PHPVersion
+	// Used to fix phpcs violations, nil if phpcbf is not available.
+	Phpcbf SniffFixer
+}
+
+type Provider interface {
+	Kind() protocol.CodeActionKind
+	Provide(params *Params) ([]protocol.CodeAction, error)
+}
+
+var providers = []Provider{
+	NewOrganizeImports(), // source.organizeImports
+	NewExtract(),         // refactor.extract
+	NewInline(),          // refactor.inline
+	NewGenerate(),        // source.generate
+	NewAddTypeHints(),    // quickfix
+	NewPhpcs(),           // quickfix
+	NewNamespace(),       // quickfix
+}
+
+// Kinds returns all the code action kinds that can be provided.
+func Kinds() []protocol.CodeActionKind {
+	var kinds []protocol.CodeActionKind
+	for _, provider := range providers {
+		if !slices.Contains(kinds, provider.Kind()) {
+			kinds = append(kinds, provider.Kind())
+		}
+	}
+
+	return kinds
+}
+
+// Provide returns the code actions of all the providers matching the only
+// filter, if only is empty, all providers are used.
+func Provide(params *Params, only []protocol.CodeActionKind) ([]protocol.CodeAction, error) {
+	var actions []protocol.CodeAction
+	var errs []error
+	for _, provider := range providers {
+		if !matches(only, provider.Kind()) {
+			continue
+		}
+
+		res, err := provider.Provide(params)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("providing %s code actions: %w", provider.Kind(), err))
+			continue
+		}
+
+		actions = append(actions, res...)
+	}
+
+	return actions, errors.Join(errs...)
+}
+
+// matches returns whether the kind is requested, a request for "refactor"
+// matches "refactor.extract" for example.
+func matches(only []protocol.CodeActionKind, kind protocol.CodeActionKind) bool {
+	if len(only) == 0 {
+		return true
+	}
+
+	for _, o := range only {
+		if kind == o || strings.HasPrefix(string(kind), string(o)+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *Params) edit(edits ...protocol.TextEdit) *protocol.WorkspaceEdit {
+	return &protocol.WorkspaceEdit{
+		Changes: map[protocol.DocumentURI][]protocol.TextEdit{
+			protocol.DocumentURI("file://" + p.Path): edits,
+		},
+	}
+}
+
+// replace creates a text edit replacing the content between the byte offsets.
+func (p *Params) replace(start, end int, text string) protocol.TextEdit {
+	return protocol.TextEdit{
+		Range: protocol.Range{
+			Start: position.ToLSPPosition(p.Content, start),
+			End:   position.ToLSPPosition(p.Content, end),
+		},
+		NewText: text,
+	}
+}
@@ -0,0 +1,65 @@
+package codeactions_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/laytan/go-lsp-protocol/pkg/lsp/protocol"
+	"github.com/laytan/phpls/internal/codeactions"
+	"github.com/laytan/phpls/pkg/parsing"
+	"github.com/laytan/phpls/pkg/phpversion"
+	"github.com/laytan/phpls/pkg/position"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/exp/slices"
+)
+
+func params(t *testing.T, content string) *codeactions.Params {
+	t.Helper()
+
+	root, err := parsing.New(phpversion.EightOne()).Parse([]byte(content))
+	require.NoError(t, err)
+
+	return &codeactions.Params{
+		Path:       "/test.php",
+		Content:    content,
+		Root:       root,
+		PHPVersion: phpversion.EightOne(),
+	}
+}
+
+// rangeOf returns the range of the first occurrence of needle in content.
+func rangeOf(t *testing.T, content string, needle string) protocol.Range {
+	t.Helper()
+
+	start := strings.Index(content, needle)
+	require.NotEqual(t, -1, start, "%q is not in the content", needle)
+
+	return protocol.Range{
+		Start: position.ToLSPPosition(content, start),
+		End:   position.ToLSPPosition(content, start+len(needle)),
+	}
+}
+
+// applyEdits applies the (non-overlapping) edits to the content.
+func applyEdits(content string, edits []protocol.TextEdit) string {
+	offset := func(pos protocol.Position) int {
+		lineStart := 0
+		for i := uint32(0); i < pos.Line; i++ {
+			lineStart += strings.IndexByte(content[lineStart:], '\n') + 1
+		}
+
+		return lineStart + int(pos.Character)
+	}
+
+	edits = slices.Clone(edits)
+	slices.SortFunc(edits, func(a, b protocol.TextEdit) bool {
+		return offset(a.Range.Start) > offset(b.Range.Start)
+	})
+
+	for _, edit := range edits {
+		start, end := offset(edit.Range.Start), offset(edit.Range.End)
+		content = content[:start] + edit.NewText + content[end:]
+	}
+
+	return content
+}
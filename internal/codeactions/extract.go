@@ -0,0 +1,596 @@
+package codeactions
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/laytan/go-lsp-protocol/pkg/lsp/protocol"
+	"github.com/laytan/php-parser/pkg/ast"
+	"github.com/laytan/php-parser/pkg/visitor"
+	"github.com/laytan/php-parser/pkg/visitor/traverser"
+	"github.com/laytan/phpls/internal/expr"
+	"github.com/laytan/phpls/internal/symbol"
+	"github.com/laytan/phpls/internal/wrkspc"
+	"github.com/laytan/phpls/pkg/nodeident"
+	"github.com/laytan/phpls/pkg/nodevar"
+	"github.com/laytan/phpls/pkg/phpdoxer"
+	"golang.org/x/exp/slices"
+)
+
+type ExtractProvider struct{}
+
+func NewExtract() *ExtractProvider {
+	return &ExtractProvider{}
+}
+
+func (e *ExtractProvider) Kind() protocol.CodeActionKind {
+	return protocol.RefactorExtract
+}
+
+func (e *ExtractProvider) Provide(params *Params) ([]protocol.CodeAction, error) {
+	var actions []protocol.CodeAction
+	if edits := ExtractVariable(params); len(edits) > 0 {
+		actions = append(actions, protocol.CodeAction{
+			Title: "Extract to variable",
+			Kind:  protocol.RefactorExtract,
+			Edit:  params.edit(edits...),
+		})
+	}
+
+	if edits := ExtractMethod(params); len(edits) > 0 {
+		actions = append(actions, protocol.CodeAction{
+			Title: "Extract to method",
+			Kind:  protocol.RefactorExtract,
+			Edit:  params.edit(edits...),
+		})
+	}
+
+	return actions, nil
+}
+
+// ExtractVariable returns the edits that move the selected expression into a
+// new variable, assigned right before the statement it is part of.
+func ExtractVariable(params *Params) []protocol.TextEdit {
+	start, end := params.selection()
+	if start == end {
+		return nil
+	}
+
+	path := enclosing(params.Root, start, end)
+	exprI := slices.IndexFunc(path, func(node ast.Vertex) bool {
+		pos := node.GetPosition()
+		return pos.StartPos == start && pos.EndPos == end && isExpr(node)
+	})
+	if exprI == -1 {
+		return nil
+	}
+
+	subject := path[exprI]
+	if _, ok := subject.(*ast.ExprVariable); ok {
+		return nil
+	}
+
+	scopeI := variableScope(path[:exprI])
+	if scopeI == -1 {
+		return nil
+	}
+
+	stmtI := -1
+	for i := exprI - 1; i >= scopeI && stmtI == -1; i-- {
+		if stmts, ok := stmtList(path[i]); ok && slices.Contains(stmts, path[i+1]) {
+			stmtI = i + 1
+		}
+	}
+
+	if stmtI == -1 || !canExtractFrom(path[stmtI:exprI+1]) {
+		return nil
+	}
+
+	taken := names(variables(scopeNodes(path[scopeI])...))
+	name := uniqueName(suggestName(subject), taken)
+	stmtStart := path[stmtI].GetPosition().StartPos
+
+	return []protocol.TextEdit{
+		params.replace(
+			stmtStart,
+			stmtStart,
+			fmt.Sprintf(
+				"%s = %s;%s%s",
+				name,
+				params.text(subject),
+				params.newline(),
+				params.indentAt(stmtStart),
+			),
+		),
+		params.replace(start, end, name),
+	}
+}
+
+// canExtractFrom checks if the expression (last node of the path), can be
+// evaluated before the statement (first node of the path) without changing
+// the meaning of the code.
+func canExtractFrom(path []ast.Vertex) bool {
+	switch stmt := path[0].(type) {
+	case *ast.StmtWhile, *ast.StmtDo, *ast.StmtFor, *ast.StmtStatic, *ast.StmtGlobal,
+		*ast.StmtConstList, *ast.StmtUnset, *ast.StmtFunction:
+		return false
+	case *ast.StmtForeach:
+		if path[1] != stmt.Expr {
+			return false
+		}
+	}
+
+	for i, node := range path[1 : len(path)-1] {
+		switch node.(type) {
+		case *ast.ExprArrowFunction, *ast.ExprClosure, *ast.StmtElseIf, *ast.Parameter,
+			*ast.ExprIsset, *ast.ExprEmpty, *ast.ExprList:
+			return false
+		}
+
+		// Only the first operand is always evaluated, hoisting anything else
+		// evaluates it when it would have been short-circuited.
+		if first, ok := conditionalFirst(node); ok && path[i+2] != first {
+			return false
+		}
+	}
+
+	subject := path[len(path)-1]
+	parent := path[len(path)-2]
+	switch parent.(type) {
+	case *ast.ExprPreInc, *ast.ExprPreDec, *ast.ExprPostInc, *ast.ExprPostDec:
+		return false
+	}
+
+	// The subject is being assigned to.
+	if nodevar.IsAssignment(parent.GetType()) &&
+		parent.GetPosition().StartPos == subject.GetPosition().StartPos {
+		return false
+	}
+
+	return true
+}
+
+// conditionalFirst returns the operand that is always evaluated, if the node
+// evaluates its other operands conditionally.
+func conditionalFirst(node ast.Vertex) (ast.Vertex, bool) {
+	switch typed := node.(type) {
+	case *ast.ExprBinaryBooleanAnd:
+		return typed.Left, true
+	case *ast.ExprBinaryBooleanOr:
+		return typed.Left, true
+	case *ast.ExprBinaryLogicalAnd:
+		return typed.Left, true
+	case *ast.ExprBinaryLogicalOr:
+		return typed.Left, true
+	case *ast.ExprBinaryCoalesce:
+		return typed.Left, true
+	case *ast.ExprTernary:
+		return typed.Cond, true
+	case *ast.ExprMatch:
+		return typed.Expr, true
+	default:
+		return nil, false
+	}
+}
+
+// suggestName returns a variable name based on what the expression is.
+func suggestName(subject ast.Vertex) string {
+	var name string
+	switch typed := subject.(type) {
+	case *ast.ExprMethodCall:
+		name = identifier(typed.Method)
+	case *ast.ExprNullsafeMethodCall:
+		name = identifier(typed.Method)
+	case *ast.ExprStaticCall:
+		name = identifier(typed.Call)
+	case *ast.ExprPropertyFetch:
+		name = identifier(typed.Prop)
+	case *ast.ExprNullsafePropertyFetch:
+		name = identifier(typed.Prop)
+	case *ast.ExprFunctionCall:
+		name = lastNamePart(typed.Function)
+	case *ast.ExprNew:
+		name = lastNamePart(typed.Class)
+	}
+
+	if after, ok := strings.CutPrefix(name, "get"); ok && after != "" {
+		if r, _ := utf8.DecodeRuneInString(after); unicode.IsUpper(r) {
+			name = after
+		}
+	}
+
+	if !identifierRgx.MatchString(name) {
+		return "$value"
+	}
+
+	r, size := utf8.DecodeRuneInString(name)
+	return "$" + string(unicode.ToLower(r)) + name[size:]
+}
+
+func identifier(node ast.Vertex) string {
+	if ident, ok := node.(*ast.Identifier); ok {
+		return string(ident.Value)
+	}
+
+	return ""
+}
+
+func lastNamePart(node ast.Vertex) string {
+	switch node.(type) {
+	case *ast.Name, *ast.NameFullyQualified, *ast.NameRelative:
+		parts := strings.Split(nodeident.Get(node), `\`)
+		return parts[len(parts)-1]
+	default:
+		return ""
+	}
+}
+
+// ExtractMethod returns the edits that move the selected statements into a
+// new private method on the class, the variables used in the statements
+// become parameters, and the variables assigned in the statements, that are
+// used afterwards, are returned from the method.
+func ExtractMethod(params *Params) []protocol.TextEdit {
+	start, end := params.selection()
+	if start == end {
+		return nil
+	}
+
+	path := enclosing(params.Root, start, end)
+	methodI := -1
+	for i := len(path) - 1; i >= 0 && methodI == -1; i-- {
+		if isFunctionLike(path[i]) {
+			methodI = i
+		}
+	}
+	if methodI < 1 {
+		return nil
+	}
+
+	method, ok := path[methodI].(*ast.StmtClassMethod)
+	if !ok || !isClassLike(path[methodI-1]) {
+		return nil
+	}
+
+	var selected []ast.Vertex
+	for i := len(path) - 1; i > methodI && selected == nil; i-- {
+		if stmts, ok := stmtList(path[i]); ok {
+			selected = selectedStmts(stmts, start, end)
+		}
+	}
+
+	if selected == nil || !canExtractStmts(selected) {
+		return nil
+	}
+
+	extraction := &methodExtraction{
+		params: params,
+		method: method,
+		class:  path[methodI-1],
+		vars:   variables(scopeNodes(method)...),
+	}
+
+	return extraction.edits(start, end)
+}
+
+// selectedStmts returns the statements that are selected, or nil if the
+// selection is not exactly a list of statements.
+func selectedStmts(stmts []ast.Vertex, start, end int) []ast.Vertex {
+	var selected []ast.Vertex
+	for _, stmt := range stmts {
+		pos := stmt.GetPosition()
+		switch {
+		case pos.StartPos >= start && pos.EndPos <= end:
+			selected = append(selected, stmt)
+		case pos.StartPos < end && pos.EndPos > start:
+			return nil
+		}
+	}
+
+	if len(selected) == 0 ||
+		selected[0].GetPosition().StartPos != start ||
+		selected[len(selected)-1].GetPosition().EndPos != end {
+		return nil
+	}
+
+	return selected
+}
+
+// canExtractStmts checks that the statements don't contain control flow that
+// can't be moved into another method.
+func canExtractStmts(stmts []ast.Vertex) bool {
+	v := &controlFlowVisitor{}
+	t := traverser.NewTraverser(v)
+	for _, stmt := range stmts {
+		stmt.Accept(t)
+	}
+
+	return !v.escapes
+}
+
+type controlFlowVisitor struct {
+	visitor.Null
+
+	loops   int
+	escapes bool
+}
+
+func (v *controlFlowVisitor) EnterNode(node ast.Vertex) bool {
+	switch node.(type) {
+	case *ast.StmtFunction, *ast.StmtClass, *ast.ExprClosure, *ast.ExprArrowFunction:
+		return false
+	case *ast.StmtReturn, *ast.ExprYield, *ast.ExprYieldFrom, *ast.StmtGoto, *ast.StmtLabel,
+		*ast.StmtGlobal, *ast.StmtStatic:
+		v.escapes = true
+	case *ast.StmtBreak, *ast.StmtContinue:
+		if v.loops == 0 {
+			v.escapes = true
+		}
+	case *ast.StmtFor, *ast.StmtForeach, *ast.StmtWhile, *ast.StmtDo, *ast.StmtSwitch:
+		v.loops++
+	}
+
+	return !v.escapes
+}
+
+func (v *controlFlowVisitor) LeaveNode(node ast.Vertex) {
+	switch node.(type) {
+	case *ast.StmtFor, *ast.StmtForeach, *ast.StmtWhile, *ast.StmtDo, *ast.StmtSwitch:
+		v.loops--
+	}
+}
+
+type methodExtraction struct {
+	params *Params
+	method *ast.StmtClassMethod
+	class  ast.Vertex
+	// All variable occurrences in the method.
+	vars []*varOccurrence
+}
+
+func (m *methodExtraction) edits(start, end int) []protocol.TextEdit {
+	var before, inside, after []*varOccurrence
+	for _, occurrence := range m.vars {
+		if occurrence.name == "$this" {
+			continue
+		}
+
+		switch pos := occurrence.node.Position.StartPos; {
+		case pos < start:
+			before = append(before, occurrence)
+		case pos >= end:
+			after = append(after, occurrence)
+		default:
+			inside = append(inside, occurrence)
+		}
+	}
+
+	beforeNames, afterNames := names(before), names(after)
+	var args, returns []string
+	for _, occurrence := range inside {
+		if occurrence.reads() && slices.Contains(beforeNames, occurrence.name) &&
+			!slices.Contains(args, occurrence.name) {
+			args = append(args, occurrence.name)
+		}
+
+		if occurrence.writes() && slices.Contains(afterNames, occurrence.name) &&
+			!slices.Contains(returns, occurrence.name) {
+			returns = append(returns, occurrence.name)
+		}
+	}
+
+	name := uniqueName("extracted", m.methodNames())
+	newline := m.params.newline()
+
+	return []protocol.TextEdit{
+		m.params.replace(start, end, m.call(name, args, returns)),
+		m.params.replace(
+			m.method.Position.EndPos,
+			m.method.Position.EndPos,
+			newline+newline+m.declaration(name, args, returns, start, end),
+		),
+	}
+}
+
+func (m *methodExtraction) call(name string, args []string, returns []string) string {
+	receiver := "$this->"
+	if m.isStatic() {
+		receiver = "self::"
+	}
+
+	call := fmt.Sprintf("%s%s(%s);", receiver, name, strings.Join(args, ", "))
+	switch {
+	case len(returns) == 1:
+		return returns[0] + " = " + call
+	case len(returns) > 1 && atLeast(m.params.PHPVersion, 7, 1):
+		return "[" + strings.Join(returns, ", ") + "] = " + call
+	case len(returns) > 1:
+		return "list(" + strings.Join(returns, ", ") + ") = " + call
+	default:
+		return call
+	}
+}
+
+// declaration renders the new method, with the code between start and end as
+// its body.
+func (m *methodExtraction) declaration(
+	name string,
+	args []string,
+	returns []string,
+	start, end int,
+) string {
+	newline := m.params.newline()
+	methodIndent := m.params.indentAt(m.method.Position.StartPos)
+	bodyIndent := methodIndent + m.indentUnit(methodIndent)
+	body := reindent(m.params.Content[start:end], m.params.indentAt(start), bodyIndent, newline)
+
+	modifiers := "private "
+	if m.isStatic() {
+		modifiers += "static "
+	}
+
+	params := make([]string, 0, len(args))
+	for _, arg := range args {
+		if hint := m.varType(arg, hintParam); hint != "" {
+			arg = hint + " " + arg
+		}
+
+		params = append(params, arg)
+	}
+
+	var returnHint string
+	switch len(returns) {
+	case 0:
+		returnHint, _ = typeHint(&phpdoxer.TypeVoid{}, m.params.PHPVersion, hintReturn)
+	case 1:
+		returnHint = m.varType(returns[0], hintReturn)
+	default:
+		returnHint = "array"
+	}
+
+	if returnHint != "" {
+		returnHint = ": " + returnHint
+	}
+
+	var b strings.Builder
+	b.WriteString(methodIndent + modifiers + "function " + name)
+	b.WriteString("(" + strings.Join(params, ", ") + ")" + returnHint + newline)
+	b.WriteString(methodIndent + "{" + newline)
+	b.WriteString(body + newline)
+
+	switch len(returns) {
+	case 0:
+	case 1:
+		b.WriteString(newline + bodyIndent + "return " + returns[0] + ";" + newline)
+	default:
+		b.WriteString(newline + bodyIndent + "return [" + strings.Join(returns, ", ") + "];" + newline)
+	}
+
+	b.WriteString(methodIndent + "}")
+	return b.String()
+}
+
+// indentUnit returns the indentation of one level, based on the statements in
+// the method, defaulting to 4 spaces.
+func (m *methodExtraction) indentUnit(methodIndent string) string {
+	if list, ok := m.method.Stmt.(*ast.StmtStmtList); ok && len(list.Stmts) > 0 {
+		stmtIndent := m.params.indentAt(list.Stmts[0].GetPosition().StartPos)
+		if unit := strings.TrimPrefix(stmtIndent, methodIndent); unit != "" && unit != stmtIndent {
+			return unit
+		}
+	}
+
+	return "    "
+}
+
+func (m *methodExtraction) isStatic() bool {
+	for _, modifier := range m.method.Modifiers {
+		if strings.EqualFold(identifier(modifier), "static") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m *methodExtraction) methodNames() []string {
+	stmts, _ := classStmts(m.class)
+
+	var res []string
+	for _, stmt := range stmts {
+		if method, ok := stmt.(*ast.StmtClassMethod); ok {
+			res = append(res, nodeident.Get(method))
+		}
+	}
+
+	return res
+}
+
+// varType infers the type of the variable using the parameter type, the @var
+// type or the expression it is assigned, an empty string is returned if no
+// type could be inferred.
+func (m *methodExtraction) varType(name string, pos hintPosition) string {
+	for _, param := range m.method.Params {
+		param := param.(*ast.Parameter)
+		if nodeident.Get(param) != name {
+			continue
+		}
+
+		switch {
+		case param.VariadicTkn != nil:
+			return "array"
+		case param.Type != nil:
+			return m.params.text(param.Type)
+		}
+
+		doc := symbol.NewDoxed(m.method).FindDoc(symbol.FilterParamName(name))
+		if doc == nil || doc.(*phpdoxer.NodeParam).Type == nil {
+			return ""
+		}
+
+		hint, _ := typeHint(doc.(*phpdoxer.NodeParam).Type, m.params.PHPVersion, pos)
+		return hint
+	}
+
+	for _, occurrence := range m.vars {
+		assignment, ok := occurrence.parent.(*ast.ExprAssign)
+		if occurrence.name != name || !ok || assignment.Var != occurrence.node {
+			continue
+		}
+
+		rooter := wrkspc.NewRooter(m.params.Path, m.params.Root)
+		if typ, err := symbol.NewVariable(rooter, occurrence.node).Type(); err == nil {
+			hint, _ := typeHint(typ, m.params.PHPVersion, pos)
+			return hint
+		}
+
+		return m.exprType(assignment.Expr)
+	}
+
+	return ""
+}
+
+// exprType infers the type of the expression.
+func (m *methodExtraction) exprType(node ast.Vertex) string {
+	switch typed := node.(type) {
+	case *ast.ScalarString, *ast.ScalarEncapsed, *ast.ScalarHeredoc, *ast.ExprBinaryConcat,
+		*ast.ExprCastString:
+		return "string"
+	case *ast.ScalarLnumber, *ast.ExprCastInt:
+		return "int"
+	case *ast.ScalarDnumber, *ast.ExprCastDouble:
+		return "float"
+	case *ast.ExprArray, *ast.ExprCastArray:
+		return "array"
+	case *ast.ExprCastBool, *ast.ExprBooleanNot, *ast.ExprInstanceOf, *ast.ExprIsset,
+		*ast.ExprEmpty, *ast.ExprBinaryEqual, *ast.ExprBinaryNotEqual, *ast.ExprBinaryIdentical,
+		*ast.ExprBinaryNotIdentical, *ast.ExprBinaryGreater, *ast.ExprBinaryGreaterOrEqual,
+		*ast.ExprBinarySmaller, *ast.ExprBinarySmallerOrEqual, *ast.ExprBinaryBooleanAnd,
+		*ast.ExprBinaryBooleanOr, *ast.ExprBinaryLogicalAnd, *ast.ExprBinaryLogicalOr,
+		*ast.ExprBinaryLogicalXor:
+		return "bool"
+	case *ast.ExprClosure, *ast.ExprArrowFunction:
+		return `\Closure`
+	case *ast.ExprBrackets:
+		return m.exprType(typed.Expr)
+	case *ast.ExprNew:
+		if lastNamePart(typed.Class) != "" {
+			return m.params.text(typed.Class)
+		}
+	case *ast.ExprVariable, *ast.ExprFunctionCall, *ast.ExprMethodCall, *ast.ExprNullsafeMethodCall,
+		*ast.ExprStaticCall, *ast.ExprPropertyFetch, *ast.ExprNullsafePropertyFetch,
+		*ast.ExprStaticPropertyFetch:
+		_, lastClass, left := expr.Resolve(node, &expr.Scopes{
+			Path:  m.params.Path,
+			Root:  m.params.Root,
+			Class: m.class,
+			Block: m.method,
+		})
+		if left == 0 && lastClass != nil {
+			return lastClass.String()
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,412 @@
+package codeactions_test
+
+import (
+	"testing"
+
+	"github.com/laytan/phpls/internal/codeactions"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractVariable(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name      string
+		input     string
+		selection string
+		// Empty if the selection can't be extracted.
+		expect string
+	}{
+		{
+			name: "expression",
+			input: `<?php
+
+function test(int $a, int $b) {
+    return $a + $b * 2;
+}
+`,
+			selection: "$b * 2",
+			expect: `<?php
+
+function test(int $a, int $b) {
+    $value = $b * 2;
+    return $a + $value;
+}
+`,
+		},
+		{
+			name: "named after the method",
+			input: `<?php
+
+class Foo {
+    public function test() {
+        $name = 1;
+        echo strtoupper($this->getName());
+    }
+}
+`,
+			selection: "$this->getName()",
+			expect: `<?php
+
+class Foo {
+    public function test() {
+        $name = 1;
+        $name2 = $this->getName();
+        echo strtoupper($name2);
+    }
+}
+`,
+		},
+		{
+			name: "loop condition",
+			input: `<?php
+
+while (next($foo)) {
+}
+`,
+			selection: "next($foo)",
+		},
+		{
+			name: "assignment target",
+			input: `<?php
+
+function test() {
+    $this->foo = 1;
+}
+`,
+			selection: "$this->foo",
+		},
+		{
+			name: "right of &&",
+			input: `<?php
+
+if ($u !== null && $u->isAdmin()) {
+}
+`,
+			selection: "$u->isAdmin()",
+		},
+		{
+			name: "right of ||",
+			input: `<?php
+
+if ($u === null || $u->isAdmin()) {
+}
+`,
+			selection: "$u->isAdmin()",
+		},
+		{
+			name: "right of and",
+			input: `<?php
+
+$ok = $u !== null and $u->isAdmin();
+`,
+			selection: "$u->isAdmin()",
+		},
+		{
+			name: "right of or",
+			input: `<?php
+
+$ok = $u === null or $u->isAdmin();
+`,
+			selection: "$u->isAdmin()",
+		},
+		{
+			name: "right of ??",
+			input: `<?php
+
+echo $a ?? compute();
+`,
+			selection: "compute()",
+		},
+		{
+			name: "ternary true branch",
+			input: `<?php
+
+echo $u ? $u->name() : 'none';
+`,
+			selection: "$u->name()",
+		},
+		{
+			name: "ternary false branch",
+			input: `<?php
+
+echo $u ? 'some' : fallback();
+`,
+			selection: "fallback()",
+		},
+		{
+			name: "short ternary",
+			input: `<?php
+
+echo $u ?: fallback();
+`,
+			selection: "fallback()",
+		},
+		{
+			name: "match arm body",
+			input: `<?php
+
+echo match ($a) {
+    1 => one(),
+    default => 0,
+};
+`,
+			selection: "one()",
+		},
+		{
+			name: "match arm condition",
+			input: `<?php
+
+echo match (true) {
+    check() => 1,
+    default => 0,
+};
+`,
+			selection: "check()",
+		},
+		{
+			name: "left of &&",
+			input: `<?php
+
+if (check() && $b) {
+}
+`,
+			selection: "check()",
+			expect: `<?php
+
+$check = check();
+if ($check && $b) {
+}
+`,
+		},
+		{
+			name: "partial expression",
+			input: `<?php
+
+echo 1 + 2 + 3;
+`,
+			selection: "2 + 3",
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			p := params(t, scenario.input)
+			p.Range = rangeOf(t, scenario.input, scenario.selection)
+
+			edits := codeactions.ExtractVariable(p)
+			if scenario.expect == "" {
+				require.Empty(t, edits)
+				return
+			}
+
+			require.Equal(t, scenario.expect, applyEdits(scenario.input, edits))
+		})
+	}
+}
+
+func TestExtractMethod(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name      string
+		input     string
+		selection string
+		// Empty if the selection can't be extracted.
+		expect string
+	}{
+		{
+			name: "parameters and return",
+			input: `<?php
+
+class Foo
+{
+    public function test(int $a, string $b): int
+    {
+        $c = 10;
+        $d = $a + $c;
+        echo $b;
+        $e = 'x';
+
+        return $d;
+    }
+}
+`,
+			selection: `$d = $a + $c;
+        echo $b;
+        $e = 'x';`,
+			expect: `<?php
+
+class Foo
+{
+    public function test(int $a, string $b): int
+    {
+        $c = 10;
+        $d = $this->extracted($a, $c, $b);
+
+        return $d;
+    }
+
+    private function extracted(int $a, int $c, string $b)
+    {
+        $d = $a + $c;
+        echo $b;
+        $e = 'x';
+
+        return $d;
+    }
+}
+`,
+		},
+		{
+			name: "static without return",
+			input: `<?php
+
+class Foo
+{
+	public static function test(): void
+	{
+		foreach ([1, 2] as $i) {
+			echo $i;
+		}
+	}
+
+	private function extracted()
+	{
+	}
+}
+`,
+			selection: `foreach ([1, 2] as $i) {
+			echo $i;
+		}`,
+			expect: `<?php
+
+class Foo
+{
+	public static function test(): void
+	{
+		self::extracted2();
+	}
+
+	private static function extracted2(): void
+	{
+		foreach ([1, 2] as $i) {
+			echo $i;
+		}
+	}
+
+	private function extracted()
+	{
+	}
+}
+`,
+		},
+		{
+			name: "multiple returns",
+			input: `<?php
+
+class Foo
+{
+    public function test()
+    {
+        /** @var Foo $a */
+        $a = $this->foo();
+        $a = $this->bar();
+        $b = 2;
+
+        return $a->baz($b);
+    }
+}
+`,
+			selection: `$a = $this->bar();
+        $b = 2;`,
+			expect: `<?php
+
+class Foo
+{
+    public function test()
+    {
+        /** @var Foo $a */
+        $a = $this->foo();
+        [$a, $b] = $this->extracted();
+
+        return $a->baz($b);
+    }
+
+    private function extracted(): array
+    {
+        $a = $this->bar();
+        $b = 2;
+
+        return [$a, $b];
+    }
+}
+`,
+		},
+		{
+			name: "return statement",
+			input: `<?php
+
+class Foo
+{
+    public function test()
+    {
+        if (true) {
+            return;
+        }
+    }
+}
+`,
+			selection: `if (true) {
+            return;
+        }`,
+		},
+		{
+			name: "partial statement",
+			input: `<?php
+
+class Foo
+{
+    public function test()
+    {
+        $a = 1;
+        $b = 2;
+    }
+}
+`,
+			selection: `1;
+        $b = 2;`,
+		},
+		{
+			name: "not in a method",
+			input: `<?php
+
+function test() {
+    echo 1;
+}
+`,
+			selection: "echo 1;",
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			p := params(t, scenario.input)
+			p.Range = rangeOf(t, scenario.input, scenario.selection)
+
+			edits := codeactions.ExtractMethod(p)
+			if scenario.expect == "" {
+				require.Empty(t, edits)
+				return
+			}
+
+			require.Equal(t, scenario.expect, applyEdits(scenario.input, edits))
+		})
+	}
+}
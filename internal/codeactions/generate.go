@@ -0,0 +1,555 @@
+package codeactions
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/laytan/go-lsp-protocol/pkg/lsp/protocol"
+	"github.com/laytan/php-parser/pkg/ast"
+	"github.com/laytan/phpls/internal/symbol"
+	"github.com/laytan/phpls/internal/wrkspc"
+	"github.com/laytan/phpls/pkg/nodeident"
+	"github.com/laytan/phpls/pkg/phpdoxer"
+	"github.com/laytan/phpls/pkg/phpversion"
+)
+
+// SourceGenerate is the kind of the actions that generate code for a class,
+// this is not a kind defined by the LSP spec, but is a sub kind of "source".
+const SourceGenerate protocol.CodeActionKind = "source.generate"
+
+type GenerateProvider struct{}
+
+func NewGenerate() *GenerateProvider {
+	return &GenerateProvider{}
+}
+
+func (g *GenerateProvider) Kind() protocol.CodeActionKind {
+	return SourceGenerate
+}
+
+func (g *GenerateProvider) Provide(params *Params) ([]protocol.CodeAction, error) {
+	var actions []protocol.CodeAction
+	add := func(title string, edits []protocol.TextEdit) {
+		if len(edits) > 0 {
+			actions = append(actions, protocol.CodeAction{
+				Title: title,
+				Kind:  SourceGenerate,
+				Edit:  params.edit(edits...),
+			})
+		}
+	}
+
+	add("Generate constructor", GenerateConstructor(params))
+	add("Promote constructor properties", PromoteProperties(params))
+
+	getters := GenerateAccessors(params, true, false)
+	setters := GenerateAccessors(params, false, true)
+	if len(getters) > 0 && len(setters) > 0 {
+		add("Generate getters and setters", GenerateAccessors(params, true, true))
+	}
+
+	add("Generate getters", getters)
+	add("Generate setters", setters)
+
+	return actions, nil
+}
+
+// GenerateConstructor returns the edits that add a constructor to the class,
+// assigning the selected properties, or all (non-static, without default
+// value) properties if none are selected.
+// If the class already has a constructor, nil is returned.
+func GenerateConstructor(params *Params) []protocol.TextEdit {
+	cls := newGenClass(params)
+	if cls == nil || cls.method("__construct") != nil {
+		return nil
+	}
+
+	props := cls.properties(func(prop *genProperty) bool {
+		return prop.node.Expr == nil
+	})
+	if len(props) == 0 {
+		return nil
+	}
+
+	var docs, args, body []string
+	for _, prop := range props {
+		hint, doc := prop.hint(params.PHPVersion, hintParam)
+		if doc != "" {
+			docs = append(docs, fmt.Sprintf("@param %s $%s", doc, prop.name))
+		}
+
+		args = append(args, strings.TrimSpace(hint+" $"+prop.name))
+		body = append(body, fmt.Sprintf("$this->%s = $%s;", prop.name, prop.name))
+	}
+
+	method := cls.renderMethod(
+		docs,
+		fmt.Sprintf("public function __construct(%s)", strings.Join(args, ", ")),
+		body,
+	)
+
+	// Add the constructor before the first method.
+	offset := cls.bodyStart()
+	for _, stmt := range cls.stmts {
+		if _, ok := stmt.(*ast.StmtClassMethod); ok {
+			break
+		}
+
+		offset = stmt.GetPosition().EndPos
+	}
+
+	nl := params.newline()
+	return []protocol.TextEdit{params.replace(offset, offset, nl+nl+method)}
+}
+
+// GenerateAccessors returns the edits that add getters and/or setters for the
+// selected properties, or all (non-static) properties if none are selected.
+// Accessors that already exist are not generated again, and readonly
+// properties don't get setters.
+func GenerateAccessors(params *Params, getters bool, setters bool) []protocol.TextEdit {
+	cls := newGenClass(params)
+	if cls == nil {
+		return nil
+	}
+
+	var methods []string
+	for _, prop := range cls.properties(nil) {
+		if getters && cls.method(prop.getter()) == nil {
+			hint, doc := prop.hint(params.PHPVersion, hintReturn)
+			var docs []string
+			if doc != "" {
+				docs = append(docs, "@return "+doc)
+			}
+
+			signature := fmt.Sprintf("public function %s()", prop.getter())
+			if hint != "" {
+				signature += ": " + hint
+			}
+
+			methods = append(methods, cls.renderMethod(
+				docs,
+				signature,
+				[]string{fmt.Sprintf("return $this->%s;", prop.name)},
+			))
+		}
+
+		if setters && !prop.readonly && cls.method(prop.setter()) == nil {
+			hint, doc := prop.hint(params.PHPVersion, hintParam)
+			var docs []string
+			if doc != "" {
+				docs = append(docs, fmt.Sprintf("@param %s $%s", doc, prop.name))
+			}
+
+			signature := fmt.Sprintf("public function %s(%s)", prop.setter(), strings.TrimSpace(hint+" $"+prop.name))
+			if void, ok := typeHint(&phpdoxer.TypeVoid{}, params.PHPVersion, hintReturn); ok {
+				signature += ": " + void
+			}
+
+			methods = append(methods, cls.renderMethod(
+				docs,
+				signature,
+				[]string{fmt.Sprintf("$this->%s = $%s;", prop.name, prop.name)},
+			))
+		}
+	}
+
+	if len(methods) == 0 {
+		return nil
+	}
+
+	offset := cls.bodyStart()
+	if len(cls.stmts) > 0 {
+		offset = cls.stmts[len(cls.stmts)-1].GetPosition().EndPos
+	}
+
+	nl := params.newline()
+	return []protocol.TextEdit{
+		params.replace(offset, offset, nl+nl+strings.Join(methods, nl+nl)),
+	}
+}
+
+// PromoteProperties returns the edits that convert properties which are
+// assigned a constructor parameter of the same name into promoted
+// constructor properties (PHP 8.0+).
+//
+// Properties with a default value, doc comment or attributes are left alone,
+// so no information is lost.
+func PromoteProperties(params *Params) []protocol.TextEdit {
+	if params.PHPVersion == nil || !atLeast(params.PHPVersion, 8, 0) {
+		return nil
+	}
+
+	cls := newGenClass(params)
+	if cls == nil {
+		return nil
+	}
+
+	constructor := cls.method("__construct")
+	if constructor == nil {
+		return nil
+	}
+
+	body, ok := constructor.Stmt.(*ast.StmtStmtList)
+	if !ok {
+		return nil
+	}
+
+	var edits []protocol.TextEdit
+	for _, stmt := range body.Stmts {
+		name, ok := propertyAssignment(stmt)
+		if !ok {
+			continue
+		}
+
+		param := constructorParam(constructor, name)
+		prop := cls.property(name)
+		if param == nil || prop == nil || !canPromote(prop) {
+			continue
+		}
+
+		var promoted string
+		for _, modifier := range prop.list.Modifiers {
+			// var is not allowed on parameters, it is the same as public.
+			if m := identifier(modifier); strings.EqualFold(m, "var") {
+				promoted += "public "
+			} else {
+				promoted += m + " "
+			}
+		}
+
+		if param.Type == nil && prop.list.Type != nil {
+			promoted += params.text(prop.list.Type) + " "
+		}
+
+		paramStart := param.Position.StartPos
+		stmtStart, stmtEnd := params.lineRange(stmt.GetPosition().StartPos, stmt.GetPosition().EndPos)
+		propStart, propEnd := params.blankLineRange(prop.list.Position.StartPos, prop.list.Position.EndPos)
+		edits = append(
+			edits,
+			params.replace(paramStart, paramStart, promoted),
+			params.replace(stmtStart, stmtEnd, ""),
+			params.replace(propStart, propEnd, ""),
+		)
+	}
+
+	return edits
+}
+
+// propertyAssignment returns the property name if the statement is an
+// assignment of a variable to the property of the same name: $this->foo = $foo.
+func propertyAssignment(stmt ast.Vertex) (string, bool) {
+	exprStmt, ok := stmt.(*ast.StmtExpression)
+	if !ok {
+		return "", false
+	}
+
+	assign, ok := exprStmt.Expr.(*ast.ExprAssign)
+	if !ok {
+		return "", false
+	}
+
+	fetch, ok := assign.Var.(*ast.ExprPropertyFetch)
+	if !ok {
+		return "", false
+	}
+
+	this, ok := fetch.Var.(*ast.ExprVariable)
+	if !ok || nodeident.Get(this) != "$this" {
+		return "", false
+	}
+
+	value, ok := assign.Expr.(*ast.ExprVariable)
+	name := identifier(fetch.Prop)
+	if !ok || name == "" || nodeident.Get(value) != "$"+name {
+		return "", false
+	}
+
+	return name, true
+}
+
+func constructorParam(constructor *ast.StmtClassMethod, name string) *ast.Parameter {
+	for _, param := range constructor.Params {
+		param := param.(*ast.Parameter)
+		if nodeident.Get(param) == "$"+name && len(param.Modifiers) == 0 &&
+			param.VariadicTkn == nil && param.AmpersandTkn == nil {
+			return param
+		}
+	}
+
+	return nil
+}
+
+func canPromote(prop *genProperty) bool {
+	return len(prop.list.Props) == 1 &&
+		len(prop.list.Modifiers) > 0 &&
+		len(prop.list.AttrGroups) == 0 &&
+		prop.node.Expr == nil &&
+		!prop.static &&
+		len(symbol.NodeComments(prop.list)) == 0
+}
+
+// blankLineRange is lineRange, but also removes a blank line following the
+// range, if it would otherwise leave two blank lines, or a blank line at the
+// start of a block.
+func (p *Params) blankLineRange(start, end int) (int, int) {
+	start, end = p.lineRange(start, end)
+	if start == 0 || p.Content[start-1] != '\n' {
+		return start, end
+	}
+
+	next := strings.IndexByte(p.Content[end:], '\n')
+	if next == -1 || strings.TrimSpace(p.Content[end:end+next]) != "" {
+		return start, end
+	}
+
+	prev := strings.TrimSpace(p.Content[strings.LastIndexByte(p.Content[:start-1], '\n')+1 : start])
+	if prev == "" || strings.HasSuffix(prev, "{") {
+		end += next + 1
+	}
+
+	return start, end
+}
+
+type genClass struct {
+	params *Params
+	node   ast.Vertex
+	stmts  []ast.Vertex
+	props  []*genProperty
+	// The range of the request.
+	start int
+	end   int
+}
+
+// newGenClass returns the class or trait the request is in, or nil.
+func newGenClass(params *Params) *genClass {
+	start, end := params.selection()
+	path := enclosing(params.Root, start, end)
+
+	for i := len(path) - 1; i >= 0; i-- {
+		switch path[i].(type) {
+		case *ast.StmtClass, *ast.StmtTrait:
+		default:
+			continue
+		}
+
+		stmts, _ := classStmts(path[i])
+		cls := &genClass{params: params, node: path[i], stmts: stmts, start: start, end: end}
+		cls.collectProperties()
+		return cls
+	}
+
+	return nil
+}
+
+func (c *genClass) collectProperties() {
+	sym := symbol.NewClassLike(wrkspc.NewRooter(c.params.Path, c.params.Root), c.node)
+	iter := sym.PropertiesIter()
+	for prop, done, err := iter(); !done; prop, done, err = iter() {
+		if err != nil {
+			log.Println(fmt.Errorf("[codeactions.genClass.collectProperties]: %w", err))
+			continue
+		}
+
+		// Promoted constructor properties are skipped.
+		list, ok := prop.Node().(*ast.StmtPropertyList)
+		if !ok || len(list.Props) == 0 {
+			continue
+		}
+
+		// A list declares multiple properties with the same modifiers and type,
+		// split it so each symbol is about one property.
+		for _, node := range list.Props {
+			node := node.(*ast.StmtProperty)
+			propSym := prop
+			if len(list.Props) > 1 {
+				single := *list
+				single.Props = []ast.Vertex{node}
+				single.SeparatorTkns = nil
+				propSym = symbol.NewProperty(sym, &single)
+			}
+
+			c.props = append(c.props, &genProperty{
+				params:   c.params,
+				sym:      propSym,
+				list:     list,
+				node:     node,
+				name:     strings.TrimPrefix(nodeident.Get(node.Var), "$"),
+				static:   propSym.IsStatic(),
+				readonly: nodeident.HasModifier(list.Modifiers, "readonly"),
+			})
+		}
+	}
+}
+
+// properties returns the non-static properties that are selected, or all
+// non-static properties matching the filter if none are selected.
+func (c *genClass) properties(filter func(*genProperty) bool) []*genProperty {
+	var selected, all []*genProperty
+	for _, prop := range c.props {
+		if prop.static {
+			continue
+		}
+
+		pos := prop.list.Position
+		if pos.StartPos <= c.end && pos.EndPos >= c.start {
+			selected = append(selected, prop)
+		}
+
+		if filter == nil || filter(prop) {
+			all = append(all, prop)
+		}
+	}
+
+	if len(selected) > 0 {
+		return selected
+	}
+
+	return all
+}
+
+func (c *genClass) property(name string) *genProperty {
+	for _, prop := range c.props {
+		if prop.name == name {
+			return prop
+		}
+	}
+
+	return nil
+}
+
+func (c *genClass) method(name string) *ast.StmtClassMethod {
+	for _, stmt := range c.stmts {
+		if method, ok := stmt.(*ast.StmtClassMethod); ok && strings.EqualFold(nodeident.Get(method), name) {
+			return method
+		}
+	}
+
+	return nil
+}
+
+// bodyStart returns the offset right after the opening curly bracket.
+func (c *genClass) bodyStart() int {
+	switch typed := c.node.(type) {
+	case *ast.StmtClass:
+		return typed.OpenCurlyBracketTkn.Position.EndPos
+	case *ast.StmtTrait:
+		return typed.OpenCurlyBracketTkn.Position.EndPos
+	default:
+		return c.node.GetPosition().EndPos
+	}
+}
+
+// indents returns the indentation of the class members and the indentation
+// of one level.
+func (c *genClass) indents() (member string, unit string) {
+	classIndent := c.params.indentAt(c.node.GetPosition().StartPos)
+	if len(c.stmts) > 0 {
+		member = c.params.indentAt(c.stmts[0].GetPosition().StartPos)
+	}
+
+	unit = strings.TrimPrefix(member, classIndent)
+	if unit == "" || unit == member && classIndent != "" {
+		unit = "    "
+		member = classIndent + unit
+	}
+
+	return member, unit
+}
+
+func (c *genClass) renderMethod(docs []string, signature string, body []string) string {
+	nl := c.params.newline()
+	indent, unit := c.indents()
+
+	var b strings.Builder
+	if len(docs) > 0 {
+		b.WriteString(indent + "/**" + nl)
+		for _, doc := range docs {
+			b.WriteString(indent + " * " + doc + nl)
+		}
+		b.WriteString(indent + " */" + nl)
+	}
+
+	b.WriteString(indent + signature + nl)
+	b.WriteString(indent + "{" + nl)
+	for _, line := range body {
+		b.WriteString(indent + unit + line + nl)
+	}
+	b.WriteString(indent + "}")
+
+	return b.String()
+}
+
+type genProperty struct {
+	params   *Params
+	sym      *symbol.Property
+	list     *ast.StmtPropertyList
+	node     *ast.StmtProperty
+	name     string
+	static   bool
+	readonly bool
+}
+
+// hint returns the native type hint to use for the property, and the doc type
+// if it is more specific than the native type hint.
+func (p *genProperty) hint(phpv *phpversion.PHPVersion, pos hintPosition) (hint string, doc string) {
+	typ, cls, err := p.sym.Type()
+	if err != nil {
+		if !errors.Is(err, symbol.ErrNoPropertyType) {
+			log.Println(fmt.Errorf("[codeactions.genProperty.hint]: %w", err))
+		}
+
+		// Union types etc. can't be converted by symbol, but are valid as is.
+		if p.list.Type != nil {
+			return p.params.text(p.list.Type), ""
+		}
+
+		return "", ""
+	}
+
+	// The type is written relative to another file, which can have other imports.
+	if cls.Path() != p.params.Path {
+		return "", ""
+	}
+
+	hint, ok := typeHint(typ, phpv, pos)
+	if !ok && p.list.Type != nil {
+		hint = p.params.text(p.list.Type)
+	}
+
+	if p.sym.FindDoc(symbol.FilterDocKind(phpdoxer.KindVar)) != nil && typ.String() != hint {
+		doc = typ.String()
+	}
+
+	return hint, doc
+}
+
+func (p *genProperty) getter() string {
+	hint, _ := p.hint(p.params.PHPVersion, hintReturn)
+	if hint == "bool" || hint == "?bool" {
+		if after, ok := strings.CutPrefix(p.name, "is"); ok && startsUpper(after) {
+			return p.name
+		}
+
+		return "is" + ucFirst(p.name)
+	}
+
+	return "get" + ucFirst(p.name)
+}
+
+func (p *genProperty) setter() string {
+	return "set" + ucFirst(p.name)
+}
+
+func ucFirst(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + s[size:]
+}
+
+func startsUpper(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
+	return unicode.IsUpper(r)
+}
@@ -0,0 +1,417 @@
+package codeactions_test
+
+import (
+	"testing"
+
+	"github.com/laytan/phpls/internal/codeactions"
+	"github.com/laytan/phpls/pkg/phpversion"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateConstructor(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name      string
+		input     string
+		selection string
+		// Empty if no constructor can be generated.
+		expect string
+	}{
+		{
+			name: "all properties",
+			input: `<?php
+
+class Foo
+{
+    private int $a;
+
+    /** @var array<string> */
+    private array $b;
+
+    private static $c;
+
+    public function test()
+    {
+    }
+}
+`,
+			selection: "class Foo",
+			expect: `<?php
+
+class Foo
+{
+    private int $a;
+
+    /** @var array<string> */
+    private array $b;
+
+    private static $c;
+
+    /**
+     * @param array<string> $b
+     */
+    public function __construct(int $a, array $b)
+    {
+        $this->a = $a;
+        $this->b = $b;
+    }
+
+    public function test()
+    {
+    }
+}
+`,
+		},
+		{
+			name: "selected properties",
+			input: `<?php
+
+class Foo {
+	private int $a;
+	private ?string $b;
+}
+`,
+			selection: "private ?string $b;",
+			expect: `<?php
+
+class Foo {
+	private int $a;
+	private ?string $b;
+
+	public function __construct(?string $b)
+	{
+		$this->b = $b;
+	}
+}
+`,
+		},
+		{
+			name: "multiple properties in one statement",
+			input: `<?php
+
+class Foo
+{
+    private int $a = 1, $b, $c;
+}
+`,
+			selection: "class Foo",
+			expect: `<?php
+
+class Foo
+{
+    private int $a = 1, $b, $c;
+
+    public function __construct(int $b, int $c)
+    {
+        $this->b = $b;
+        $this->c = $c;
+    }
+}
+`,
+		},
+		{
+			name: "existing constructor",
+			input: `<?php
+
+class Foo
+{
+    private int $a;
+
+    public function __construct()
+    {
+    }
+}
+`,
+			selection: "class Foo",
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			p := params(t, scenario.input)
+			p.Range = rangeOf(t, scenario.input, scenario.selection)
+
+			edits := codeactions.GenerateConstructor(p)
+			if scenario.expect == "" {
+				require.Empty(t, edits)
+				return
+			}
+
+			require.Equal(t, scenario.expect, applyEdits(scenario.input, edits))
+		})
+	}
+}
+
+func TestGenerateAccessors(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name      string
+		input     string
+		selection string
+		getters   bool
+		setters   bool
+		version   *phpversion.PHPVersion
+		expect    string
+	}{
+		{
+			name: "getters and setters",
+			input: `<?php
+
+class Foo
+{
+    private bool $enabled;
+
+    public readonly string $name;
+
+    /** @var Foo[] */
+    private $children;
+
+    public function getName(): string
+    {
+        return $this->name;
+    }
+}
+`,
+			selection: "class Foo",
+			getters:   true,
+			setters:   true,
+			expect: `<?php
+
+class Foo
+{
+    private bool $enabled;
+
+    public readonly string $name;
+
+    /** @var Foo[] */
+    private $children;
+
+    public function getName(): string
+    {
+        return $this->name;
+    }
+
+    public function isEnabled(): bool
+    {
+        return $this->enabled;
+    }
+
+    public function setEnabled(bool $enabled): void
+    {
+        $this->enabled = $enabled;
+    }
+
+    /**
+     * @return array<Foo>
+     */
+    public function getChildren(): array
+    {
+        return $this->children;
+    }
+
+    /**
+     * @param array<Foo> $children
+     */
+    public function setChildren(array $children): void
+    {
+        $this->children = $children;
+    }
+}
+`,
+		},
+		{
+			name: "multiple properties in one statement",
+			input: `<?php
+
+class Foo
+{
+    private $a, $b;
+
+    public function getA()
+    {
+        return $this->a;
+    }
+}
+`,
+			selection: "$b",
+			getters:   true,
+			expect: `<?php
+
+class Foo
+{
+    private $a, $b;
+
+    public function getA()
+    {
+        return $this->a;
+    }
+
+    public function getB()
+    {
+        return $this->b;
+    }
+}
+`,
+		},
+		{
+			name: "old php version",
+			input: `<?php
+
+class Foo
+{
+    /** @var ?int */
+    private $a;
+}
+`,
+			selection: "$a",
+			setters:   true,
+			version:   &phpversion.PHPVersion{Major: 7},
+			expect: `<?php
+
+class Foo
+{
+    /** @var ?int */
+    private $a;
+
+    /**
+     * @param null|int $a
+     */
+    public function setA($a)
+    {
+        $this->a = $a;
+    }
+}
+`,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			p := params(t, scenario.input)
+			p.Range = rangeOf(t, scenario.input, scenario.selection)
+			if scenario.version != nil {
+				p.PHPVersion = scenario.version
+			}
+
+			edits := codeactions.GenerateAccessors(p, scenario.getters, scenario.setters)
+			require.Equal(t, scenario.expect, applyEdits(scenario.input, edits))
+		})
+	}
+}
+
+func TestPromoteProperties(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name    string
+		input   string
+		version *phpversion.PHPVersion
+		// Empty if nothing can be promoted.
+		expect string
+	}{
+		{
+			name: "promotes assigned properties",
+			input: `<?php
+
+class Foo
+{
+    private int $a;
+
+    protected readonly string $b;
+
+    /** @var int[] */
+    private array $c;
+
+    public function __construct(int $a, $b, array $c)
+    {
+        $this->a = $a;
+        $this->b = $b;
+        $this->c = $c;
+    }
+}
+`,
+			expect: `<?php
+
+class Foo
+{
+    /** @var int[] */
+    private array $c;
+
+    public function __construct(private int $a, protected readonly string $b, array $c)
+    {
+        $this->c = $c;
+    }
+}
+`,
+		},
+		{
+			name: "var",
+			input: `<?php
+
+class Foo
+{
+    var $a;
+
+    public function __construct($a)
+    {
+        $this->a = $a;
+    }
+}
+`,
+			expect: `<?php
+
+class Foo
+{
+    public function __construct(public $a)
+    {
+    }
+}
+`,
+		},
+		{
+			name: "php 7",
+			input: `<?php
+
+class Foo
+{
+    private int $a;
+
+    public function __construct(int $a)
+    {
+        $this->a = $a;
+    }
+}
+`,
+			version: &phpversion.PHPVersion{Major: 7, Minor: 4},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			p := params(t, scenario.input)
+			p.Range = rangeOf(t, scenario.input, "class Foo")
+			if scenario.version != nil {
+				p.PHPVersion = scenario.version
+			}
+
+			edits := codeactions.PromoteProperties(p)
+			if scenario.expect == "" {
+				require.Empty(t, edits)
+				return
+			}
+
+			require.Equal(t, scenario.expect, applyEdits(scenario.input, edits))
+		})
+	}
+}
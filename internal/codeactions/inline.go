@@ -0,0 +1,295 @@
+package codeactions
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/laytan/go-lsp-protocol/pkg/lsp/protocol"
+	"github.com/laytan/php-parser/pkg/ast"
+	"github.com/laytan/php-parser/pkg/visitor"
+	"github.com/laytan/php-parser/pkg/visitor/traverser"
+	"github.com/laytan/phpls/pkg/nodeident"
+	"github.com/laytan/phpls/pkg/position"
+	"golang.org/x/exp/slices"
+)
+
+type InlineProvider struct{}
+
+func NewInline() *InlineProvider {
+	return &InlineProvider{}
+}
+
+func (i *InlineProvider) Kind() protocol.CodeActionKind {
+	return protocol.RefactorInline
+}
+
+func (i *InlineProvider) Provide(params *Params) ([]protocol.CodeAction, error) {
+	name, edits := InlineVariable(params)
+	if len(edits) == 0 {
+		return nil, nil
+	}
+
+	return []protocol.CodeAction{{
+		Title: fmt.Sprintf("Inline variable %s", name),
+		Kind:  protocol.RefactorInline,
+		Edit:  params.edit(edits...),
+	}}, nil
+}
+
+// InlineVariable returns the edits that replace the usages of the variable at
+// the start of the range with the expression it is assigned, and remove the
+// assignment.
+//
+// The variable must be a local variable that is assigned exactly once, as a
+// statement of its own, before any usages. Expressions with side effects, like
+// calls, are only inlined into a single usage that is not in a loop.
+func InlineVariable(params *Params) (name string, edits []protocol.TextEdit) {
+	cursor := position.FromLSPPosition(params.Content, params.Range.Start)
+	path := enclosing(params.Root, cursor, cursor)
+
+	var variable *ast.ExprVariable
+	for i := len(path) - 1; i >= 0 && variable == nil; i-- {
+		variable, _ = path[i].(*ast.ExprVariable)
+	}
+
+	scopeI := variableScope(path)
+	if variable == nil || scopeI == -1 {
+		return "", nil
+	}
+
+	var assignment *varOccurrence
+	var usages []*varOccurrence
+	for _, occurrence := range variables(scopeNodes(path[scopeI])...) {
+		if occurrence.name != nodeident.Get(variable) {
+			continue
+		}
+
+		if !occurrence.writes() {
+			usages = append(usages, occurrence)
+			continue
+		}
+
+		if assignment != nil {
+			return "", nil
+		}
+
+		assignment = occurrence
+	}
+
+	if assignment == nil || len(usages) == 0 {
+		return "", nil
+	}
+
+	assign, ok := assignment.parent.(*ast.ExprAssign)
+	if !ok || assign.Var != assignment.node {
+		return "", nil
+	}
+
+	stmt := assignmentStmt(params.Root, assign)
+	if stmt == nil {
+		return "", nil
+	}
+
+	for _, usage := range usages {
+		if usage.node.Position.StartPos < stmt.Position.EndPos {
+			return "", nil
+		}
+
+		switch usage.parent.(type) {
+		case *ast.ExprIsset, *ast.StmtUnset, *ast.ExprClosureUse:
+			return "", nil
+		}
+	}
+
+	// The expression is evaluated at each usage instead of once, that is only
+	// the same if it has no side effects and the variables it reads are not
+	// written in between. Otherwise, it has to be evaluated exactly once, at a
+	// single usage that is not in a loop.
+	pure := isPure(assign.Expr)
+	if !pure && len(usages) > 1 {
+		return "", nil
+	}
+
+	end := usages[len(usages)-1].node.Position.EndPos
+	for _, usage := range usages {
+		if loop := outerLoop(params.Root, usage, stmt); loop != nil {
+			if !pure {
+				return "", nil
+			}
+
+			if loopEnd := loop.GetPosition().EndPos; loopEnd > end {
+				end = loopEnd
+			}
+		}
+	}
+
+	if writesBetween(scopeNodes(path[scopeI]), assign.Expr, stmt.Position.EndPos, end) {
+		return "", nil
+	}
+
+	value := params.text(assign.Expr)
+	for _, usage := range usages {
+		replacement := value
+		if needsBrackets(assign.Expr, usage) {
+			replacement = "(" + value + ")"
+		}
+
+		pos := usage.node.Position
+		edits = append(edits, params.replace(pos.StartPos, pos.EndPos, replacement))
+	}
+
+	start, end := params.lineRange(stmt.Position.StartPos, stmt.Position.EndPos)
+	edits = append(edits, params.replace(start, end, ""))
+
+	return assignment.name, edits
+}
+
+// assignmentStmt returns the expression statement that consists of only the
+// given assignment, or nil if it is part of something else.
+func assignmentStmt(root *ast.Root, assign *ast.ExprAssign) *ast.StmtExpression {
+	path := enclosing(root, assign.Position.StartPos, assign.Position.EndPos)
+	for i := len(path) - 1; i > 0; i-- {
+		if path[i] != assign {
+			continue
+		}
+
+		if stmt, ok := path[i-1].(*ast.StmtExpression); ok {
+			return stmt
+		}
+
+		return nil
+	}
+
+	return nil
+}
+
+// isPure returns whether evaluating the expression has no side effects, so it
+// can be evaluated multiple times: literals, constants, variables and operators
+// on those.
+func isPure(expr ast.Vertex) bool {
+	v := &pureVisitor{pure: true}
+	expr.Accept(traverser.NewTraverser(v))
+	return v.pure
+}
+
+type pureVisitor struct {
+	visitor.Null
+
+	pure bool
+}
+
+func (v *pureVisitor) EnterNode(node ast.Vertex) bool {
+	if !v.pure {
+		return false
+	}
+
+	t := node.GetType()
+	switch {
+	case t >= ast.TypeExprBinaryBitwiseAnd && t <= ast.TypeExprBinarySpaceship,
+		t >= ast.TypeScalarDnumber && t <= ast.TypeScalarString,
+		t >= ast.TypeName && t <= ast.TypeNamePart:
+		return true
+	}
+
+	switch typed := node.(type) {
+	case *ast.Identifier, *ast.ExprArray, *ast.ExprArrayItem, *ast.ExprBrackets,
+		*ast.ExprConstFetch, *ast.ExprClassConstFetch, *ast.ExprUnaryMinus, *ast.ExprUnaryPlus,
+		*ast.ExprBooleanNot, *ast.ExprBitwiseNot, *ast.ExprTernary:
+		return true
+	case *ast.ExprVariable:
+		_, v.pure = typed.Name.(*ast.Identifier)
+		return v.pure
+	default:
+		v.pure = false
+		return false
+	}
+}
+
+// outerLoop returns the outermost loop, or arrow function, the usage is in
+// that does not also contain the assignment statement. Nil if there is none.
+func outerLoop(root *ast.Root, usage *varOccurrence, stmt ast.Vertex) ast.Vertex {
+	pos, stmtPos := usage.node.Position, stmt.GetPosition()
+	for _, node := range enclosing(root, pos.StartPos, pos.EndPos) {
+		switch node.(type) {
+		case *ast.StmtFor, *ast.StmtForeach, *ast.StmtWhile, *ast.StmtDo,
+			*ast.ExprArrowFunction:
+			loopPos := node.GetPosition()
+			if loopPos.StartPos > stmtPos.StartPos || loopPos.EndPos < stmtPos.EndPos {
+				return node
+			}
+		}
+	}
+
+	return nil
+}
+
+// writesBetween returns whether any of the variables read by the expression
+// are written to in the scope, between the start and end offsets.
+func writesBetween(scope []ast.Vertex, expr ast.Vertex, start, end int) bool {
+	var reads []string
+	for _, occurrence := range variables(expr) {
+		reads = append(reads, occurrence.name)
+	}
+
+	for _, occurrence := range variables(scope...) {
+		pos := occurrence.node.Position
+		if occurrence.writes() && pos.StartPos >= start && pos.EndPos <= end &&
+			slices.Contains(reads, occurrence.name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// needsBrackets returns whether the expression needs to be wrapped in brackets
+// when it replaces the variable usage, to keep operator precedence intact.
+func needsBrackets(value ast.Vertex, usage *varOccurrence) bool {
+	switch value.(type) {
+	case *ast.ExprVariable, *ast.ExprConstFetch, *ast.ExprClassConstFetch, *ast.ExprFunctionCall,
+		*ast.ExprMethodCall, *ast.ExprNullsafeMethodCall, *ast.ExprStaticCall,
+		*ast.ExprPropertyFetch, *ast.ExprNullsafePropertyFetch, *ast.ExprStaticPropertyFetch,
+		*ast.ExprArray, *ast.ExprArrayDimFetch, *ast.ExprBrackets, *ast.ExprIsset, *ast.ExprEmpty,
+		*ast.ScalarDnumber, *ast.ScalarLnumber, *ast.ScalarString, *ast.ScalarEncapsed,
+		*ast.ScalarHeredoc, *ast.ScalarMagicConstant:
+		return false
+	case *ast.ExprBinaryLogicalAnd, *ast.ExprBinaryLogicalOr, *ast.ExprBinaryLogicalXor:
+		return true
+	}
+
+	switch parent := usage.parent.(type) {
+	case *ast.Argument, *ast.ExprArrayItem, *ast.ExprAssign, *ast.ExprBrackets, *ast.StmtReturn,
+		*ast.StmtEcho, *ast.StmtExpression, *ast.StmtIf, *ast.StmtElseIf, *ast.StmtWhile,
+		*ast.StmtSwitch:
+		return false
+	case *ast.ExprArrayDimFetch:
+		return parent.Dim != usage.node
+	default:
+		return true
+	}
+}
+
+// lineRange expands the range to the whole line(s), including the line break,
+// if there is nothing else on them.
+func (p *Params) lineRange(start, end int) (int, int) {
+	lineStart := strings.LastIndexByte(p.Content[:start], '\n') + 1
+	if strings.TrimSpace(p.Content[lineStart:start]) != "" {
+		return start, end
+	}
+
+	lineEnd := strings.IndexByte(p.Content[end:], '\n')
+	if lineEnd == -1 {
+		lineEnd = len(p.Content) - end
+	}
+
+	if strings.TrimSpace(p.Content[end:end+lineEnd]) != "" {
+		return start, end
+	}
+
+	end += lineEnd
+	if end < len(p.Content) {
+		end++
+	}
+
+	return lineStart, end
+}
@@ -0,0 +1,208 @@
+package codeactions_test
+
+import (
+	"testing"
+
+	"github.com/laytan/phpls/internal/codeactions"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInlineVariable(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name   string
+		input  string
+		cursor string
+		// Empty if the variable can't be inlined.
+		expect string
+	}{
+		{
+			name: "brackets where needed",
+			input: `<?php
+
+function test() {
+    $a = 1 + 2;
+    echo $a * 3;
+    foo($a);
+}
+`,
+			cursor: "$a * 3",
+			expect: `<?php
+
+function test() {
+    echo (1 + 2) * 3;
+    foo(1 + 2);
+}
+`,
+		},
+		{
+			name: "method call",
+			input: `<?php
+
+class Foo {
+    public function test() {
+        $name = $this->name();
+        return $name->foo;
+    }
+}
+`,
+			cursor: "$name =",
+			expect: `<?php
+
+class Foo {
+    public function test() {
+        return $this->name()->foo;
+    }
+}
+`,
+		},
+		{
+			name: "assigned multiple times",
+			input: `<?php
+
+function test() {
+    $a = 1;
+    $a = 2;
+    echo $a;
+}
+`,
+			cursor: "$a;",
+		},
+		{
+			name: "parameter",
+			input: `<?php
+
+function test($a) {
+    $a = 1;
+    echo $a;
+}
+`,
+			cursor: "$a;",
+		},
+		{
+			name: "used before assignment",
+			input: `<?php
+
+function test() {
+    echo $a;
+    $a = 1;
+    echo $a;
+}
+`,
+			cursor: "$a;",
+		},
+		{
+			name: "call used multiple times",
+			input: `<?php
+
+function test() {
+    $x = foo();
+    a($x);
+    b($x);
+}
+`,
+			cursor: "$x =",
+		},
+		{
+			name: "call used in loop",
+			input: `<?php
+
+function test($items) {
+    $x = foo();
+    foreach ($items as $item) {
+        a($x, $item);
+    }
+}
+`,
+			cursor: "$x =",
+		},
+		{
+			name: "call in same loop",
+			input: `<?php
+
+function test($items) {
+    foreach ($items as $item) {
+        $x = foo($item);
+        a($x);
+    }
+}
+`,
+			cursor: "$x =",
+			expect: `<?php
+
+function test($items) {
+    foreach ($items as $item) {
+        a(foo($item));
+    }
+}
+`,
+		},
+		{
+			name: "read variable written before usage",
+			input: `<?php
+
+function test() {
+    $a = 1;
+    $x = $a + 1;
+    $a = 5;
+    echo $x;
+}
+`,
+			cursor: "$x =",
+		},
+		{
+			name: "read variable written in loop after usage",
+			input: `<?php
+
+function test($a) {
+    $x = $a * 2;
+    while ($a < 10) {
+        echo $x;
+        $a++;
+    }
+}
+`,
+			cursor: "$x =",
+		},
+		{
+			name: "constant used in loop",
+			input: `<?php
+
+function test($items) {
+    $x = PREFIX . '_';
+    foreach ($items as $item) {
+        echo $x . $item;
+    }
+}
+`,
+			cursor: "$x =",
+			expect: `<?php
+
+function test($items) {
+    foreach ($items as $item) {
+        echo (PREFIX . '_') . $item;
+    }
+}
+`,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			p := params(t, scenario.input)
+			p.Range = rangeOf(t, scenario.input, scenario.cursor)
+
+			_, edits := codeactions.InlineVariable(p)
+			if scenario.expect == "" {
+				require.Empty(t, edits)
+				return
+			}
+
+			require.Equal(t, scenario.expect, applyEdits(scenario.input, edits))
+		})
+	}
+}
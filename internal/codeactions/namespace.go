@@ -0,0 +1,45 @@
+package codeactions
+
+import (
+	"fmt"
+
+	"github.com/laytan/go-lsp-protocol/pkg/lsp/protocol"
+	"github.com/laytan/phpls/internal/diagnostics"
+	"github.com/laytan/phpls/pkg/position"
+)
+
+// NamespaceProvider corrects namespaces that don't match the PSR-4 autoload
+// mapping, as reported by the declarations diagnostics.
+type NamespaceProvider struct{}
+
+func NewNamespace() *NamespaceProvider {
+	return &NamespaceProvider{}
+}
+
+func (n *NamespaceProvider) Kind() protocol.CodeActionKind {
+	return protocol.QuickFix
+}
+
+func (n *NamespaceProvider) Provide(params *Params) ([]protocol.CodeAction, error) {
+	var actions []protocol.CodeAction
+	for i := range params.Diagnostics {
+		diagnostic := params.Diagnostics[i]
+		data, ok := diagnostics.NamespaceDiagnostic(&diagnostic)
+		if !ok {
+			continue
+		}
+
+		start := position.FromLSPPosition(params.Content, diagnostic.Range.Start)
+		end := position.FromLSPPosition(params.Content, diagnostic.Range.End)
+
+		actions = append(actions, protocol.CodeAction{
+			Title:       fmt.Sprintf("Change namespace to %s", data.Namespace),
+			Kind:        protocol.QuickFix,
+			Diagnostics: []protocol.Diagnostic{diagnostic},
+			IsPreferred: true,
+			Edit:        params.edit(params.replace(start, end, data.Namespace)),
+		})
+	}
+
+	return actions, nil
+}
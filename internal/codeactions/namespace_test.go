@@ -0,0 +1,54 @@
+package codeactions_test
+
+import (
+	"testing"
+
+	"github.com/laytan/go-lsp-protocol/pkg/lsp/protocol"
+	"github.com/laytan/phpls/internal/codeactions"
+	"github.com/laytan/phpls/internal/diagnostics"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamespace(t *testing.T) {
+	t.Parallel()
+
+	input := `<?php
+
+namespace App\Model;
+
+class User
+{
+}
+`
+
+	p := params(t, input)
+	p.Diagnostics = []protocol.Diagnostic{
+		{
+			Range:  rangeOf(t, input, `App\Model`),
+			Code:   "psr4-namespace",
+			Source: "phpls-declarations",
+			Data:   &diagnostics.NamespaceData{Namespace: `App\Models`},
+		},
+		// Without data, the namespace can't be corrected.
+		{
+			Range:  rangeOf(t, input, "User"),
+			Code:   "psr4-namespace",
+			Source: "phpls-declarations",
+		},
+	}
+
+	actions, err := codeactions.NewNamespace().Provide(p)
+	require.NoError(t, err)
+	require.Len(t, actions, 1)
+	require.Equal(t, `Change namespace to App\Models`, actions[0].Title)
+
+	edits := actions[0].Edit.Changes[protocol.DocumentURI("file://"+p.Path)]
+	require.Equal(t, `<?php
+
+namespace App\Models;
+
+class User
+{
+}
+`, applyEdits(input, edits))
+}
@@ -0,0 +1,218 @@
+package codeactions
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/laytan/go-lsp-protocol/pkg/lsp/protocol"
+	"github.com/laytan/php-parser/pkg/ast"
+	"github.com/laytan/phpls/internal/config"
+	"github.com/laytan/phpls/pkg/imports"
+	"github.com/laytan/phpls/pkg/set"
+	"golang.org/x/exp/slices"
+)
+
+type OrganizeImportsOptions struct {
+	GroupByType bool
+	GroupUse    config.GroupUseStyle
+}
+
+type OrganizeImportsProvider struct{}
+
+func NewOrganizeImports() *OrganizeImportsProvider {
+	return &OrganizeImportsProvider{}
+}
+
+func (o *OrganizeImportsProvider) Kind() protocol.CodeActionKind {
+	return protocol.SourceOrganizeImports
+}
+
+func (o *OrganizeImportsProvider) Provide(params *Params) ([]protocol.CodeAction, error) {
+	cfg := config.Current.CodeActions.OrganizeImports
+	edits := OrganizeImports(params, &OrganizeImportsOptions{
+		GroupByType: cfg.GroupByType,
+		GroupUse:    cfg.GroupUse,
+	})
+	if len(edits) == 0 {
+		return nil, nil
+	}
+
+	return []protocol.CodeAction{{
+		Title: "Organize imports",
+		Kind:  protocol.SourceOrganizeImports,
+		Edit:  params.edit(edits...),
+	}}, nil
+}
+
+// OrganizeImports returns the edits that remove unused imports and sort the
+// remaining ones, for each namespace in the file.
+func OrganizeImports(params *Params, opts *OrganizeImportsOptions) []protocol.TextEdit {
+	var edits []protocol.TextEdit
+	for _, scope := range imports.Scopes(params.Root, len(params.Content)) {
+		if edit, ok := organizeScope(scope, params, opts); ok {
+			edits = append(edits, edit)
+		}
+	}
+
+	return edits
+}
+
+func organizeScope(
+	s *imports.Scope,
+	params *Params,
+	opts *OrganizeImportsOptions,
+) (protocol.TextEdit, bool) {
+	first, last := -1, -1
+	for i, stmt := range s.Stmts {
+		switch stmt.(type) {
+		case *ast.StmtUseList, *ast.StmtGroupUseList:
+			if first == -1 {
+				first = i
+			}
+			last = i
+		}
+	}
+
+	if first == -1 {
+		return protocol.TextEdit{}, false
+	}
+
+	usage := s.Usage(params.Content)
+	var used []*imports.Import
+	for _, stmt := range s.Stmts[first : last+1] {
+		stmtImports, ok := imports.Parse(stmt)
+		// Something between the imports, we don't want to move code around.
+		if !ok {
+			return protocol.TextEdit{}, false
+		}
+
+		for _, imp := range stmtImports {
+			if usage.Uses(imp) {
+				used = append(used, imp)
+			}
+		}
+	}
+
+	start := s.Stmts[first].GetPosition().StartPos
+	end := s.Stmts[last].GetPosition().EndPos
+
+	newline := "\n"
+	if strings.Contains(params.Content, "\r\n") {
+		newline = "\r\n"
+	}
+
+	lineStart := strings.LastIndexByte(params.Content[:start], '\n') + 1
+	indent := params.Content[lineStart:start]
+	if strings.TrimSpace(indent) != "" {
+		indent = ""
+	}
+
+	text := renderImports(used, opts, newline, indent)
+	if text == "" {
+		start, end = removalRange(params.Content, lineStart, start, end, indent)
+	}
+
+	if params.Content[start:end] == text {
+		return protocol.TextEdit{}, false
+	}
+
+	return params.replace(start, end, text), true
+}
+
+// removalRange widens the range to remove the lines of the use statements
+// entirely, including one empty line after them.
+func removalRange(content string, lineStart, start, end int, indent string) (int, int) {
+	if indent != "" || lineStart == start {
+		start = lineStart
+	}
+
+	for i := 0; i < 2; i++ {
+		rest := content[end:]
+		lineEnd := strings.IndexByte(rest, '\n')
+		if lineEnd == -1 || strings.TrimSpace(rest[:lineEnd]) != "" {
+			break
+		}
+
+		end += lineEnd + 1
+	}
+
+	return start, end
+}
+
+func renderImports(
+	imps []*imports.Import,
+	opts *OrganizeImportsOptions,
+	newline string,
+	indent string,
+) string {
+	slices.SortStableFunc(imps, func(a, b *imports.Import) bool {
+		if opts.GroupByType && a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+
+		return strings.ToLower(a.String()) < strings.ToLower(b.String())
+	})
+
+	var lines []string
+	seen := set.New[string]()
+	for i, imp := range imps {
+		key := imp.Kind.Keyword() + imp.String()
+		if seen.Has(key) {
+			continue
+		}
+		seen.Add(key)
+
+		if opts.GroupByType && i > 0 && imps[i-1].Kind != imp.Kind {
+			lines = append(lines, "")
+		}
+
+		if opts.GroupUse != config.GroupUseMerge || imp.Namespace() == "" {
+			lines = append(lines, fmt.Sprintf("use %s%s;", imp.Kind.Keyword(), imp))
+			continue
+		}
+
+		// Already rendered in a group.
+		if seen.Has(groupKey(imp)) {
+			continue
+		}
+
+		var group []*imports.Import
+		for _, other := range imps[i:] {
+			if other.Kind == imp.Kind && other.Namespace() == imp.Namespace() {
+				group = append(group, other)
+			}
+		}
+
+		if len(group) == 1 {
+			lines = append(lines, fmt.Sprintf("use %s%s;", imp.Kind.Keyword(), imp))
+			continue
+		}
+
+		seen.Add(groupKey(imp))
+		members := make([]string, 0, len(group))
+		for _, member := range group {
+			seen.Add(member.Kind.Keyword() + member.String())
+			members = append(members, strings.TrimPrefix(member.String(), imp.Namespace()+`\`))
+		}
+
+		members = slices.Compact(members)
+		lines = append(lines, fmt.Sprintf(
+			"use %s%s\\{%s};",
+			imp.Kind.Keyword(),
+			imp.Namespace(),
+			strings.Join(members, ", "),
+		))
+	}
+
+	for i := 1; i < len(lines); i++ {
+		if lines[i] != "" {
+			lines[i] = indent + lines[i]
+		}
+	}
+
+	return strings.Join(lines, newline)
+}
+
+func groupKey(imp *imports.Import) string {
+	return imp.Kind.Keyword() + imp.Namespace() + `\{}`
+}
@@ -0,0 +1,417 @@
+package codeactions_test
+
+import (
+	"testing"
+
+	"github.com/laytan/phpls/internal/codeactions"
+	"github.com/laytan/phpls/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrganizeImports(t *testing.T) {
+	t.Parallel()
+
+	grouped := &codeactions.OrganizeImportsOptions{
+		GroupByType: true,
+		GroupUse:    config.GroupUseSplit,
+	}
+
+	scenarios := []struct {
+		name   string
+		opts   *codeactions.OrganizeImportsOptions
+		input  string
+		expect string
+	}{
+		{
+			name: "removes unused and sorts",
+			opts: grouped,
+			input: `<?php
+
+namespace Test;
+
+use Foo\Zed;
+use Foo\Unused;
+use Foo\Bar;
+
+new Bar();
+Zed::test();
+`,
+			expect: `<?php
+
+namespace Test;
+
+use Foo\Bar;
+use Foo\Zed;
+
+new Bar();
+Zed::test();
+`,
+		},
+		{
+			name: "groups by type",
+			opts: grouped,
+			input: `<?php
+
+use const Foo\BAR;
+use function Foo\bar;
+use function Foo\unused;
+use Foo\Baz;
+
+echo bar(BAR, Baz::class);
+`,
+			expect: `<?php
+
+use Foo\Baz;
+
+use function Foo\bar;
+
+use const Foo\BAR;
+
+echo bar(BAR, Baz::class);
+`,
+		},
+		{
+			name: "without grouping",
+			opts: &codeactions.OrganizeImportsOptions{GroupUse: config.GroupUseSplit},
+			input: `<?php
+
+use Foo\Qux;
+use function Foo\bar;
+
+echo bar(Qux::class);
+`,
+			expect: `<?php
+
+use function Foo\bar;
+use Foo\Qux;
+
+echo bar(Qux::class);
+`,
+		},
+		{
+			name: "keeps imports used in phpdoc",
+			opts: grouped,
+			input: `<?php
+
+use Foo\Param;
+use Foo\Returned;
+use Foo\Thrown;
+use Foo\Generic;
+use Foo\Unused;
+
+/**
+ * @param Param $a
+ * @return array<int, Generic>|Returned
+ * @throws Thrown
+ */
+function test($a) {}
+`,
+			expect: `<?php
+
+use Foo\Generic;
+use Foo\Param;
+use Foo\Returned;
+use Foo\Thrown;
+
+/**
+ * @param Param $a
+ * @return array<int, Generic>|Returned
+ * @throws Thrown
+ */
+function test($a) {}
+`,
+		},
+		{
+			name: "keeps imports used in generic tags",
+			opts: grouped,
+			input: `<?php
+
+use Foo\Repo;
+use Foo\Collection;
+use Foo\User;
+use Foo\Bound;
+use Foo\HasItems;
+use Foo\Unused;
+
+/**
+ * @template T of Bound
+ * @extends Collection<User>
+ * @implements Repo<User>
+ */
+class Users extends Collection implements Repo
+{
+    /** @use HasItems<User> */
+    use \Foo\Items;
+}
+`,
+			expect: `<?php
+
+use Foo\Bound;
+use Foo\Collection;
+use Foo\HasItems;
+use Foo\Repo;
+use Foo\User;
+
+/**
+ * @template T of Bound
+ * @extends Collection<User>
+ * @implements Repo<User>
+ */
+class Users extends Collection implements Repo
+{
+    /** @use HasItems<User> */
+    use \Foo\Items;
+}
+`,
+		},
+		{
+			name: "keeps imports used in magic members",
+			opts: grouped,
+			input: `<?php
+
+use Foo\Written;
+use Foo\Prop;
+use Foo\Read;
+use Foo\Returned;
+use Foo\First;
+use Foo\Second;
+use Foo\Unused;
+
+/**
+ * @property Prop $prop
+ * @property-read Read $read
+ * @property-write Written $written
+ * @method Returned make(First $first, Second ...$rest)
+ */
+class Magic {}
+`,
+			expect: `<?php
+
+use Foo\First;
+use Foo\Prop;
+use Foo\Read;
+use Foo\Returned;
+use Foo\Second;
+use Foo\Written;
+
+/**
+ * @property Prop $prop
+ * @property-read Read $read
+ * @property-write Written $written
+ * @method Returned make(First $first, Second ...$rest)
+ */
+class Magic {}
+`,
+		},
+		{
+			name: "keeps imports used in mixins",
+			opts: grouped,
+			input: `<?php
+
+use Foo\Unused;
+use Foo\Builder;
+
+/**
+ * @mixin Builder
+ */
+class Model {}
+`,
+			expect: `<?php
+
+use Foo\Builder;
+
+/**
+ * @mixin Builder
+ */
+class Model {}
+`,
+		},
+		{
+			name: "keeps imports used anywhere in unknown tags",
+			opts: grouped,
+			input: `<?php
+
+use Foo\Seen;
+use Foo\Out;
+use Foo\Aliased;
+use Foo\Source;
+use Foo\Unused;
+use function Foo\helper;
+
+/**
+ * @see Seen::test()
+ * @see helper()
+ * @param-out array<Out> $out
+ * @psalm-type Alias = array<int, Aliased>
+ * @phpstan-import-type Imported from Source
+ */
+function test(&$out) {}
+`,
+			expect: `<?php
+
+use Foo\Aliased;
+use Foo\Out;
+use Foo\Seen;
+use Foo\Source;
+
+use function Foo\helper;
+
+/**
+ * @see Seen::test()
+ * @see helper()
+ * @param-out array<Out> $out
+ * @psalm-type Alias = array<int, Aliased>
+ * @phpstan-import-type Imported from Source
+ */
+function test(&$out) {}
+`,
+		},
+		{
+			name: "aliases and namespace imports",
+			opts: grouped,
+			input: `<?php
+
+use Foo\Bar as Baz;
+use Foo\Sub;
+use Foo\Bar;
+
+new Baz();
+new Sub\Test();
+`,
+			expect: `<?php
+
+use Foo\Bar as Baz;
+use Foo\Sub;
+
+new Baz();
+new Sub\Test();
+`,
+		},
+		{
+			name: "splits group use",
+			opts: grouped,
+			input: `<?php
+
+use Foo\{Bar, Baz as Qux, Unused};
+
+new Bar();
+new Qux();
+`,
+			expect: `<?php
+
+use Foo\Bar;
+use Foo\Baz as Qux;
+
+new Bar();
+new Qux();
+`,
+		},
+		{
+			name: "merges group use",
+			opts: &codeactions.OrganizeImportsOptions{
+				GroupByType: true,
+				GroupUse:    config.GroupUseMerge,
+			},
+			input: `<?php
+
+use Foo\Baz as Qux;
+use Other\Test;
+use Foo\Bar;
+
+new Bar();
+new Qux();
+new Test();
+`,
+			expect: `<?php
+
+use Foo\{Bar, Baz as Qux};
+use Other\Test;
+
+new Bar();
+new Qux();
+new Test();
+`,
+		},
+		{
+			name: "removes all",
+			opts: grouped,
+			input: `<?php
+
+namespace Test;
+
+use Foo\Bar;
+
+echo 'test';
+`,
+			expect: `<?php
+
+namespace Test;
+
+echo 'test';
+`,
+		},
+		{
+			name: "multiple namespaces",
+			opts: grouped,
+			input: `<?php
+
+namespace A {
+    use Foo\Unused;
+    use Foo\Bar;
+    use Foo\Another;
+
+    new Bar();
+    new Another();
+}
+
+namespace B {
+    use Foo\Bar;
+
+    function test(Bar $bar) {}
+}
+`,
+			expect: `<?php
+
+namespace A {
+    use Foo\Another;
+    use Foo\Bar;
+
+    new Bar();
+    new Another();
+}
+
+namespace B {
+    use Foo\Bar;
+
+    function test(Bar $bar) {}
+}
+`,
+		},
+		{
+			name: "nothing to do",
+			opts: grouped,
+			input: `<?php
+
+use Foo\Bar;
+
+new Bar();
+`,
+			expect: `<?php
+
+use Foo\Bar;
+
+new Bar();
+`,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			edits := codeactions.OrganizeImports(params(t, scenario.input), scenario.opts)
+			require.Equal(t, scenario.expect, applyEdits(scenario.input, edits))
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package codeactions
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/laytan/go-lsp-protocol/pkg/lsp/protocol"
+	"github.com/laytan/phpls/internal/diagnostics"
+	"github.com/laytan/phpls/pkg/diff"
+	"github.com/laytan/phpls/pkg/phpcs"
+	"github.com/laytan/phpls/pkg/position"
+)
+
+// SniffFixer fixes the violations of specific phpcs sniffs in code.
+type SniffFixer interface {
+	FormatSniffs(code []byte, sniffs ...string) ([]byte, error)
+}
+
+type PhpcsProvider struct{}
+
+func NewPhpcs() *PhpcsProvider {
+	return &PhpcsProvider{}
+}
+
+func (p *PhpcsProvider) Kind() protocol.CodeActionKind {
+	return protocol.QuickFix
+}
+
+func (p *PhpcsProvider) Provide(params *Params) ([]protocol.CodeAction, error) {
+	var actions []protocol.CodeAction
+
+	// Each sniff only needs to be run once, even with multiple violations.
+	fixed := make(map[string]*diff.Diff)
+
+	for i := range params.Diagnostics {
+		diagnostic := params.Diagnostics[i]
+		data, ok := diagnostics.PhpcsDiagnostic(&diagnostic)
+		if !ok {
+			continue
+		}
+
+		source, ok := diagnostic.Code.(string)
+		if !ok {
+			continue
+		}
+
+		sniff := phpcs.SniffCode(source)
+
+		if data.Fixable && params.Phpcbf != nil {
+			if _, ok := fixed[sniff]; !ok {
+				fixed[sniff] = fixSniff(params, sniff)
+			}
+
+			if edits := FixViolation(fixed[sniff], diagnostic); len(edits) > 0 {
+				actions = append(actions, protocol.CodeAction{
+					Title:       fmt.Sprintf("Fix %s", source),
+					Kind:        protocol.QuickFix,
+					Diagnostics: []protocol.Diagnostic{diagnostic},
+					IsPreferred: true,
+					Edit:        params.edit(edits...),
+				})
+			}
+		}
+
+		actions = append(actions, protocol.CodeAction{
+			Title:       fmt.Sprintf("Ignore %s for this line", sniff),
+			Kind:        protocol.QuickFix,
+			Diagnostics: []protocol.Diagnostic{diagnostic},
+			Edit:        params.edit(IgnoreViolation(params, diagnostic, sniff)),
+		})
+	}
+
+	return actions, nil
+}
+
+func fixSniff(params *Params, sniff string) *diff.Diff {
+	out, err := params.Phpcbf.FormatSniffs([]byte(params.Content), sniff)
+	if err != nil {
+		log.Println(fmt.Errorf("[codeactions.fixSniff]: fixing %s: %w", sniff, err))
+		return nil
+	}
+
+	return diff.New(params.Content, string(out))
+}
+
+// FixViolation returns the edits out of the diff, created by fixing the sniff
+// of the diagnostic in the whole file, that fix the line of the diagnostic.
+func FixViolation(fixed *diff.Diff, diagnostic protocol.Diagnostic) []protocol.TextEdit {
+	if fixed == nil {
+		return nil
+	}
+
+	var edits []protocol.TextEdit
+	for _, hunk := range fixed.Hunks {
+		if hunk.Contains(int(diagnostic.Range.Start.Line)) {
+			edits = append(edits, fixed.Edit(hunk))
+		}
+	}
+
+	return edits
+}
+
+// IgnoreViolation returns the edit that adds a phpcs:ignore comment for the
+// sniff on the line above the diagnostic, or adds the sniff to an existing
+// phpcs:ignore comment there.
+func IgnoreViolation(params *Params, diagnostic protocol.Diagnostic, sniff string) protocol.TextEdit {
+	lineStart := position.FromLSPPosition(
+		params.Content,
+		protocol.Position{Line: diagnostic.Range.Start.Line},
+	)
+
+	if lineStart > 0 {
+		prevStart := strings.LastIndexByte(params.Content[:lineStart-1], '\n') + 1
+		prev := strings.TrimRight(params.Content[prevStart:lineStart-1], "\r")
+		if strings.HasPrefix(strings.TrimSpace(prev), "// phpcs:ignore ") {
+			end := prevStart + len(prev)
+			return params.replace(end, end, ", "+sniff)
+		}
+	}
+
+	comment := params.indentAt(lineStart) + "// phpcs:ignore " + sniff + params.newline()
+	return params.replace(lineStart, lineStart, comment)
+}
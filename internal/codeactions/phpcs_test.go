@@ -0,0 +1,108 @@
+package codeactions_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/laytan/go-lsp-protocol/pkg/lsp/protocol"
+	"github.com/laytan/phpls/internal/codeactions"
+	"github.com/laytan/phpls/internal/diagnostics"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeFixer struct {
+	fix func(code string) string
+}
+
+func (f *fakeFixer) FormatSniffs(code []byte, _ ...string) ([]byte, error) {
+	return []byte(f.fix(string(code))), nil
+}
+
+func TestPhpcs(t *testing.T) {
+	t.Parallel()
+
+	input := `<?php
+
+function test() {
+    $a=1;
+    // phpcs:ignore Generic.Files.LineLength
+    $b=2;
+}
+`
+
+	diagnostic := func(line uint32, fixable bool) protocol.Diagnostic {
+		return protocol.Diagnostic{
+			Range:  protocol.Range{Start: protocol.Position{Line: line}, End: protocol.Position{Line: line}},
+			Code:   "Squiz.WhiteSpace.OperatorSpacing.NoSpaceBefore",
+			Source: "phpls-phpcs",
+			Data:   &diagnostics.PhpcsData{Fixable: fixable},
+		}
+	}
+
+	scenarios := []struct {
+		name       string
+		diagnostic protocol.Diagnostic
+		// The expected content after applying each action.
+		expect []string
+	}{
+		{
+			name:       "fix and ignore",
+			diagnostic: diagnostic(3, true),
+			expect: []string{
+				`<?php
+
+function test() {
+    $a = 1;
+    // phpcs:ignore Generic.Files.LineLength
+    $b=2;
+}
+`,
+				`<?php
+
+function test() {
+    // phpcs:ignore Squiz.WhiteSpace.OperatorSpacing
+    $a=1;
+    // phpcs:ignore Generic.Files.LineLength
+    $b=2;
+}
+`,
+			},
+		},
+		{
+			name:       "existing ignore comment",
+			diagnostic: diagnostic(5, false),
+			expect: []string{
+				`<?php
+
+function test() {
+    $a=1;
+    // phpcs:ignore Generic.Files.LineLength, Squiz.WhiteSpace.OperatorSpacing
+    $b=2;
+}
+`,
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			p := params(t, input)
+			p.Diagnostics = []protocol.Diagnostic{scenario.diagnostic}
+			p.Phpcbf = &fakeFixer{fix: func(code string) string {
+				return strings.ReplaceAll(code, "=", " = ")
+			}}
+
+			actions, err := codeactions.NewPhpcs().Provide(p)
+			require.NoError(t, err)
+			require.Len(t, actions, len(scenario.expect))
+
+			for i, action := range actions {
+				edits := action.Edit.Changes[protocol.DocumentURI("file://"+p.Path)]
+				require.Equal(t, scenario.expect[i], applyEdits(input, edits))
+			}
+		})
+	}
+}
@@ -0,0 +1,446 @@
+package codeactions
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/laytan/php-parser/pkg/ast"
+	"github.com/laytan/php-parser/pkg/visitor"
+	"github.com/laytan/php-parser/pkg/visitor/traverser"
+	"github.com/laytan/phpls/pkg/nodeident"
+	"github.com/laytan/phpls/pkg/position"
+	"golang.org/x/exp/slices"
+)
+
+var identifierRgx = regexp.MustCompile(`^[a-zA-Z_\x80-\xff][a-zA-Z0-9_\x80-\xff]*$`)
+
+// selection returns the byte offsets of the requested range, with surrounding
+// whitespace trimmed off.
+func (p *Params) selection() (start int, end int) {
+	start = position.FromLSPPosition(p.Content, p.Range.Start)
+	end = position.FromLSPPosition(p.Content, p.Range.End)
+
+	for start < end && unicode.IsSpace(rune(p.Content[start])) {
+		start++
+	}
+
+	for end > start && unicode.IsSpace(rune(p.Content[end-1])) {
+		end--
+	}
+
+	return start, end
+}
+
+// indentAt returns the whitespace at the start of the line containing offset.
+func (p *Params) indentAt(offset int) string {
+	lineStart := strings.LastIndexByte(p.Content[:offset], '\n') + 1
+	line := p.Content[lineStart:]
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+}
+
+func (p *Params) newline() string {
+	if strings.Contains(p.Content, "\r\n") {
+		return "\r\n"
+	}
+
+	return "\n"
+}
+
+func (p *Params) text(node ast.Vertex) string {
+	pos := node.GetPosition()
+	return p.Content[pos.StartPos:pos.EndPos]
+}
+
+// enclosing returns the nodes that contain the given byte range, from the
+// root to the innermost node.
+func enclosing(root *ast.Root, start, end int) []ast.Vertex {
+	v := &enclosingVisitor{start: start, end: end}
+	root.Accept(traverser.NewTraverser(v))
+	return v.path
+}
+
+type enclosingVisitor struct {
+	visitor.Null
+
+	start int
+	end   int
+	path  []ast.Vertex
+}
+
+func (v *enclosingVisitor) EnterNode(node ast.Vertex) bool {
+	pos := node.GetPosition()
+	if pos == nil {
+		return true
+	}
+
+	if pos.StartPos > v.start || pos.EndPos < v.end {
+		return false
+	}
+
+	v.path = append(v.path, node)
+	return true
+}
+
+// stmtList returns the statements that are directly part of the node, or
+// false if the node does not hold a list of statements.
+func stmtList(node ast.Vertex) ([]ast.Vertex, bool) {
+	switch typed := node.(type) {
+	case *ast.Root:
+		return typed.Stmts, true
+	case *ast.StmtNamespace:
+		return typed.Stmts, true
+	case *ast.StmtStmtList:
+		return typed.Stmts, true
+	case *ast.StmtFunction:
+		return typed.Stmts, true
+	case *ast.ExprClosure:
+		return typed.Stmts, true
+	case *ast.StmtCase:
+		return typed.Stmts, true
+	case *ast.StmtDefault:
+		return typed.Stmts, true
+	case *ast.StmtTry:
+		return typed.Stmts, true
+	case *ast.StmtCatch:
+		return typed.Stmts, true
+	case *ast.StmtFinally:
+		return typed.Stmts, true
+	default:
+		return nil, false
+	}
+}
+
+func isFunctionLike(node ast.Vertex) bool {
+	switch node.(type) {
+	case *ast.StmtFunction, *ast.StmtClassMethod, *ast.ExprClosure, *ast.ExprArrowFunction:
+		return true
+	default:
+		return false
+	}
+}
+
+func isClassLike(node ast.Vertex) bool {
+	switch node.(type) {
+	case *ast.StmtClass, *ast.StmtTrait, *ast.StmtEnum, *ast.StmtInterface:
+		return true
+	default:
+		return false
+	}
+}
+
+func classStmts(node ast.Vertex) ([]ast.Vertex, bool) {
+	switch typed := node.(type) {
+	case *ast.StmtClass:
+		return typed.Stmts, true
+	case *ast.StmtTrait:
+		return typed.Stmts, true
+	case *ast.StmtEnum:
+		return typed.Stmts, true
+	case *ast.StmtInterface:
+		return typed.Stmts, true
+	default:
+		return nil, false
+	}
+}
+
+// variableScope returns the index of the innermost node in path that has its
+// own variable scope (the root or a function-like), or -1 if the path is
+// inside a class-like but not in one of its methods.
+func variableScope(path []ast.Vertex) int {
+	for i := len(path) - 1; i >= 0; i-- {
+		switch {
+		case isFunctionLike(path[i]):
+			return i
+		case isClassLike(path[i]):
+			return -1
+		}
+	}
+
+	return 0
+}
+
+func isExpr(node ast.Vertex) bool {
+	t := node.GetType()
+	switch {
+	case t == ast.TypeExprArrayItem, t == ast.TypeExprClosureUse, t == ast.TypeExprList:
+		return false
+	case t >= ast.TypeExprArray && t <= ast.TypeExprThrow:
+		return true
+	case t == ast.TypeScalarDnumber, t == ast.TypeScalarEncapsed, t == ast.TypeScalarHeredoc,
+		t == ast.TypeScalarLnumber, t == ast.TypeScalarMagicConstant, t == ast.TypeScalarString:
+		return true
+	default:
+		return false
+	}
+}
+
+// uniqueName returns name, or name suffixed with the lowest number that
+// makes it not part of taken.
+func uniqueName(name string, taken []string) string {
+	if !slices.Contains(taken, name) {
+		return name
+	}
+
+	for i := 2; ; i++ {
+		if candidate := fmt.Sprintf("%s%d", name, i); !slices.Contains(taken, candidate) {
+			return candidate
+		}
+	}
+}
+
+// reindent moves the (multi-line) code from the from indentation to the to
+// indentation, the first line is expected to be without indentation.
+func reindent(code, from, to, newline string) string {
+	lines := strings.Split(code, newline)
+	for i, line := range lines {
+		switch {
+		case i == 0:
+			lines[i] = to + line
+		case strings.TrimSpace(line) == "":
+			lines[i] = ""
+		default:
+			lines[i] = to + strings.TrimPrefix(line, from)
+		}
+	}
+
+	return strings.Join(lines, newline)
+}
+
+type varAccess int
+
+const (
+	accessRead varAccess = 1 << iota
+	accessWrite
+)
+
+type varOccurrence struct {
+	node   *ast.ExprVariable
+	name   string
+	access varAccess
+	// The node directly containing the variable.
+	parent ast.Vertex
+}
+
+func (o *varOccurrence) reads() bool {
+	return o.access&accessRead != 0
+}
+
+func (o *varOccurrence) writes() bool {
+	return o.access&accessWrite != 0
+}
+
+// variables returns the occurrences of (non-dynamic) variables in the nodes,
+// in source order.
+//
+// Closures are not entered because they have their own scope, the variables
+// they use are recorded as a read (or a write if used by reference).
+// The parameters of arrow functions are not recorded either.
+func variables(nodes ...ast.Vertex) []*varOccurrence {
+	v := &variablesVisitor{accesses: map[*ast.ExprVariable]varAccess{}}
+	t := traverser.NewTraverser(v)
+	for _, node := range nodes {
+		node.Accept(t)
+	}
+
+	slices.SortStableFunc(v.occurrences, func(a, b *varOccurrence) bool {
+		return a.node.Position.StartPos < b.node.Position.StartPos
+	})
+
+	return v.occurrences
+}
+
+type variablesVisitor struct {
+	visitor.Null
+
+	occurrences []*varOccurrence
+	accesses    map[*ast.ExprVariable]varAccess
+	// Parameters of the arrow functions we are in.
+	shadowed [][]string
+	stack    []ast.Vertex
+}
+
+func (v *variablesVisitor) EnterNode(node ast.Vertex) bool {
+	switch typed := node.(type) {
+	case *ast.StmtFunction, *ast.StmtClassMethod, *ast.StmtClass, *ast.StmtTrait,
+		*ast.StmtInterface, *ast.StmtEnum:
+		return false
+
+	case *ast.ExprClosure:
+		for _, use := range typed.Uses {
+			use := use.(*ast.ExprClosureUse)
+			if variable, ok := use.Var.(*ast.ExprVariable); ok {
+				access := accessRead
+				if use.AmpersandTkn != nil {
+					access |= accessWrite
+				}
+
+				v.record(variable, use, access)
+			}
+		}
+
+		return false
+
+	case *ast.ExprArrowFunction:
+		names := make([]string, 0, len(typed.Params))
+		for _, param := range typed.Params {
+			names = append(names, nodeident.Get(param))
+		}
+
+		v.shadowed = append(v.shadowed, names)
+		v.stack = append(v.stack, node)
+		typed.Expr.Accept(traverser.NewTraverser(v))
+		v.stack = v.stack[:len(v.stack)-1]
+		v.shadowed = v.shadowed[:len(v.shadowed)-1]
+		return false
+
+	case *ast.ExprVariable:
+		if _, ok := typed.Name.(*ast.Identifier); ok {
+			access, ok := v.accesses[typed]
+			if !ok {
+				access = accessRead
+			}
+
+			var parent ast.Vertex
+			if len(v.stack) > 0 {
+				parent = v.stack[len(v.stack)-1]
+			}
+
+			v.record(typed, parent, access)
+		}
+
+	case *ast.ExprAssign:
+		v.assigned(typed.Var, accessWrite)
+	case *ast.ExprAssignReference:
+		v.assigned(typed.Var, accessWrite)
+		v.assigned(typed.Expr, accessRead|accessWrite)
+	case *ast.ExprAssignBitwiseAnd:
+		v.assigned(typed.Var, accessRead|accessWrite)
+	case *ast.ExprAssignBitwiseOr:
+		v.assigned(typed.Var, accessRead|accessWrite)
+	case *ast.ExprAssignBitwiseXor:
+		v.assigned(typed.Var, accessRead|accessWrite)
+	case *ast.ExprAssignCoalesce:
+		v.assigned(typed.Var, accessRead|accessWrite)
+	case *ast.ExprAssignConcat:
+		v.assigned(typed.Var, accessRead|accessWrite)
+	case *ast.ExprAssignDiv:
+		v.assigned(typed.Var, accessRead|accessWrite)
+	case *ast.ExprAssignMinus:
+		v.assigned(typed.Var, accessRead|accessWrite)
+	case *ast.ExprAssignMod:
+		v.assigned(typed.Var, accessRead|accessWrite)
+	case *ast.ExprAssignMul:
+		v.assigned(typed.Var, accessRead|accessWrite)
+	case *ast.ExprAssignPlus:
+		v.assigned(typed.Var, accessRead|accessWrite)
+	case *ast.ExprAssignPow:
+		v.assigned(typed.Var, accessRead|accessWrite)
+	case *ast.ExprAssignShiftLeft:
+		v.assigned(typed.Var, accessRead|accessWrite)
+	case *ast.ExprAssignShiftRight:
+		v.assigned(typed.Var, accessRead|accessWrite)
+	case *ast.ExprPreInc:
+		v.assigned(typed.Var, accessRead|accessWrite)
+	case *ast.ExprPreDec:
+		v.assigned(typed.Var, accessRead|accessWrite)
+	case *ast.ExprPostInc:
+		v.assigned(typed.Var, accessRead|accessWrite)
+	case *ast.ExprPostDec:
+		v.assigned(typed.Var, accessRead|accessWrite)
+	case *ast.StmtForeach:
+		v.assigned(typed.Key, accessWrite)
+		v.assigned(typed.Var, accessWrite)
+	case *ast.StmtCatch:
+		v.assigned(typed.Var, accessWrite)
+	case *ast.StmtGlobal:
+		for _, variable := range typed.Vars {
+			v.assigned(variable, accessWrite)
+		}
+	case *ast.StmtStaticVar:
+		v.assigned(typed.Var, accessWrite)
+	case *ast.Parameter:
+		v.assigned(typed.Var, accessWrite)
+	case *ast.ExprClosureUse:
+		v.assigned(typed.Var, accessWrite)
+	}
+
+	v.stack = append(v.stack, node)
+	return true
+}
+
+func (v *variablesVisitor) LeaveNode(node ast.Vertex) {
+	if len(v.stack) > 0 && v.stack[len(v.stack)-1] == node {
+		v.stack = v.stack[:len(v.stack)-1]
+	}
+}
+
+func (v *variablesVisitor) record(node *ast.ExprVariable, parent ast.Vertex, access varAccess) {
+	name := nodeident.Get(node)
+	for _, names := range v.shadowed {
+		if slices.Contains(names, name) {
+			return
+		}
+	}
+
+	v.occurrences = append(v.occurrences, &varOccurrence{
+		node:   node,
+		name:   name,
+		access: access,
+		parent: parent,
+	})
+}
+
+// assigned marks the variable(s) that are assigned to by the target,
+// assigning to an array key or destructuring also counts.
+func (v *variablesVisitor) assigned(target ast.Vertex, access varAccess) {
+	switch typed := target.(type) {
+	case *ast.ExprVariable:
+		v.accesses[typed] = access
+	case *ast.ExprArrayDimFetch:
+		v.assigned(typed.Var, accessRead|accessWrite)
+	case *ast.ExprList:
+		for _, item := range typed.Items {
+			v.assigned(item, access)
+		}
+	case *ast.ExprArray:
+		for _, item := range typed.Items {
+			v.assigned(item, access)
+		}
+	case *ast.ExprArrayItem:
+		v.assigned(typed.Val, access)
+	}
+}
+
+// scopeNodes returns the nodes that make up the variable scope of the given
+// root or function-like node.
+func scopeNodes(scope ast.Vertex) []ast.Vertex {
+	switch typed := scope.(type) {
+	case *ast.Root:
+		return typed.Stmts
+	case *ast.StmtFunction:
+		return append(slices.Clone(typed.Params), typed.Stmts...)
+	case *ast.StmtClassMethod:
+		return append(slices.Clone(typed.Params), typed.Stmt)
+	case *ast.ExprClosure:
+		nodes := append(slices.Clone(typed.Params), typed.Uses...)
+		return append(nodes, typed.Stmts...)
+	case *ast.ExprArrowFunction:
+		return append(slices.Clone(typed.Params), typed.Expr)
+	default:
+		return nil
+	}
+}
+
+// names returns the unique variable names of the occurrences, in order.
+func names(occurrences []*varOccurrence) []string {
+	var res []string
+	for _, occurrence := range occurrences {
+		if !slices.Contains(res, occurrence.name) {
+			res = append(res, occurrence.name)
+		}
+	}
+
+	return res
+}
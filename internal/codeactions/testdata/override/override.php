@@ -0,0 +1,27 @@
+<?php
+
+namespace CodeActions\TestData;
+
+interface Shape
+{
+    public function scale($factor);
+}
+
+class Square implements Shape
+{
+    /**
+     * @param float $factor
+     * @return static
+     */
+    public function scale($factor)
+    {
+        return $this;
+    }
+
+    /**
+     * @param float $size
+     */
+    public function resize($size)
+    {
+    }
+}
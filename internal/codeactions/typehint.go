@@ -0,0 +1,223 @@
+package codeactions
+
+import (
+	"strings"
+
+	"github.com/laytan/phpls/pkg/phpdoxer"
+	"github.com/laytan/phpls/pkg/phpversion"
+	"golang.org/x/exp/slices"
+)
+
+// hintPosition is where a type hint is used, some types are only valid in
+// specific positions.
+type hintPosition int
+
+const (
+	hintParam hintPosition = iota
+	hintReturn
+	hintProperty
+)
+
+// typeHint converts the doc type into the equivalent native type hint, if it
+// can be expressed as one in the given php version and position.
+//
+// Types that are more specific than what the native hints allow (int<0, max>,
+// 'literal', array<int, string>) are widened to their native type.
+func typeHint(typ phpdoxer.Type, phpv *phpversion.PHPVersion, pos hintPosition) (string, bool) {
+	parts, ok := hintParts(typ, phpv, pos)
+	if !ok || len(parts) == 0 {
+		return "", false
+	}
+
+	var nullable bool
+	if i := slices.Index(parts, "null"); i != -1 && len(parts) > 1 {
+		parts = slices.Delete(parts, i, i+1)
+		nullable = true
+	}
+
+	if slices.Contains(parts, "mixed") {
+		if len(parts) > 1 {
+			return "", false
+		}
+
+		return "mixed", true
+	}
+
+	if len(parts) == 1 {
+		switch {
+		case parts[0] == "null":
+			return "null", !nullable && atLeast(phpv, 8, 2)
+		case parts[0] == "false" && !atLeast(phpv, 8, 2):
+			// Standalone false is only allowed since 8.2.
+			parts[0] = "bool"
+		}
+
+		switch {
+		case !nullable:
+			return parts[0], true
+		case parts[0] == "void" || parts[0] == "never":
+			return "", false
+		case strings.Contains(parts[0], "&"):
+			// Nullable intersections need DNF types.
+			return "", false
+		case atLeast(phpv, 7, 1):
+			return "?" + parts[0], true
+		default:
+			return "", false
+		}
+	}
+
+	if !atLeast(phpv, 8, 0) {
+		return "", false
+	}
+
+	for _, part := range parts {
+		if part == "void" || part == "never" || strings.Contains(part, "&") {
+			return "", false
+		}
+	}
+
+	if nullable {
+		parts = append(parts, "null")
+	}
+
+	return strings.Join(parts, "|"), true
+}
+
+// hintParts returns the native types the union of typ consists of.
+func hintParts(typ phpdoxer.Type, phpv *phpversion.PHPVersion, pos hintPosition) ([]string, bool) {
+	switch typed := typ.(type) {
+	case *phpdoxer.TypeUnion:
+		left, ok := hintParts(typed.Left, phpv, pos)
+		if !ok {
+			return nil, false
+		}
+
+		right, ok := hintParts(typed.Right, phpv, pos)
+		if !ok {
+			return nil, false
+		}
+
+		for _, part := range right {
+			if !slices.Contains(left, part) {
+				left = append(left, part)
+			}
+		}
+
+		if slices.Contains(left, "true") && slices.Contains(left, "false") {
+			left = append(left, "bool")
+		}
+
+		// bool covers true and false.
+		if slices.Contains(left, "bool") {
+			parts := left[:0]
+			for _, part := range left {
+				if part != "true" && part != "false" {
+					parts = append(parts, part)
+				}
+			}
+
+			left = parts
+		}
+
+		return left, true
+
+	case *phpdoxer.TypePrecedence:
+		return hintParts(typed.Type, phpv, pos)
+
+	case *phpdoxer.TypeIntersection:
+		if !atLeast(phpv, 8, 1) {
+			return nil, false
+		}
+
+		left, ok := hintParts(typed.Left, phpv, pos)
+		if !ok || len(left) != 1 || !isClassHint(left[0]) {
+			return nil, false
+		}
+
+		right, ok := hintParts(typed.Right, phpv, pos)
+		if !ok || len(right) != 1 || !isClassHint(strings.Split(right[0], "&")[0]) {
+			return nil, false
+		}
+
+		return []string{left[0] + "&" + right[0]}, true
+
+	case *phpdoxer.TypeBool:
+		switch {
+		case typed.Accepts == phpdoxer.BoolAcceptsFalse && atLeast(phpv, 8, 0):
+			return []string{"false"}, true
+		case typed.Accepts == phpdoxer.BoolAcceptsTrue && atLeast(phpv, 8, 2):
+			return []string{"true"}, true
+		default:
+			return []string{"bool"}, true
+		}
+
+	case *phpdoxer.TypeArrayKey:
+		return []string{"int", "string"}, atLeast(phpv, 8, 0)
+
+	case *phpdoxer.TypeScalar:
+		return []string{"int", "float", "string", "bool"}, atLeast(phpv, 8, 0)
+	}
+
+	hint, ok := singleHint(typ, phpv, pos)
+	if !ok {
+		return nil, false
+	}
+
+	return []string{hint}, true
+}
+
+func singleHint(typ phpdoxer.Type, phpv *phpversion.PHPVersion, pos hintPosition) (string, bool) {
+	switch typed := typ.(type) {
+	case *phpdoxer.TypeMixed:
+		return "mixed", atLeast(phpv, 8, 0)
+	case *phpdoxer.TypeNull:
+		return "null", true
+	case *phpdoxer.TypeString, *phpdoxer.TypeStringLiteral:
+		return "string", true
+	case *phpdoxer.TypeInt, *phpdoxer.TypeIntLiteral, *phpdoxer.TypeIntMask, *phpdoxer.TypeIntMaskOf:
+		return "int", true
+	case *phpdoxer.TypeFloat, *phpdoxer.TypeFloatLiteral:
+		return "float", true
+	case *phpdoxer.TypeArray, *phpdoxer.TypeArrayShape:
+		return "array", true
+	case *phpdoxer.TypeIterable:
+		return "iterable", atLeast(phpv, 7, 1)
+	case *phpdoxer.TypeCallable:
+		return "callable", pos != hintProperty
+	case *phpdoxer.TypeObject:
+		return "object", atLeast(phpv, 7, 2)
+	case *phpdoxer.TypeVoid:
+		return "void", pos == hintReturn && atLeast(phpv, 7, 1)
+	case *phpdoxer.TypeNever:
+		return "never", pos == hintReturn && atLeast(phpv, 8, 1)
+	case *phpdoxer.TypeClassLike:
+		switch typed.Name {
+		case "$this", "static":
+			if pos == hintReturn && atLeast(phpv, 8, 0) {
+				return "static", true
+			}
+
+			return "self", true
+		default:
+			return typed.Name, true
+		}
+	default:
+		return "", false
+	}
+}
+
+func isClassHint(hint string) bool {
+	switch hint {
+	case "mixed", "null", "string", "int", "float", "bool", "true", "false", "array", "iterable",
+		"callable", "object", "void", "never", "static", "self":
+		return false
+	default:
+		return true
+	}
+}
+
+// atLeast returns whether phpv is at least the given major and minor version.
+func atLeast(phpv *phpversion.PHPVersion, major, minor uint8) bool {
+	return !(&phpversion.PHPVersion{Major: major, Minor: minor}).IsHigherThan(phpv)
+}
@@ -0,0 +1,488 @@
+package compat
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/laytan/php-parser/pkg/ast"
+	"github.com/laytan/php-parser/pkg/token"
+	"github.com/laytan/php-parser/pkg/visitor"
+	"github.com/laytan/php-parser/pkg/visitor/traverser"
+	"github.com/laytan/phpls/internal/index"
+	"github.com/laytan/phpls/internal/wrkspc"
+	"github.com/laytan/phpls/pkg/fqn"
+	"github.com/laytan/phpls/pkg/nodeident"
+	"github.com/laytan/phpls/pkg/phpversion"
+	"github.com/laytan/phpls/pkg/set"
+	"github.com/laytan/phpls/pkg/stubs/stubtransform"
+)
+
+const (
+	CodeSyntax   = "unsupported-syntax"
+	CodeFunction = "unavailable-function"
+	CodeClass    = "unavailable-class"
+	CodeConstant = "unavailable-constant"
+)
+
+var (
+	php71 = &phpversion.PHPVersion{Major: 7, Minor: 1}
+	php72 = &phpversion.PHPVersion{Major: 7, Minor: 2}
+	php74 = &phpversion.PHPVersion{Major: 7, Minor: 4}
+	php80 = &phpversion.PHPVersion{Major: 8, Minor: 0}
+	php81 = &phpversion.PHPVersion{Major: 8, Minor: 1}
+	php82 = &phpversion.PHPVersion{Major: 8, Minor: 2}
+)
+
+type rooter interface {
+	Root() *ast.Root
+	Path() string
+}
+
+type Violation struct {
+	// The node using the unavailable syntax or symbol.
+	Node    ast.Vertex
+	Code    string
+	Message string
+}
+
+func (v *Violation) Line() int {
+	return v.Node.GetPosition().StartLine
+}
+
+type Options struct {
+	// The lowest PHP version the code should be compatible with.
+	MinVersion *phpversion.PHPVersion
+	// The highest PHP version the code should be compatible with.
+	MaxVersion *phpversion.PHPVersion
+	// The availability of the built-in symbols, keyed by their lowercased FQN.
+	Availability map[string]*stubtransform.Availability
+}
+
+// Diagnose finds usages of syntax that is newer than the minimum version, and
+// built-in functions, classes and constants that are not available in either
+// the minimum or maximum version.
+//
+// Symbols declared in the project are not reported, so polyfills are respected.
+func Diagnose(root rooter, opts Options) []*Violation {
+	fqnt := fqn.NewTraverser()
+	root.Root().Accept(traverser.NewTraverser(fqnt))
+
+	t := &compatTraverser{
+		opts:     opts,
+		fqnt:     fqnt,
+		declared: index.Declarations(root.Path(), root.Root()),
+	}
+	root.Root().Accept(traverser.NewTraverser(t))
+
+	return t.violations
+}
+
+type compatTraverser struct {
+	visitor.Null
+
+	opts     Options
+	fqnt     *fqn.Traverser
+	declared *set.Set[string]
+
+	violations []*Violation
+}
+
+func (t *compatTraverser) EnterNode(node ast.Vertex) bool {
+	switch typedNode := node.(type) {
+	case *ast.ExprNew:
+		t.class(typedNode.Class)
+
+	case *ast.ExprInstanceOf:
+		t.class(typedNode.Class)
+
+	case *ast.StmtCatch:
+		if len(typedNode.Types) > 1 {
+			t.syntax(node, "Catching multiple exception types", php71)
+		}
+
+		if typedNode.Var == nil {
+			t.syntax(node, "Catching an exception without a variable", php80)
+		}
+
+		for _, typ := range typedNode.Types {
+			t.class(typ)
+		}
+
+	case *ast.StmtClass:
+		if nodeident.HasModifier(typedNode.Modifiers, "readonly") {
+			t.syntax(node, "A readonly class", php82)
+		}
+
+		if typedNode.Extends != nil {
+			t.class(typedNode.Extends)
+		}
+
+		for _, impl := range typedNode.Implements {
+			t.class(impl)
+		}
+
+	case *ast.StmtInterface:
+		for _, ext := range typedNode.Extends {
+			t.class(ext)
+		}
+
+	case *ast.StmtEnum:
+		t.syntax(node, "An enum", php81)
+
+		for _, impl := range typedNode.Implements {
+			t.class(impl)
+		}
+
+	case *ast.StmtTraitUse:
+		for _, trait := range typedNode.Traits {
+			t.class(trait)
+		}
+
+	case *ast.StmtClassConstList:
+		if nodeident.HasModifier(typedNode.Modifiers, "public") ||
+			nodeident.HasModifier(typedNode.Modifiers, "protected") ||
+			nodeident.HasModifier(typedNode.Modifiers, "private") {
+			t.syntax(node, "A class constant visibility modifier", php71)
+		}
+
+		if nodeident.HasModifier(typedNode.Modifiers, "final") {
+			t.syntax(node, "A final class constant", php81)
+		}
+
+	case *ast.Parameter:
+		if len(typedNode.Modifiers) > 0 {
+			t.syntax(node, "Constructor property promotion", php80)
+		}
+
+		if nodeident.HasModifier(typedNode.Modifiers, "readonly") {
+			t.syntax(node, "A readonly property", php81)
+		}
+
+		if _, ok := typedNode.DefaultValue.(*ast.ExprNew); ok {
+			t.syntax(typedNode.DefaultValue, "Using new in an initializer", php81)
+		}
+
+		t.typeHint(typedNode.Type)
+
+	case *ast.StmtPropertyList:
+		if typedNode.Type != nil {
+			t.syntax(node, "A typed property", php74)
+		}
+
+		if nodeident.HasModifier(typedNode.Modifiers, "readonly") {
+			t.syntax(node, "A readonly property", php81)
+		}
+
+		t.typeHint(typedNode.Type)
+
+	case *ast.StmtFunction:
+		t.typeHint(typedNode.ReturnType)
+
+	case *ast.StmtClassMethod:
+		t.typeHint(typedNode.ReturnType)
+
+	case *ast.ExprClosure:
+		t.typeHint(typedNode.ReturnType)
+
+	case *ast.ExprArrowFunction:
+		t.syntax(node, "An arrow function", php74)
+		t.typeHint(typedNode.ReturnType)
+
+	case *ast.ExprList:
+		if typedNode.ListTkn == nil {
+			t.syntax(node, "Short list syntax", php71)
+		}
+
+	case *ast.ExprAssignCoalesce:
+		t.syntax(node, "The null coalescing assignment operator", php74)
+
+	case *ast.ExprArrayItem:
+		if typedNode.EllipsisTkn != nil {
+			t.syntax(node, "Unpacking inside an array", php74)
+		}
+
+	case *ast.ScalarLnumber:
+		t.number(node, typedNode.Value)
+
+		value := strings.ToLower(string(typedNode.Value))
+		if strings.HasPrefix(value, "0o") {
+			t.syntax(node, "Explicit octal notation", php81)
+		}
+
+	case *ast.ScalarDnumber:
+		t.number(node, typedNode.Value)
+
+	case *ast.ExprMatch:
+		t.syntax(node, "A match expression", php80)
+
+	case *ast.ExprNullsafeMethodCall:
+		t.syntax(node, "The nullsafe operator", php80)
+		t.firstClassCallable(node, typedNode.EllipsisTkn)
+
+	case *ast.ExprNullsafePropertyFetch:
+		t.syntax(node, "The nullsafe operator", php80)
+
+	case *ast.ExprThrow:
+		t.syntax(node, "A throw expression", php80)
+
+	case *ast.Argument:
+		if typedNode.Name != nil {
+			t.syntax(node, "A named argument", php80)
+		}
+
+	case *ast.ExprFunctionCall:
+		t.firstClassCallable(node, typedNode.EllipsisTkn)
+		t.function(typedNode.Function)
+
+	case *ast.ExprMethodCall:
+		t.firstClassCallable(node, typedNode.EllipsisTkn)
+
+	case *ast.ExprStaticCall:
+		t.firstClassCallable(node, typedNode.EllipsisTkn)
+		t.class(typedNode.Class)
+
+	case *ast.ExprStaticPropertyFetch:
+		t.class(typedNode.Class)
+
+	case *ast.ExprClassConstFetch:
+		// Foo::class does not need Foo to exist.
+		if !strings.EqualFold(nodeident.Get(typedNode.Const), "class") {
+			t.class(typedNode.Class)
+			break
+		}
+
+		if _, ok := typedNode.Class.(*ast.ExprVariable); ok {
+			t.syntax(node, "Using ::class on an object", php80)
+		}
+
+	case *ast.ExprConstFetch:
+		t.constant(typedNode.Const)
+	}
+
+	return true
+}
+
+func (t *compatTraverser) report(violation *Violation) {
+	t.violations = append(t.violations, violation)
+}
+
+// syntax reports the node if the feature is not available in the minimum version,
+// the maximum version is the version the file is parsed with so it always supports it.
+func (t *compatTraverser) syntax(node ast.Vertex, feature string, since *phpversion.PHPVersion) {
+	if !since.IsHigherThan(t.opts.MinVersion) {
+		return
+	}
+
+	t.report(&Violation{
+		Node: node,
+		Code: CodeSyntax,
+		Message: fmt.Sprintf(
+			"%s requires PHP %s, the minimum PHP version is %s.",
+			feature,
+			short(since),
+			short(t.opts.MinVersion),
+		),
+	})
+}
+
+func (t *compatTraverser) number(node ast.Vertex, value []byte) {
+	if strings.Contains(string(value), "_") {
+		t.syntax(node, "A numeric literal separator", php74)
+	}
+}
+
+// firstClassCallable reports calls like strlen(...), the ellipsis token is
+// only set for those.
+func (t *compatTraverser) firstClassCallable(node ast.Vertex, ellipsis *token.Token) {
+	if ellipsis != nil {
+		t.syntax(node, "First-class callable syntax", php81)
+	}
+}
+
+func (t *compatTraverser) typeHint(hint ast.Vertex) {
+	switch typedHint := hint.(type) {
+	case nil:
+		return
+
+	case *ast.Nullable:
+		t.syntax(hint, "A nullable type", php71)
+		t.typeHint(typedHint.Expr)
+
+	case *ast.Union:
+		t.syntax(hint, "A union type", php80)
+
+		for _, typ := range typedHint.Types {
+			if _, ok := typ.(*ast.Intersection); ok {
+				t.syntax(hint, "A disjunctive normal form type", php82)
+			}
+
+			t.typeHint(typ)
+		}
+
+	case *ast.Intersection:
+		t.syntax(hint, "An intersection type", php81)
+
+		for _, typ := range typedHint.Types {
+			t.typeHint(typ)
+		}
+
+	default:
+		if !fqn.IsResolvable(hint) {
+			return
+		}
+
+		switch strings.ToLower(nodeident.Get(hint)) {
+		case "void":
+			t.syntax(hint, "The void return type", php71)
+		case "iterable":
+			t.syntax(hint, "The iterable type", php71)
+		case "object":
+			t.syntax(hint, "The object type", php72)
+		case "mixed":
+			t.syntax(hint, "The mixed type", php80)
+		case "static":
+			t.syntax(hint, "The static return type", php80)
+		case "never":
+			t.syntax(hint, "The never return type", php81)
+		case "null", "false", "true":
+			t.syntax(hint, "A standalone null, false or true type", php82)
+		case "array", "bool", "callable", "float", "int", "parent", "self", "string":
+		default:
+			t.class(hint)
+		}
+	}
+}
+
+func (t *compatTraverser) class(name ast.Vertex) {
+	if !fqn.IsResolvable(name) {
+		return
+	}
+
+	switch strings.ToLower(nodeident.Get(name)) {
+	case "self", "static", "parent":
+		return
+	}
+
+	qualified := t.fqnt.ResultFor(name)
+	if qualified == nil {
+		return
+	}
+
+	t.symbol(name, CodeClass, "Class "+qualified.String(), qualified)
+}
+
+func (t *compatTraverser) function(name ast.Vertex) {
+	if !fqn.IsResolvable(name) {
+		return
+	}
+
+	qualified, ok := t.global(name)
+	if !ok {
+		return
+	}
+
+	t.symbol(name, CodeFunction, "Function "+qualified.String()+"()", qualified)
+}
+
+func (t *compatTraverser) constant(name ast.Vertex) {
+	if !fqn.IsResolvable(name) {
+		return
+	}
+
+	switch strings.ToLower(nodeident.Get(name)) {
+	case "true", "false", "null":
+		return
+	}
+
+	qualified, ok := t.global(name)
+	if !ok {
+		return
+	}
+
+	t.symbol(name, CodeConstant, "Constant "+qualified.String(), qualified)
+}
+
+// global qualifies the function or constant name, unqualified names fall back
+// to the global namespace like PHP does, false is returned if the namespaced
+// symbol is declared in the project.
+func (t *compatTraverser) global(name ast.Vertex) (*fqn.FQN, bool) {
+	qualified := t.fqnt.ResultFor(name)
+	if qualified == nil {
+		return nil, false
+	}
+
+	ident := nodeident.Get(name)
+	if name.GetType() == ast.TypeNameFullyQualified || strings.Contains(ident, `\`) {
+		return qualified, true
+	}
+
+	if t.isUserDefined(qualified) {
+		return nil, false
+	}
+
+	return fqn.New(`\` + ident), true
+}
+
+// symbol reports the symbol if it is not available in the minimum or maximum version.
+func (t *compatTraverser) symbol(node ast.Vertex, code string, display string, qualified *fqn.FQN) {
+	availability, ok := t.opts.Availability[strings.ToLower(qualified.String())]
+	if !ok || !availability.IsBounded() {
+		return
+	}
+
+	version := t.opts.MinVersion
+	if availability.AvailableIn(version) {
+		version = t.opts.MaxVersion
+		if availability.AvailableIn(version) {
+			return
+		}
+	}
+
+	// Polyfills.
+	if t.isUserDefined(qualified) {
+		return
+	}
+
+	t.report(&Violation{
+		Node: node,
+		Code: code,
+		Message: fmt.Sprintf(
+			"%s is not available in PHP %s, %s.",
+			display,
+			short(version),
+			reason(availability, version),
+		),
+	})
+}
+
+// isUserDefined returns whether the symbol is declared in this file or a
+// file inside the project, instead of the stubs.
+func (t *compatTraverser) isUserDefined(qualified *fqn.FQN) bool {
+	if t.declared.Has(qualified.String()) {
+		return true
+	}
+
+	root := wrkspc.Current.Root()
+	for _, node := range index.Current.FindAll(qualified) {
+		if strings.HasPrefix(node.Path, root) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func reason(availability *stubtransform.Availability, version *phpversion.PHPVersion) string {
+	switch {
+	case availability.Since != nil && availability.Since.IsHigherThan(version):
+		return "it was added in PHP " + short(availability.Since)
+	case availability.Removed != nil && !availability.Removed.IsHigherThan(version):
+		return "it was removed in PHP " + short(availability.Removed)
+	default:
+		return "it is only available up to PHP " + short(availability.To)
+	}
+}
+
+func short(version *phpversion.PHPVersion) string {
+	return fmt.Sprintf("%d.%d", version.Major, version.Minor)
+}
@@ -0,0 +1,141 @@
+package compat_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/laytan/phpls/internal/compat"
+	"github.com/laytan/phpls/internal/config"
+	"github.com/laytan/phpls/internal/index"
+	"github.com/laytan/phpls/internal/project"
+	"github.com/laytan/phpls/internal/wrkspc"
+	"github.com/laytan/phpls/pkg/functional"
+	"github.com/laytan/phpls/pkg/pathutils"
+	"github.com/laytan/phpls/pkg/phpversion"
+	"github.com/laytan/phpls/pkg/stubs/stubtransform"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(
+		m,
+		// The cache size logger.
+		goleak.IgnoreTopFunction("github.com/laytan/phpls/internal/wrkspc.New.func1"),
+	)
+}
+
+func TestDiagnose(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.Join(pathutils.Root(), "internal", "compat", "testdata")
+
+	err := setup(root, phpversion.EightOne())
+	require.NoError(t, err)
+
+	php72 := &phpversion.PHPVersion{Major: 7, Minor: 2}
+	php80 := &phpversion.PHPVersion{Major: 8, Minor: 0}
+
+	availability := map[string]*stubtransform.Availability{
+		`\stringable`:                  {Since: php80},
+		`\array_is_list`:               {Since: phpversion.EightOne()},
+		`\each`:                        {Removed: php80},
+		`\str_contains`:                {Since: php80},
+		`\php_float_epsilon`:           {Since: php72},
+		`\filter_flag_scheme_required`: {To: &phpversion.PHPVersion{Major: 7, Minor: 4}},
+		`\strlen`:                      {},
+	}
+
+	format := func(v *compat.Violation) string {
+		return fmt.Sprintf("%d %s %s", v.Line(), v.Code, v.Message)
+	}
+
+	expected := []string{
+		`7 unsupported-syntax An enum requires PHP 8.1, the minimum PHP version is 7.4.`,
+		`12 unavailable-class Class \Stringable is not available in PHP 7.4, it was added in PHP 8.0.`,
+		`15 unsupported-syntax Constructor property promotion requires PHP 8.0, the minimum PHP version is 7.4.`,
+		`15 unsupported-syntax A readonly property requires PHP 8.1, the minimum PHP version is 7.4.`,
+		`16 unsupported-syntax Constructor property promotion requires PHP 8.0, the minimum PHP version is 7.4.`,
+		`22 unsupported-syntax The nullsafe operator requires PHP 8.0, the minimum PHP version is 7.4.`,
+		`26 unsupported-syntax The mixed type requires PHP 8.0, the minimum PHP version is 7.4.`,
+		`26 unsupported-syntax A union type requires PHP 8.0, the minimum PHP version is 7.4.`,
+		`28 unsupported-syntax First-class callable syntax requires PHP 8.1, the minimum PHP version is 7.4.`,
+		`32 unsupported-syntax A match expression requires PHP 8.0, the minimum PHP version is 7.4.`,
+		`33 unavailable-function Function \array_is_list() is not available in PHP 7.4, it was added in PHP 8.1.`,
+		`34 unavailable-function Function \each() is not available in PHP 8.1, it was removed in PHP 8.0.`,
+		`38 unavailable-constant Constant \FILTER_FLAG_SCHEME_REQUIRED is not available in PHP 8.1, it is only available up to PHP 7.4.`,
+		`43 unsupported-syntax Catching an exception without a variable requires PHP 8.0, the minimum PHP version is 7.4.`,
+		`44 unsupported-syntax Using ::class on an object requires PHP 8.0, the minimum PHP version is 7.4.`,
+		`42 unavailable-class Class \Stringable is not available in PHP 7.4, it was added in PHP 8.0.`,
+		`52 unsupported-syntax A final class constant requires PHP 8.1, the minimum PHP version is 7.4.`,
+	}
+
+	violations := compat.Diagnose(
+		wrkspc.NewRooter(filepath.Join(root, "compat.php")),
+		compat.Options{
+			MinVersion:   &phpversion.PHPVersion{Major: 7, Minor: 4},
+			MaxVersion:   phpversion.EightOne(),
+			Availability: availability,
+		},
+	)
+	require.Equal(t, expected, functional.Map(violations, format))
+
+	violations = compat.Diagnose(
+		wrkspc.NewRooter(filepath.Join(root, "compat.php")),
+		compat.Options{
+			MinVersion:   phpversion.EightOne(),
+			MaxVersion:   phpversion.EightOne(),
+			Availability: availability,
+		},
+	)
+	require.Equal(
+		t,
+		[]string{
+			`34 unavailable-function Function \each() is not available in PHP 8.1, it was removed in PHP 8.0.`,
+			`38 unavailable-constant Constant \FILTER_FLAG_SCHEME_REQUIRED is not available in PHP 8.1, it is only available up to PHP 7.4.`,
+		},
+		functional.Map(violations, format),
+	)
+
+	violations = compat.Diagnose(
+		wrkspc.NewRooter(filepath.Join(root, "compat.php")),
+		compat.Options{
+			MinVersion:   &phpversion.PHPVersion{Major: 7, Minor: 0},
+			MaxVersion:   phpversion.EightOne(),
+			Availability: availability,
+		},
+	)
+	constants := []string{}
+	for _, v := range violations {
+		if v.Line() >= 50 {
+			constants = append(constants, format(v))
+		}
+	}
+	require.Equal(
+		t,
+		[]string{
+			`52 unsupported-syntax A class constant visibility modifier requires PHP 7.1, the minimum PHP version is 7.0.`,
+			`52 unsupported-syntax A final class constant requires PHP 8.1, the minimum PHP version is 7.0.`,
+			`53 unsupported-syntax A class constant visibility modifier requires PHP 7.1, the minimum PHP version is 7.0.`,
+		},
+		constants,
+	)
+}
+
+func setup(root string, phpv *phpversion.PHPVersion) error {
+	config.Current = config.Default()
+	index.Current = index.New(phpv)
+	wrkspc.Current = wrkspc.New(
+		phpv,
+		root,
+		filepath.Join(pathutils.Root(), "third_party", "phpstorm-stubs"),
+	)
+
+	p := project.New()
+	if err := p.ParseWithoutProgress(); err != nil {
+		return fmt.Errorf("[compat_test.setup]: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+<?php
+
+namespace Compat\TestData;
+
+use Stringable;
+
+enum Suit
+{
+    case Hearts;
+}
+
+class Name implements Stringable
+{
+    public function __construct(
+        private readonly string $name,
+        private ?Name $parent = null,
+    ) {
+    }
+
+    public function __toString(): string
+    {
+        return $this->parent?->name . $this->name;
+    }
+}
+
+function compat(int|string $value, Name $name): mixed
+{
+    $callable = strlen(...);
+    $fn = fn ($x) => $x;
+    $value ??= 1_000;
+
+    $result = match ($value) {
+        1 => array_is_list([]),
+        default => each($value),
+    };
+
+    if (str_contains('abc', 'a')) {
+        return \FILTER_FLAG_SCHEME_REQUIRED;
+    }
+
+    try {
+        return new \Stringable();
+    } catch (\Exception) {
+        return $name::class;
+    }
+
+    return PHP_FLOAT_EPSILON;
+}
+
+class Constants
+{
+    final public const A = 1;
+    protected const B = 2;
+}
@@ -280,6 +280,27 @@ func setComputed(cfg *Schema) {
 		os.Exit(invalid)
 	}
 	cfg.PhpVersion = v
+
+	cfg.PhpMinVersion = v
+	if cfg.Php.MinVersion != "" {
+		minv, ok := phpversion.FromString(cfg.Php.MinVersion)
+		if !ok {
+			_, _ = fmt.Fprintf(os.Stderr, "Invalid minimum PHP Version string %q", cfg.Php.MinVersion)
+			os.Exit(invalid)
+		}
+
+		if minv.IsHigherThan(v) {
+			_, _ = fmt.Fprintf(
+				os.Stderr,
+				"Minimum PHP Version %q is higher than the PHP Version %q",
+				cfg.Php.MinVersion,
+				cfg.Php.Version,
+			)
+			os.Exit(invalid)
+		}
+
+		cfg.PhpMinVersion = minv
+	}
 }
 
 func configDirs() (dirs []string) {
@@ -12,27 +12,55 @@ const (
 	DiagnosticsOnChange DiagnosticsMethod = "ON_CHANGE"
 )
 
+type DiagnosticsSeverity string
+
+const (
+	DiagnosticsSeverityError       DiagnosticsSeverity = "ERROR"
+	DiagnosticsSeverityWarning     DiagnosticsSeverity = "WARNING"
+	DiagnosticsSeverityInformation DiagnosticsSeverity = "INFORMATION"
+	DiagnosticsSeverityHint        DiagnosticsSeverity = "HINT"
+)
+
+type FormatterKind string
+
+const (
+	FormatterPhpcbf     FormatterKind = "PHPCBF"
+	FormatterPhpCsFixer FormatterKind = "PHP_CS_FIXER"
+)
+
+type GroupUseStyle string
+
+const (
+	GroupUseSplit GroupUseStyle = "SPLIT"
+	GroupUseMerge GroupUseStyle = "MERGE"
+)
+
 type Schema struct {
 	// TODO: implement usage of this.
 	Php Php `json:"php,omitempty"`
 	// TODO: implement usage of this.
-	Phpcbf             Phpcbf      `json:"phpcbf,omitempty"`
-	Diagnostics        Diagnostics `json:"diagnostics,omitempty"`
-	Extensions         []string    `json:"extensions,omitempty"          uniqueItems:"true" minItems:"1" default:".php"              doc:"File extensions to consider PHP code."                                                    usage:"File extensions to consider PHP code."`
-	IgnoredDirectories []string    `json:"ignored_directories,omitempty" uniqueItems:"true"              default:".git,node_modules" doc:"Directories to ignore completely, use when you have huge directories with non-php files." usage:"Directories to ignore completely, use when you have huge directories with non-php files." flag:"ignored-directories"`
-	Server             Server      `json:"server,omitempty"`
-	Statsviz           Statsviz    `json:"statsviz,omitempty"`
-	CachePath          string      `json:"cache_path,omitempty"                                                                      doc:"Root directory for generated stubs and logs, defaults to the user cache directory."       usage:"Root directory for generated stubs and logs, defaults to the user cache directory."       flag:"cache-path"`
-	DumpConfig         bool        `json:"dump_config,omitempty"                                         default:"false"             doc:"Dump the resolved config before validation, useful for debugging."                        usage:"Dump the resolved config before validation, useful for debugging."                        flag:"dump-config"`
+	Phpcbf             Phpcbf        `json:"phpcbf,omitempty"`
+	PhpCsFixer         PhpCsFixer    `json:"php_cs_fixer,omitempty"`
+	Formatter          FormatterKind `json:"formatter,omitempty" default:"PHPCBF" enum:"PHPCBF,PHP_CS_FIXER" doc:"The formatter to use, either PHPCBF or PHP_CS_FIXER." usage:"The formatter to use, either PHPCBF or PHP_CS_FIXER."`
+	Diagnostics        Diagnostics   `json:"diagnostics,omitempty"`
+	CodeActions        CodeActions   `json:"code_actions,omitempty"`
+	Extensions         []string      `json:"extensions,omitempty"          uniqueItems:"true" minItems:"1" default:".php"              doc:"File extensions to consider PHP code."                                                    usage:"File extensions to consider PHP code."`
+	IgnoredDirectories []string      `json:"ignored_directories,omitempty" uniqueItems:"true"              default:".git,node_modules" doc:"Directories to ignore completely, use when you have huge directories with non-php files." usage:"Directories to ignore completely, use when you have huge directories with non-php files." flag:"ignored-directories"`
+	Server             Server        `json:"server,omitempty"`
+	Statsviz           Statsviz      `json:"statsviz,omitempty"`
+	CachePath          string        `json:"cache_path,omitempty"                                                                      doc:"Root directory for generated stubs and logs, defaults to the user cache directory."       usage:"Root directory for generated stubs and logs, defaults to the user cache directory."       flag:"cache-path"`
+	DumpConfig         bool          `json:"dump_config,omitempty"                                         default:"false"             doc:"Dump the resolved config before validation, useful for debugging."                        usage:"Dump the resolved config before validation, useful for debugging."                        flag:"dump-config"`
 
-	LogsPath   string                 `json:"-" flag:"-"`
-	StubsPath  string                 `json:"-" flag:"-"`
-	PhpVersion *phpversion.PHPVersion `json:"-" flag:"-"`
+	LogsPath      string                 `json:"-" flag:"-"`
+	StubsPath     string                 `json:"-" flag:"-"`
+	PhpVersion    *phpversion.PHPVersion `json:"-" flag:"-"`
+	PhpMinVersion *phpversion.PHPVersion `json:"-" flag:"-"`
 }
 
 type Php struct {
-	Binary  string `json:"binary,omitempty"  default:"php" example:"valet php" doc:"The php binary used to execute external commands like analyzers."         usage:"The php binary used to execute external commands like analyzers."`
-	Version string `json:"version,omitempty"               example:"8.1"       doc:"The PHP version to use when parsing, defaults to the output of 'php -v'." usage:"The PHP version to use when parsing, defaults to the output of 'php -v'." pattern:"^[7-8](\\.[0-9]+){0,2}$"`
+	Binary     string `json:"binary,omitempty"  default:"php" example:"valet php" doc:"The php binary used to execute external commands like analyzers."         usage:"The php binary used to execute external commands like analyzers."`
+	Version    string `json:"version,omitempty"               example:"8.1"       doc:"The PHP version to use when parsing, defaults to the output of 'php -v'." usage:"The PHP version to use when parsing, defaults to the output of 'php -v'." pattern:"^[7-8](\\.[0-9]+){0,2}$"`
+	MinVersion string `json:"min_version,omitempty"        example:"7.4"       doc:"The lowest PHP version the code should be compatible with, defaults to the version." usage:"The lowest PHP version the code should be compatible with, defaults to the version." pattern:"^[7-8](\\.[0-9]+){0,2}$"`
 }
 
 type Phpcbf struct {
@@ -41,10 +69,29 @@ type Phpcbf struct {
 	Standard string   `json:"standard,omitempty"                                    doc:"The PHPCS standard to format according to, NOTE: if this is set, the project level config is ignored." usage:"The PHPCS standard to format according to, NOTE: if this is set, the project level config is ignored."`
 }
 
+type PhpCsFixer struct {
+	Binary []string `json:"binary,omitempty" default:"vendor/bin/php-cs-fixer,php-cs-fixer" doc:"The paths checked, in order, for the PHP-CS-Fixer binary, used by both the formatter and the analyzer." usage:"The paths checked, in order, for the PHP-CS-Fixer binary, used by both the formatter and the analyzer." uniqueItems:"true" minItems:"1"`
+}
+
 type Diagnostics struct {
-	Enabled bool    `json:"enabled,omitempty" default:"true"`
-	Phpstan Phpstan `json:"phpstan,omitempty"`
-	Phpcs   Phpcs   `json:"phpcs,omitempty"`
+	Enabled       bool                  `json:"enabled,omitempty" default:"true"`
+	Phpstan       Phpstan               `json:"phpstan,omitempty"`
+	Phpcs         Phpcs                 `json:"phpcs,omitempty"`
+	Psalm         Psalm                 `json:"psalm,omitempty"`
+	PhpCsFixer    PhpCsFixerDiagnostics `json:"php_cs_fixer,omitempty"`
+	Throws        Throws                `json:"throws,omitempty"`
+	Syntax        Syntax                `json:"syntax,omitempty"`
+	Undefined     Undefined             `json:"undefined,omitempty"`
+	Compatibility Compatibility         `json:"compatibility,omitempty"`
+	Deprecated    Deprecated            `json:"deprecated,omitempty"`
+	Unused        Unused                `json:"unused,omitempty"`
+	Visibility    Visibility            `json:"visibility,omitempty"`
+	Arguments     Arguments             `json:"arguments,omitempty"`
+	Contracts     Contracts             `json:"contracts,omitempty"`
+	Phpdoc        Phpdoc                `json:"phpdoc,omitempty"`
+	Declarations  Declarations          `json:"declarations,omitempty"`
+	Custom        []Custom              `json:"custom,omitempty" doc:"External analyzers that don't need any code to be supported." flag:"-"`
+	Baseline      string                `json:"baseline,omitempty" default:"phpls-baseline.json" doc:"Path, relative to the project root, of the baseline file, diagnostics in the baseline are not reported. Generate it with the phpls.generateBaseline command." usage:"Path, relative to the project root, of the baseline file, diagnostics in the baseline are not reported."`
 }
 
 type Phpstan struct {
@@ -57,11 +104,105 @@ type Phpcs struct {
 	Binary []string `json:"binary,omitempty" default:"vendor/bin/phpcs,phpcs" uniqueItems:"true" minItems:"1" example:"phpcs" doc:"The paths checked, in order, for the PHPCS binary." usage:"The paths checked, in order, for the PHPCS binary."`
 }
 
+type Psalm struct {
+	Analyzer
+	Binary []string `json:"binary,omitempty" default:"vendor/bin/psalm,psalm" uniqueItems:"true" minItems:"1" example:"psalm" doc:"The paths checked, in order, for the Psalm binary." usage:"The paths checked, in order, for the Psalm binary."`
+}
+
+// PhpCsFixerDiagnostics is opt-in and runs on save by default, it runs
+// php-cs-fixer once for every applied rule. The binary is configured with
+// php_cs_fixer.binary. Convert it with Analyzer(...).
+type PhpCsFixerDiagnostics struct {
+	Method  DiagnosticsMethod `json:"method,omitempty"  default:"ON_SAVE" enum:"ON_SAVE,ON_CHANGE" doc:"When to run diagnostics, either ON_SAVE or ON_CHANGE." usage:"When to run diagnostics, either ON_SAVE or ON_CHANGE."`
+	Enabled bool              `json:"enabled,omitempty" default:"false"`
+}
+
+type Throws struct {
+	Analyzer
+}
+
+type Syntax struct {
+	Analyzer
+}
+
+type Undefined struct {
+	Analyzer
+	Severity      DiagnosticsSeverity `json:"severity,omitempty"       default:"ERROR" enum:"ERROR,WARNING,INFORMATION,HINT" doc:"The severity of undefined symbol diagnostics."                                                              usage:"The severity of undefined symbol diagnostics."`
+	SuppressMagic bool                `json:"suppress_magic,omitempty" default:"true"                                        doc:"Don't report undefined members on classes with magic methods like __call, __callStatic, __get and __set." usage:"Don't report undefined members on classes with magic methods like __call, __callStatic, __get and __set."`
+}
+
+type Compatibility struct {
+	SaveAnalyzer
+}
+
+type Deprecated struct {
+	Analyzer
+}
+
+type Unused struct {
+	Analyzer
+}
+
+type Visibility struct {
+	Analyzer
+}
+
+type Arguments struct {
+	SaveAnalyzer
+}
+
+type Contracts struct {
+	SaveAnalyzer
+}
+
+type Phpdoc struct {
+	SaveAnalyzer
+}
+
+type Declarations struct {
+	SaveAnalyzer
+}
+
+// Custom is an external analyzer, the field types are kept primitive because
+// the config loader can't convert named types inside lists.
+type Custom struct {
+	Name        string   `json:"name" minLength:"1" doc:"The name of the analyzer, used as the source of its diagnostics."`
+	Command     []string `json:"command" minItems:"1" doc:"The command and its arguments, {file} is replaced with the path of the file, {stdin} is removed and makes the code be written to the command's stdin. Without {stdin}, unsaved code is written to a temporary file next to the file."`
+	Format      string   `json:"format" enum:"CHECKSTYLE,SARIF,JSON_LINES,REGEX" doc:"The output format of the command, JSON_LINES expects an object per line with the keys file, line, column, end_line, end_column, severity, code and message."`
+	Pattern     string   `json:"pattern,omitempty" doc:"The regular expression for the REGEX format, using the named groups file, line, column, end_line, end_column, severity, code and message."`
+	Method      string   `json:"method,omitempty" enum:"ON_SAVE,ON_CHANGE" doc:"When to run the analyzer, either ON_SAVE or ON_CHANGE, defaults to ON_SAVE."`
+	Error       []string `json:"error,omitempty" doc:"Severities of the command that are reported as errors, defaults to error."`
+	Warning     []string `json:"warning,omitempty" doc:"Severities of the command that are reported as warnings, defaults to warning, unknown severities are also reported as warnings."`
+	Information []string `json:"information,omitempty" doc:"Severities of the command that are reported as information, defaults to info, information and note."`
+	Hint        []string `json:"hint,omitempty" doc:"Severities of the command that are reported as hints, defaults to hint."`
+}
+
 type Analyzer struct {
 	Method  DiagnosticsMethod `json:"method,omitempty"  default:"ON_CHANGE" enum:"ON_SAVE,ON_CHANGE" doc:"When to run diagnostics, either ON_SAVE or ON_CHANGE." usage:"When to run diagnostics, either ON_SAVE or ON_CHANGE."`
 	Enabled bool              `json:"enabled,omitempty" default:"true"`
 }
 
+// SaveAnalyzer is an Analyzer that runs on save by default, for the analyzers
+// that are too heavy to run on every change. Convert it with Analyzer(...).
+type SaveAnalyzer struct {
+	Method  DiagnosticsMethod `json:"method,omitempty"  default:"ON_SAVE" enum:"ON_SAVE,ON_CHANGE" doc:"When to run diagnostics, either ON_SAVE or ON_CHANGE." usage:"When to run diagnostics, either ON_SAVE or ON_CHANGE."`
+	Enabled bool              `json:"enabled,omitempty" default:"true"`
+}
+
+type CodeActions struct {
+	OrganizeImports OrganizeImports `json:"organize_imports,omitempty"`
+	TypeHints       TypeHints       `json:"type_hints,omitempty"`
+}
+
+type OrganizeImports struct {
+	GroupByType bool          `json:"group_by_type,omitempty" default:"true"                                doc:"Group the imports by type, classes first, then functions and then constants, separated by an empty line." usage:"Group the imports by type, classes first, then functions and then constants, separated by an empty line."`
+	GroupUse    GroupUseStyle `json:"group_use,omitempty"     default:"SPLIT" enum:"SPLIT,MERGE" doc:"SPLIT group use statements into a statement per import, or MERGE imports from the same namespace into group use statements." usage:"SPLIT group use statements into a statement per import, or MERGE imports from the same namespace into group use statements."`
+}
+
+type TypeHints struct {
+	RemoveRedundantDocs bool `json:"remove_redundant_docs,omitempty" default:"false" doc:"Remove PHPDoc tags that are redundant after adding type hints from them, tags with a description or a more specific type are kept." usage:"Remove PHPDoc tags that are redundant after adding type hints from them, tags with a description or a more specific type are kept."`
+}
+
 type Server struct {
 	Communication connection.ConnType `json:"communication,omitempty" default:"stdio"          enum:"stdio,ws,tcp" doc:"How to communicate: standard io, web sockets or tcp."             usage:"How to communicate: standard io, web sockets or tcp."`
 	URL           string              `json:"url,omitempty"           default:"127.0.0.1:2001"                     doc:"The URL to use for the websocket or tcp server."                  usage:"The URL to use for the websocket or tcp server."`
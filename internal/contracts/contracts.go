@@ -0,0 +1,535 @@
+package contracts
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/laytan/php-parser/pkg/ast"
+	"github.com/laytan/php-parser/pkg/visitor"
+	"github.com/laytan/php-parser/pkg/visitor/traverser"
+	"github.com/laytan/phpls/internal/index"
+	"github.com/laytan/phpls/internal/symbol"
+	"github.com/laytan/phpls/internal/wrkspc"
+	"github.com/laytan/phpls/pkg/fqn"
+	"github.com/laytan/phpls/pkg/nodeident"
+	"github.com/laytan/phpls/pkg/phprivacy"
+	"github.com/laytan/phpls/pkg/set"
+)
+
+type rooter interface {
+	Root() *ast.Root
+	Path() string
+}
+
+type Kind int
+
+const (
+	KindUnimplemented Kind = iota
+	KindIncompatible
+	KindVisibility
+	KindFinalMethod
+	KindFinalClass
+)
+
+type Violation struct {
+	// The node of the class name, the extended class or the method name.
+	Node ast.Vertex
+	Kind Kind
+	// The fully qualified name of the class the violation is in.
+	FQN *fqn.FQN
+	// The method, empty for extended final classes.
+	Method string
+	// The fully qualified name of the class or interface the method, or the
+	// extended class, is declared in.
+	Parent *fqn.FQN
+	// Why an override is incompatible, like "it accepts fewer parameters".
+	Reason string
+	// The privacy of the overridden method, for visibility violations.
+	Privacy phprivacy.Privacy
+}
+
+func (v *Violation) Message() string {
+	switch v.Kind {
+	case KindUnimplemented:
+		return fmt.Sprintf(
+			"Class %s must implement abstract method %s::%s() or be declared abstract.",
+			v.FQN,
+			v.Parent,
+			v.Method,
+		)
+	case KindIncompatible:
+		return fmt.Sprintf(
+			"Declaration of %s::%s() must be compatible with %s::%s(), %s.",
+			v.FQN,
+			v.Method,
+			v.Parent,
+			v.Method,
+			v.Reason,
+		)
+	case KindVisibility:
+		weaker := ""
+		if v.Privacy != phprivacy.PrivacyPublic {
+			weaker = " or weaker"
+		}
+
+		return fmt.Sprintf(
+			"Access level to %s::%s() must be %s (as in class %s)%s.",
+			v.FQN,
+			v.Method,
+			v.Privacy,
+			v.Parent,
+			weaker,
+		)
+	case KindFinalMethod:
+		return fmt.Sprintf("Cannot override final method %s::%s().", v.Parent, v.Method)
+	default:
+		return fmt.Sprintf("Class %s cannot extend final class %s.", v.FQN, v.Parent)
+	}
+}
+
+func (v *Violation) Code() string {
+	switch v.Kind {
+	case KindUnimplemented:
+		return "unimplemented-method"
+	case KindIncompatible:
+		return "incompatible-override"
+	case KindVisibility:
+		return "override-visibility"
+	case KindFinalMethod:
+		return "final-override"
+	default:
+		return "final-extend"
+	}
+}
+
+func (v *Violation) Line() int {
+	return v.Node.GetPosition().StartLine
+}
+
+// Diagnose checks the classes in the root against the classes and interfaces
+// they inherit from.
+//
+// It reports concrete classes that don't implement every abstract or interface
+// method, overriding methods that are incompatible with the overridden method,
+// overridden final methods and extended final classes.
+//
+// Types are compared using the native type hints, classes that can't be
+// resolved are assumed to be compatible.
+func Diagnose(root rooter) []*Violation {
+	t := &contractsTraverser{
+		rooter: root,
+		fqnts:  make(map[*ast.Root]*fqn.Traverser),
+	}
+	root.Root().Accept(traverser.NewTraverser(t))
+
+	return t.violations
+}
+
+type contractsTraverser struct {
+	visitor.Null
+
+	rooter rooter
+	// Cached fqn traversers of the files of inherited classes.
+	fqnts map[*ast.Root]*fqn.Traverser
+
+	violations []*Violation
+}
+
+func (t *contractsTraverser) StmtClass(node *ast.StmtClass) {
+	// Anonymous classes don't have a name to resolve members with.
+	if node.Name == nil {
+		return
+	}
+
+	cls := symbol.NewClassLike(t.rooter, node)
+	t.extends(node, cls)
+
+	for _, method := range cls.FindMethods(false) {
+		t.override(cls, method)
+	}
+
+	if !cls.IsAbstract() {
+		t.implemented(node, cls)
+	}
+}
+
+func (t *contractsTraverser) extends(node *ast.StmtClass, cls *symbol.ClassLike) {
+	if node.Extends == nil {
+		return
+	}
+
+	parent, err := symbol.NewClassLikeFromName(t.rooter.Root(), node.Extends)
+	if err != nil || !parent.IsFinal() {
+		return
+	}
+
+	t.violations = append(t.violations, &Violation{
+		Node:   node.Extends,
+		Kind:   KindFinalClass,
+		FQN:    cls.GetFQN(),
+		Parent: parent.GetFQN(),
+	})
+}
+
+// implemented checks that every abstract method the class inherits is
+// implemented by the class or one of the classes it inherits from.
+func (t *contractsTraverser) implemented(node *ast.StmtClass, cls *symbol.ClassLike) {
+	type abstract struct {
+		cls    *symbol.ClassLike
+		method *symbol.Method
+	}
+
+	concrete := set.New[string]()
+	var abstracts []abstract
+	collect := func(c *symbol.ClassLike) {
+		isInterface := c.Kind() == ast.TypeStmtInterface
+		for _, method := range c.FindMethods(false) {
+			if isInterface || method.IsAbstract() {
+				abstracts = append(abstracts, abstract{cls: c, method: method})
+				continue
+			}
+
+			concrete.Add(strings.ToLower(method.Name()))
+		}
+	}
+
+	collect(cls)
+	iter := cls.InheritsIter()
+	for inhCls, done, err := iter(); !done; inhCls, done, err = iter() {
+		if err != nil {
+			continue
+		}
+
+		collect(inhCls)
+	}
+
+	for _, a := range abstracts {
+		name := strings.ToLower(a.method.Name())
+		if concrete.Has(name) {
+			continue
+		}
+
+		// Report every method once, even if multiple interfaces declare it.
+		concrete.Add(name)
+
+		t.violations = append(t.violations, &Violation{
+			Node:   node.Name,
+			Kind:   KindUnimplemented,
+			FQN:    cls.GetFQN(),
+			Method: a.method.Name(),
+			Parent: a.cls.GetFQN(),
+		})
+	}
+}
+
+// override checks the method against the method it overrides, if any.
+func (t *contractsTraverser) override(cls *symbol.ClassLike, method *symbol.Method) {
+	iter := cls.InheritsIter()
+	for inhCls, done, err := iter(); !done; inhCls, done, err = iter() {
+		if err != nil {
+			continue
+		}
+
+		// Methods of used traits are copied into the class, not overridden.
+		if inhCls.Kind() == ast.TypeStmtTrait {
+			continue
+		}
+
+		parent := inhCls.FindMethod(func(m *symbol.Method) bool {
+			return strings.EqualFold(m.Name(), method.Name()) &&
+				m.Privacy() != phprivacy.PrivacyPrivate
+		})
+		if parent == nil {
+			continue
+		}
+
+		// The first overridden method is compared, which in turn is compared
+		// with the methods it overrides.
+		t.compare(cls, method, inhCls, parent)
+		return
+	}
+}
+
+func (t *contractsTraverser) compare(
+	cls *symbol.ClassLike,
+	method *symbol.Method,
+	parentCls *symbol.ClassLike,
+	parent *symbol.Method,
+) {
+	violation := &Violation{
+		Node:   method.Node().Name,
+		FQN:    cls.GetFQN(),
+		Method: method.Name(),
+		Parent: parentCls.GetFQN(),
+	}
+
+	if parent.IsFinal() {
+		violation.Kind = KindFinalMethod
+		t.violations = append(t.violations, violation)
+		return
+	}
+
+	if !method.CanBeAccessedFrom(parent.Privacy()) {
+		violation.Kind = KindVisibility
+		violation.Privacy = parent.Privacy()
+		t.violations = append(t.violations, violation)
+		return
+	}
+
+	// Constructors only have to be compatible with abstract constructors.
+	if strings.EqualFold(method.Name(), "__construct") &&
+		!parent.IsAbstract() && parentCls.Kind() != ast.TypeStmtInterface {
+		return
+	}
+
+	if reason := t.incompatibility(cls, method, parentCls, parent); reason != "" {
+		violation.Kind = KindIncompatible
+		violation.Reason = reason
+		t.violations = append(t.violations, violation)
+	}
+}
+
+// incompatibility returns why the method is incompatible with the parent
+// method, or an empty string when it is compatible.
+func (t *contractsTraverser) incompatibility(
+	cls *symbol.ClassLike,
+	method *symbol.Method,
+	parentCls *symbol.ClassLike,
+	parent *symbol.Method,
+) string {
+	switch {
+	case method.IsStatic() && !parent.IsStatic():
+		return "it is static"
+	case !method.IsStatic() && parent.IsStatic():
+		return "it is not static"
+	}
+
+	params := parameters(method.Node())
+	parentParams := parameters(parent.Node())
+	variadic := len(params) > 0 && params[len(params)-1].VariadicTkn != nil
+
+	if !variadic && len(params) < len(parentParams) {
+		return "it accepts fewer parameters"
+	}
+
+	if requiredCount(params) > requiredCount(parentParams) {
+		return "it requires more parameters"
+	}
+
+	for i, parentParam := range parentParams {
+		param := params[len(params)-1]
+		if i < len(params) {
+			param = params[i]
+		}
+
+		// Parameters are contravariant, the parameter has to accept every
+		// type the parent parameter accepts.
+		if !t.compatible(parentCls, parentParam.Type, cls, param.Type) {
+			return fmt.Sprintf("parameter %s has a narrower type", nodeident.Get(param.Var))
+		}
+	}
+
+	// Return types of internal methods are tentative, only deprecating
+	// incompatible overrides.
+	if parent.Node().ReturnType == nil || hasAttribute(parent.Node().AttrGroups, "TentativeType") {
+		return ""
+	}
+
+	// Return types are covariant, the parent has to accept every type the
+	// method returns.
+	if !t.compatible(cls, method.Node().ReturnType, parentCls, parent.Node().ReturnType) {
+		return "its return type is wider"
+	}
+
+	return ""
+}
+
+// compatible returns whether every type of the sub hint is accepted by the
+// super hint, a missing hint accepts anything.
+func (t *contractsTraverser) compatible(
+	subCls *symbol.ClassLike,
+	sub ast.Vertex,
+	superCls *symbol.ClassLike,
+	super ast.Vertex,
+) bool {
+	subTypes, ok := t.types(subCls, sub)
+	if !ok {
+		return true
+	}
+
+	superTypes, ok := t.types(superCls, super)
+	if !ok {
+		return true
+	}
+
+SubTypes:
+	for _, subType := range subTypes {
+		for _, superType := range superTypes {
+			if t.subtype(subCls, subType, superType) {
+				continue SubTypes
+			}
+		}
+
+		return false
+	}
+
+	return true
+}
+
+// types returns the lowercased native types and fully qualified classes of
+// the hint, false is returned for hints that can't be compared.
+func (t *contractsTraverser) types(cls *symbol.ClassLike, hint ast.Vertex) ([]string, bool) {
+	switch typedHint := hint.(type) {
+	case nil:
+		return []string{"mixed"}, true
+
+	case *ast.Nullable:
+		types, ok := t.types(cls, typedHint.Expr)
+		return append(types, "null"), ok
+
+	case *ast.Union:
+		var types []string
+		for _, typ := range typedHint.Types {
+			unionTypes, ok := t.types(cls, typ)
+			if !ok {
+				return nil, false
+			}
+
+			types = append(types, unionTypes...)
+		}
+
+		return types, true
+
+	case *ast.Intersection:
+		return nil, false
+
+	default:
+		name := strings.ToLower(nodeident.Get(hint))
+		switch name {
+		case "":
+			return nil, false
+		case "self":
+			return []string{cls.GetFQN().String()}, true
+		case "parent":
+			return nil, false
+		}
+
+		if _, ok := hint.(*ast.NameFullyQualified); !ok && nativeTypes.Has(name) {
+			return []string{name}, true
+		}
+
+		qualified := t.fqnt(cls.Root()).ResultFor(hint)
+		if qualified == nil {
+			return nil, false
+		}
+
+		return []string{qualified.String()}, true
+	}
+}
+
+var nativeTypes = set.NewFromSlice([]string{
+	"array", "bool", "callable", "false", "float", "int", "iterable", "mixed",
+	"never", "null", "object", "static", "string", "true", "void",
+})
+
+// subtype returns whether the sub type, of a hint in the class, is accepted
+// by the super type.
+func (t *contractsTraverser) subtype(cls *symbol.ClassLike, sub string, super string) bool {
+	isClass := func(typ string) bool {
+		return strings.HasPrefix(typ, fqn.PartSeperator)
+	}
+
+	switch {
+	case strings.EqualFold(sub, super), sub == "never":
+		return true
+	case super == "mixed":
+		return sub != "void"
+	case sub == "static":
+		return t.subtype(cls, cls.GetFQN().String(), super)
+	case super == "bool":
+		return sub == "true" || sub == "false"
+	case super == "iterable":
+		return sub == "array" || isClass(sub) && t.subclass(sub, `\Traversable`)
+	case super == "object":
+		return isClass(sub)
+	case super == "callable":
+		return strings.EqualFold(sub, `\Closure`)
+	case isClass(sub) && isClass(super):
+		return t.subclass(sub, super)
+	default:
+		return false
+	}
+}
+
+// subclass returns whether the class extends or implements the super class,
+// classes that can't be resolved are assumed to.
+func (t *contractsTraverser) subclass(sub string, super string) bool {
+	qualified := fqn.New(sub)
+	iNode, ok := index.Current.Find(qualified)
+	if !ok {
+		return true
+	}
+
+	cls, err := symbol.NewClassLikeFromFQN(wrkspc.NewRooter(iNode.Path), qualified)
+	if err != nil {
+		return true
+	}
+
+	iter := cls.InheritsIter()
+	for inhCls, done, err := iter(); !done; inhCls, done, err = iter() {
+		if err != nil {
+			return true
+		}
+
+		if strings.EqualFold(inhCls.GetFQN().String(), super) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (t *contractsTraverser) fqnt(root *ast.Root) *fqn.Traverser {
+	if fqnt, ok := t.fqnts[root]; ok {
+		return fqnt
+	}
+
+	fqnt := fqn.NewTraverser()
+	root.Accept(traverser.NewTraverser(fqnt))
+	t.fqnts[root] = fqnt
+	return fqnt
+}
+
+func parameters(method *ast.StmtClassMethod) []*ast.Parameter {
+	params := make([]*ast.Parameter, 0, len(method.Params))
+	for _, param := range method.Params {
+		params = append(params, param.(*ast.Parameter))
+	}
+
+	return params
+}
+
+// requiredCount returns the amount of arguments that have to be passed,
+// optional parameters before a required one are required too.
+func requiredCount(params []*ast.Parameter) int {
+	for i := len(params) - 1; i >= 0; i-- {
+		if params[i].DefaultValue == nil && params[i].VariadicTkn == nil {
+			return i + 1
+		}
+	}
+
+	return 0
+}
+
+func hasAttribute(groups []ast.Vertex, name string) bool {
+	for _, group := range groups {
+		for _, attr := range group.(*ast.AttributeGroup).Attrs {
+			parts := strings.Split(nodeident.Get(attr.(*ast.Attribute).Name), `\`)
+			if strings.EqualFold(parts[len(parts)-1], name) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,73 @@
+package contracts_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/laytan/phpls/internal/config"
+	"github.com/laytan/phpls/internal/contracts"
+	"github.com/laytan/phpls/internal/index"
+	"github.com/laytan/phpls/internal/project"
+	"github.com/laytan/phpls/internal/wrkspc"
+	"github.com/laytan/phpls/pkg/functional"
+	"github.com/laytan/phpls/pkg/pathutils"
+	"github.com/laytan/phpls/pkg/phpversion"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(
+		m,
+		// The cache size logger.
+		goleak.IgnoreTopFunction("github.com/laytan/phpls/internal/wrkspc.New.func1"),
+	)
+}
+
+func TestDiagnose(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.Join(pathutils.Root(), "internal", "contracts", "testdata")
+
+	err := setup(root, phpversion.EightOne())
+	require.NoError(t, err)
+
+	format := func(v *contracts.Violation) string {
+		return fmt.Sprintf("%d %s %s", v.Line(), v.Code(), v.Message())
+	}
+
+	expected := []string{
+		`60 unimplemented-method Class \Contracts\TestData\Square must implement abstract method \Contracts\TestData\Base::sides() or be declared abstract.`,
+		`60 unimplemented-method Class \Contracts\TestData\Square must implement abstract method \Contracts\TestData\Shape::area() or be declared abstract.`,
+		`66 incompatible-override Declaration of \Contracts\TestData\Triangle::area() must be compatible with \Contracts\TestData\Shape::area(), its return type is wider.`,
+		`71 override-visibility Access level to \Contracts\TestData\Triangle::sides() must be protected (as in class \Contracts\TestData\Base) or weaker.`,
+		`76 final-override Cannot override final method \Contracts\TestData\Base::id().`,
+		`81 incompatible-override Declaration of \Contracts\TestData\Triangle::scale() must be compatible with \Contracts\TestData\Base::scale(), it accepts fewer parameters.`,
+		`86 incompatible-override Declaration of \Contracts\TestData\Triangle::make() must be compatible with \Contracts\TestData\Base::make(), it is not static.`,
+		`92 final-extend Class \Contracts\TestData\Oval cannot extend final class \Contracts\TestData\Circle.`,
+		`108 incompatible-override Declaration of \Contracts\TestData\Polygon::parent() must be compatible with \Contracts\TestData\Base::parent(), its return type is wider.`,
+		`113 incompatible-override Declaration of \Contracts\TestData\Polygon::scale() must be compatible with \Contracts\TestData\Base::scale(), it requires more parameters.`,
+		`135 incompatible-override Declaration of \Contracts\TestData\Hexagon::sides() must be compatible with \Contracts\TestData\Base::sides(), parameter $precision has a narrower type.`,
+	}
+
+	violations := contracts.Diagnose(wrkspc.NewRooter(filepath.Join(root, "contracts.php")))
+	require.Equal(t, expected, functional.Map(violations, format))
+}
+
+func setup(root string, phpv *phpversion.PHPVersion) error {
+	config.Current = config.Default()
+	index.Current = index.New(phpv)
+	wrkspc.Current = wrkspc.New(
+		phpv,
+		root,
+		filepath.Join(pathutils.Root(), "third_party", "phpstorm-stubs"),
+	)
+
+	p := project.New()
+	if err := p.ParseWithoutProgress(); err != nil {
+		return fmt.Errorf("[contracts_test.setup]: %w", err)
+	}
+
+	return nil
+}
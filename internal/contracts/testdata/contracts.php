@@ -0,0 +1,139 @@
+<?php
+
+namespace Contracts\TestData;
+
+interface Shape
+{
+    public function area(): float;
+
+    public function name(): string;
+}
+
+abstract class Base implements Shape
+{
+    abstract protected function sides(int $precision): int;
+
+    public function name(): string
+    {
+        return static::class;
+    }
+
+    final public function id(): int
+    {
+        return 1;
+    }
+
+    public function scale(int|float $factor, bool $round = false): static
+    {
+        return $this;
+    }
+
+    public function parent(): ?Base
+    {
+        return null;
+    }
+
+    public static function make(): static
+    {
+        return new static();
+    }
+}
+
+final class Circle extends Base
+{
+    public function area(): float
+    {
+        return 3.14;
+    }
+
+    protected function sides(int $precision, bool $exact = true): int
+    {
+        return 0;
+    }
+
+    public function parent(): Circle
+    {
+        return $this;
+    }
+}
+
+class Square extends Base
+{
+}
+
+class Triangle extends Base
+{
+    public function area(): int|float
+    {
+        return 1;
+    }
+
+    private function sides(int $precision): int
+    {
+        return 3;
+    }
+
+    public function id(): int
+    {
+        return 3;
+    }
+
+    public function scale(int $factor): static
+    {
+        return $this;
+    }
+
+    public function make(): static
+    {
+        return new static();
+    }
+}
+
+class Oval extends Circle
+{
+}
+
+abstract class Polygon extends Base
+{
+    public function area(): float
+    {
+        return 1.0;
+    }
+
+    public function sides(int|string $precision, ...$rest): int
+    {
+        return 0;
+    }
+
+    public function parent(): ?Shape
+    {
+        return null;
+    }
+
+    public function scale(int|float $factor, bool $round = false, bool $exact): self
+    {
+        return $this;
+    }
+}
+
+class Named
+{
+    public function __construct(string $name)
+    {
+    }
+}
+
+class Anonymous extends Named
+{
+    public function __construct()
+    {
+    }
+}
+
+abstract class Hexagon extends Base
+{
+    protected function sides(string $precision): int
+    {
+        return 6;
+    }
+}
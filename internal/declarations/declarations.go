@@ -0,0 +1,386 @@
+package declarations
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/laytan/php-parser/pkg/ast"
+	"github.com/laytan/php-parser/pkg/visitor"
+	"github.com/laytan/php-parser/pkg/visitor/traverser"
+	"github.com/laytan/phpls/internal/index"
+	"github.com/laytan/phpls/internal/wrkspc"
+	"github.com/laytan/phpls/pkg/fqn"
+	"github.com/laytan/phpls/pkg/nodeident"
+	"github.com/laytan/phpls/pkg/nodescopes"
+)
+
+type rooter interface {
+	Root() *ast.Root
+	Path() string
+}
+
+type Kind int
+
+const (
+	KindDuplicate Kind = iota
+	KindNamespaceMismatch
+	KindFileNameMismatch
+)
+
+type Violation struct {
+	// The node of the declared name, or the namespace name for namespace mismatches.
+	Node ast.Vertex
+	Kind Kind
+	// Like "Class" or "Function".
+	Symbol string
+	FQN    *fqn.FQN
+	// The other files declaring the symbol, relative to the project root.
+	Paths []string
+	// The namespace, or the file name, the PSR-4 mapping expects.
+	Expected string
+	// The actual namespace, or file name.
+	Actual string
+}
+
+func (v *Violation) Message() string {
+	switch v.Kind {
+	case KindDuplicate:
+		return fmt.Sprintf("%s %s is also declared in %s.", v.Symbol, v.FQN, strings.Join(v.Paths, ", "))
+	case KindNamespaceMismatch:
+		actual := "Namespace " + v.Actual
+		if v.Actual == "" {
+			actual = "The global namespace"
+		}
+
+		return fmt.Sprintf(
+			"%s does not match the PSR-4 autoload mapping, expected %s.",
+			actual,
+			v.Expected,
+		)
+	default:
+		return fmt.Sprintf(
+			"%s %s is declared in %s, the PSR-4 autoload mapping expects it in %s.",
+			v.Symbol,
+			v.FQN,
+			v.Actual,
+			v.Expected,
+		)
+	}
+}
+
+func (v *Violation) Code() string {
+	switch v.Kind {
+	case KindDuplicate:
+		return "duplicate-declaration"
+	case KindNamespaceMismatch:
+		return "psr4-namespace"
+	default:
+		return "psr4-filename"
+	}
+}
+
+func (v *Violation) Line() int {
+	return v.Node.GetPosition().StartLine
+}
+
+// Diagnose finds classes, functions and constants declared in the root that
+// are also declared in other project files, and classes whose namespace or
+// file name doesn't match the PSR-4 autoload mapping in the composer.json of
+// the project.
+//
+// Only unconditional declarations are checked, declaring a symbol inside an if
+// statement is a common way of providing a fallback.
+func Diagnose(root rooter) []*Violation {
+	t := &declarationsTraverser{}
+	root.Root().Accept(traverser.NewTraverser(t))
+
+	var violations []*Violation
+	for _, decl := range t.declarations {
+		if v := duplicate(root.Path(), decl); v != nil {
+			violations = append(violations, v)
+		}
+	}
+
+	return append(violations, psr4(root.Path(), t)...)
+}
+
+type declaration struct {
+	node      ast.Vertex
+	name      ast.Vertex
+	namespace *ast.StmtNamespace
+	fqn       *fqn.FQN
+}
+
+type declarationsTraverser struct {
+	visitor.Null
+
+	namespace    *ast.StmtNamespace
+	declarations []*declaration
+	// The namespaces that contain class-like declarations.
+	namespaces []*ast.StmtNamespace
+}
+
+func (t *declarationsTraverser) EnterNode(node ast.Vertex) bool {
+	switch typedNode := node.(type) {
+	case *ast.Root:
+		return true
+
+	case *ast.StmtNamespace:
+		t.namespace = typedNode
+		return true
+
+	case *ast.StmtFunction:
+		t.declare(node, typedNode.Name)
+
+	case *ast.StmtClass:
+		t.declare(node, typedNode.Name)
+
+	case *ast.StmtInterface:
+		t.declare(node, typedNode.Name)
+
+	case *ast.StmtTrait:
+		t.declare(node, typedNode.Name)
+
+	case *ast.StmtEnum:
+		t.declare(node, typedNode.Name)
+
+	case *ast.StmtConstList:
+		for _, constant := range typedNode.Consts {
+			t.declare(constant, constant.(*ast.StmtConstant).Name)
+		}
+	}
+
+	return false
+}
+
+func (t *declarationsTraverser) declare(node ast.Vertex, name ast.Vertex) {
+	namespace := fqn.PartSeperator
+	if t.namespace != nil && t.namespace.Name != nil {
+		namespace = nodeident.Get(t.namespace) + fqn.PartSeperator
+	}
+
+	t.declarations = append(t.declarations, &declaration{
+		node:      node,
+		name:      name,
+		namespace: t.namespace,
+		fqn:       fqn.New(namespace + nodeident.Get(name)),
+	})
+
+	if nodescopes.IsClassLike(node.GetType()) &&
+		(len(t.namespaces) == 0 || t.namespaces[len(t.namespaces)-1] != t.namespace) {
+		t.namespaces = append(t.namespaces, t.namespace)
+	}
+}
+
+// duplicate checks if the declaration is also declared in other project files.
+func duplicate(path string, decl *declaration) *Violation {
+	var paths []string
+	for _, iNode := range index.Current.FindAll(decl.fqn) {
+		if iNode.Path == path || kindGroup(iNode.Kind) != kindGroup(decl.node.GetType()) {
+			continue
+		}
+
+		rel, err := filepath.Rel(wrkspc.Current.Root(), iNode.Path)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			continue
+		}
+
+		paths = append(paths, filepath.ToSlash(rel))
+	}
+
+	if len(paths) == 0 {
+		return nil
+	}
+
+	sort.Strings(paths)
+
+	return &Violation{
+		Node:   decl.name,
+		Kind:   KindDuplicate,
+		Symbol: symbolName(decl.node.GetType()),
+		FQN:    decl.fqn,
+		Paths:  paths,
+	}
+}
+
+// psr4 checks the namespaces and file name of the class-likes declared in the
+// file against the PSR-4 mapping of the directory of the file.
+func psr4(path string, t *declarationsTraverser) []*Violation {
+	if len(t.namespaces) == 0 {
+		return nil
+	}
+
+	expected, ok := expectedNamespace(path)
+	if !ok {
+		return nil
+	}
+
+	var violations []*Violation
+	for _, namespace := range t.namespaces {
+		actual := ""
+		if namespace != nil && namespace.Name != nil {
+			actual = strings.TrimPrefix(nodeident.Get(namespace), fqn.PartSeperator)
+		}
+
+		if actual == expected {
+			continue
+		}
+
+		v := &Violation{
+			Kind:     KindNamespaceMismatch,
+			Expected: expected,
+			Actual:   actual,
+		}
+
+		if namespace != nil && namespace.Name != nil {
+			v.Node = namespace.Name
+		} else {
+			// Without a namespace, report at the first class in the global namespace.
+			for _, decl := range t.declarations {
+				if decl.namespace == namespace && nodescopes.IsClassLike(decl.node.GetType()) {
+					v.Node = decl.name
+					break
+				}
+			}
+		}
+
+		violations = append(violations, v)
+	}
+
+	// A file with multiple classes can't be autoloaded by the name of each.
+	var classes []*declaration
+	for _, decl := range t.declarations {
+		if nodescopes.IsClassLike(decl.node.GetType()) {
+			classes = append(classes, decl)
+		}
+	}
+
+	if len(classes) == 1 {
+		fileName := filepath.Base(path)
+		expectedFileName := nodeident.Get(classes[0].name) + filepath.Ext(path)
+		if fileName != expectedFileName {
+			violations = append(violations, &Violation{
+				Node:     classes[0].name,
+				Kind:     KindFileNameMismatch,
+				Symbol:   symbolName(classes[0].node.GetType()),
+				FQN:      classes[0].fqn,
+				Expected: expectedFileName,
+				Actual:   fileName,
+			})
+		}
+	}
+
+	return violations
+}
+
+type composer struct {
+	Autoload    autoload `json:"autoload"`
+	AutoloadDev autoload `json:"autoload-dev"`
+}
+
+type autoload struct {
+	// Maps namespace prefixes to a directory or a list of directories.
+	Psr4 map[string]json.RawMessage `json:"psr-4"`
+}
+
+// expectedNamespace returns the namespace the file should have according to
+// the most specific PSR-4 mapping of the composer.json in the project root,
+// false is returned if no mapping contains the file.
+func expectedNamespace(path string) (string, bool) {
+	root := wrkspc.Current.Root()
+	content, err := os.ReadFile(filepath.Join(root, "composer.json"))
+	if err != nil {
+		return "", false
+	}
+
+	var c composer
+	if err := json.Unmarshal(content, &c); err != nil {
+		return "", false
+	}
+
+	rel, err := filepath.Rel(root, filepath.Dir(path))
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return "", false
+	}
+	rel = filepath.ToSlash(rel)
+
+	expected, matched := "", -1
+	for _, mapping := range []map[string]json.RawMessage{c.Autoload.Psr4, c.AutoloadDev.Psr4} {
+		for prefix, rawDirs := range mapping {
+			for _, dir := range dirs(rawDirs) {
+				dir = filepath.ToSlash(filepath.Clean(dir))
+
+				var sub string
+				switch {
+				case dir == ".":
+					sub = rel
+				case rel == dir:
+					sub = "."
+				case strings.HasPrefix(rel, dir+"/"):
+					sub = strings.TrimPrefix(rel, dir+"/")
+				default:
+					continue
+				}
+
+				if len(dir) <= matched {
+					continue
+				}
+
+				matched = len(dir)
+				expected = strings.Trim(prefix, `\`)
+				if sub != "." {
+					expected = strings.Trim(expected+`\`+strings.ReplaceAll(sub, "/", `\`), `\`)
+				}
+			}
+		}
+	}
+
+	return expected, matched != -1
+}
+
+func dirs(raw json.RawMessage) []string {
+	var dir string
+	if err := json.Unmarshal(raw, &dir); err == nil {
+		return []string{dir}
+	}
+
+	var dirs []string
+	if err := json.Unmarshal(raw, &dirs); err == nil {
+		return dirs
+	}
+
+	return nil
+}
+
+// kindGroup groups the kinds that share a symbol table in PHP.
+func kindGroup(kind ast.Type) int {
+	switch kind {
+	case ast.TypeStmtFunction:
+		return 1
+	case ast.TypeStmtConstant:
+		return 2
+	default:
+		return 0
+	}
+}
+
+func symbolName(kind ast.Type) string {
+	switch kind {
+	case ast.TypeStmtFunction:
+		return "Function"
+	case ast.TypeStmtInterface:
+		return "Interface"
+	case ast.TypeStmtTrait:
+		return "Trait"
+	case ast.TypeStmtEnum:
+		return "Enum"
+	case ast.TypeStmtConstant:
+		return "Constant"
+	default:
+		return "Class"
+	}
+}
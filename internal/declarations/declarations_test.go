@@ -0,0 +1,78 @@
+package declarations_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/laytan/phpls/internal/config"
+	"github.com/laytan/phpls/internal/declarations"
+	"github.com/laytan/phpls/internal/index"
+	"github.com/laytan/phpls/internal/project"
+	"github.com/laytan/phpls/internal/wrkspc"
+	"github.com/laytan/phpls/pkg/functional"
+	"github.com/laytan/phpls/pkg/pathutils"
+	"github.com/laytan/phpls/pkg/phpversion"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(
+		m,
+		// The cache size logger.
+		goleak.IgnoreTopFunction("github.com/laytan/phpls/internal/wrkspc.New.func1"),
+	)
+}
+
+func TestDiagnose(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.Join(pathutils.Root(), "internal", "declarations", "testdata")
+
+	err := setup(root, phpversion.EightOne())
+	require.NoError(t, err)
+
+	format := func(v *declarations.Violation) string {
+		return fmt.Sprintf("%d %s %s", v.Line(), v.Code(), v.Message())
+	}
+
+	tests := map[string][]string{
+		filepath.Join("lib", "duplicates.php"): {
+			`5 duplicate-declaration Constant \Declarations\TestData\Models\VERSION is also declared in src/Models/User.php.`,
+			`7 duplicate-declaration Function \Declarations\TestData\Models\helper is also declared in src/Models/User.php.`,
+			`11 duplicate-declaration Interface \Declarations\TestData\Models\User is also declared in src/Models/User.php.`,
+		},
+		filepath.Join("src", "Models", "User.php"): {
+			`5 duplicate-declaration Constant \Declarations\TestData\Models\VERSION is also declared in lib/duplicates.php.`,
+			`7 duplicate-declaration Function \Declarations\TestData\Models\helper is also declared in lib/duplicates.php.`,
+			`11 duplicate-declaration Class \Declarations\TestData\Models\User is also declared in lib/duplicates.php.`,
+		},
+		filepath.Join("src", "Models", "Wrong.php"): {
+			`3 psr4-namespace Namespace Declarations\TestData\Model does not match the PSR-4 autoload mapping, expected Declarations\TestData\Models.`,
+			`5 psr4-filename Class \Declarations\TestData\Model\Misnamed is declared in Wrong.php, the PSR-4 autoload mapping expects it in Misnamed.php.`,
+		},
+	}
+
+	for file, expected := range tests {
+		violations := declarations.Diagnose(wrkspc.NewRooter(filepath.Join(root, file)))
+		require.Equal(t, expected, functional.Map(violations, format), file)
+	}
+}
+
+func setup(root string, phpv *phpversion.PHPVersion) error {
+	config.Current = config.Default()
+	index.Current = index.New(phpv)
+	wrkspc.Current = wrkspc.New(
+		phpv,
+		root,
+		filepath.Join(pathutils.Root(), "third_party", "phpstorm-stubs"),
+	)
+
+	p := project.New()
+	if err := p.ParseWithoutProgress(); err != nil {
+		return fmt.Errorf("[declarations_test.setup]: %w", err)
+	}
+
+	return nil
+}
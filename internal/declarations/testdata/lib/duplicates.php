@@ -0,0 +1,23 @@
+<?php
+
+namespace Declarations\TestData\Models;
+
+const VERSION = '2.0.0';
+
+function helper(): void
+{
+}
+
+interface User
+{
+}
+
+if (! function_exists('Declarations\TestData\Models\fallback')) {
+    function fallback(): void
+    {
+    }
+}
+
+function user(): void
+{
+}
@@ -0,0 +1,13 @@
+<?php
+
+namespace Declarations\TestData\Models;
+
+const VERSION = '1.0.0';
+
+function helper(): void
+{
+}
+
+class User
+{
+}
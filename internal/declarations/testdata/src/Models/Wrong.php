@@ -0,0 +1,7 @@
+<?php
+
+namespace Declarations\TestData\Model;
+
+class Misnamed
+{
+}
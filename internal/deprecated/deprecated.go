@@ -0,0 +1,343 @@
+package deprecated
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/laytan/php-parser/pkg/ast"
+	"github.com/laytan/php-parser/pkg/visitor"
+	"github.com/laytan/php-parser/pkg/visitor/traverser"
+	"github.com/laytan/phpls/internal/expr"
+	"github.com/laytan/phpls/internal/index"
+	"github.com/laytan/phpls/internal/symbol"
+	"github.com/laytan/phpls/internal/wrkspc"
+	"github.com/laytan/phpls/pkg/fqn"
+	"github.com/laytan/phpls/pkg/nodeident"
+	"github.com/laytan/phpls/pkg/nodescopes"
+	"github.com/laytan/phpls/pkg/phpdoxer"
+)
+
+type rooter interface {
+	Root() *ast.Root
+	Path() string
+}
+
+type Kind int
+
+const (
+	KindClass Kind = iota
+	KindFunction
+	KindMethod
+)
+
+type Violation struct {
+	// The node referring to the deprecated symbol.
+	Node ast.Vertex
+	Kind Kind
+	// The name of the symbol, fully qualified for classes.
+	Name string
+	// The version the symbol was deprecated in, empty if unknown.
+	Since string
+	// The reason for the deprecation, usually pointing to a replacement.
+	Description string
+}
+
+func (v *Violation) Message() string {
+	var msg string
+	switch v.Kind {
+	case KindClass:
+		msg = fmt.Sprintf("Class %s is deprecated", v.Name)
+	case KindFunction:
+		msg = fmt.Sprintf("Function %s() is deprecated", v.Name)
+	case KindMethod:
+		msg = fmt.Sprintf("Method %s() is deprecated", v.Name)
+	}
+
+	if v.Since != "" {
+		msg += " since " + v.Since
+	}
+
+	if v.Description != "" {
+		return msg + ": " + v.Description
+	}
+
+	return msg + "."
+}
+
+func (v *Violation) Code() string {
+	return "deprecated"
+}
+
+func (v *Violation) Line() int {
+	return v.Node.GetPosition().StartLine
+}
+
+// Diagnose finds the usages of classes, functions and methods that are marked
+// deprecated with a @deprecated tag or the #[Deprecated] attribute of the stubs.
+func Diagnose(root rooter) []*Violation {
+	fqnt := fqn.NewTraverser()
+	root.Root().Accept(traverser.NewTraverser(fqnt))
+
+	t := &deprecatedTraverser{
+		rooter:  root,
+		fqnt:    fqnt,
+		classes: []ast.Vertex{root.Root()},
+		blocks:  []ast.Vertex{root.Root()},
+	}
+	root.Root().Accept(traverser.NewTraverser(t))
+
+	return t.violations
+}
+
+type deprecatedTraverser struct {
+	visitor.Null
+
+	rooter rooter
+	fqnt   *fqn.Traverser
+
+	classes []ast.Vertex
+	blocks  []ast.Vertex
+
+	violations []*Violation
+}
+
+func (t *deprecatedTraverser) EnterNode(node ast.Vertex) bool {
+	if nodescopes.IsScope(node.GetType()) {
+		t.blocks = append(t.blocks, node)
+	}
+
+	if nodescopes.IsClassLike(node.GetType()) {
+		t.classes = append(t.classes, node)
+	}
+
+	switch typedNode := node.(type) {
+	case *ast.ExprNew:
+		t.class(typedNode.Class)
+
+	case *ast.ExprInstanceOf:
+		t.class(typedNode.Class)
+
+	case *ast.StmtClass:
+		t.class(typedNode.Extends)
+
+		for _, impl := range typedNode.Implements {
+			t.class(impl)
+		}
+
+	case *ast.StmtInterface:
+		for _, ext := range typedNode.Extends {
+			t.class(ext)
+		}
+
+	case *ast.StmtTraitUse:
+		for _, trait := range typedNode.Traits {
+			t.class(trait)
+		}
+
+	case *ast.ExprClassConstFetch:
+		t.class(typedNode.Class)
+
+	case *ast.ExprStaticPropertyFetch:
+		t.class(typedNode.Class)
+
+	case *ast.ExprStaticCall:
+		t.class(typedNode.Class)
+		t.call(KindMethod, node, typedNode.Call)
+
+	case *ast.ExprMethodCall:
+		t.call(KindMethod, node, typedNode.Method)
+
+	case *ast.ExprFunctionCall:
+		t.call(KindFunction, node, typedNode.Function)
+	}
+
+	return true
+}
+
+func (t *deprecatedTraverser) LeaveNode(node ast.Vertex) {
+	if nodescopes.IsScope(node.GetType()) {
+		t.blocks = t.blocks[:len(t.blocks)-1]
+	}
+
+	if nodescopes.IsClassLike(node.GetType()) {
+		t.classes = t.classes[:len(t.classes)-1]
+	}
+}
+
+func (t *deprecatedTraverser) class(name ast.Vertex) {
+	if name == nil || !nodescopes.IsName(name.GetType()) {
+		return
+	}
+
+	switch strings.ToLower(nodeident.Get(name)) {
+	case "self", "static", "parent":
+		return
+	}
+
+	qualified := t.fqnt.ResultFor(name)
+	if qualified == nil {
+		return
+	}
+
+	iNode, ok := index.Current.Find(qualified)
+	if !ok {
+		return
+	}
+
+	root := t.rootOf(iNode.Path)
+	if root == nil {
+		return
+	}
+
+	cls := iNode.ToIRNode(root)
+	if cls == nil {
+		return
+	}
+
+	t.check(KindClass, name, qualified.String(), root, cls)
+}
+
+// call checks the function or method the call resolves to, the name node is
+// reported so only the name is rendered as deprecated.
+func (t *deprecatedTraverser) call(kind Kind, node ast.Vertex, name ast.Vertex) {
+	if kind == KindFunction && !nodescopes.IsName(name.GetType()) {
+		return
+	}
+
+	if _, ok := name.(*ast.Identifier); kind == KindMethod && !ok {
+		return
+	}
+
+	res, _, left := expr.Resolve(node, &expr.Scopes{
+		Path:  t.rooter.Path(),
+		Root:  t.rooter.Root(),
+		Class: t.classes[len(t.classes)-1],
+		Block: t.blocks[len(t.blocks)-1],
+	})
+	if left != 0 || res == nil || res.Node == nil {
+		return
+	}
+
+	root := t.rootOf(res.Path)
+	if root == nil {
+		return
+	}
+
+	t.check(kind, name, nodeident.Get(name), root, res.Node)
+}
+
+func (t *deprecatedTraverser) check(
+	kind Kind,
+	node ast.Vertex,
+	name string,
+	root *ast.Root,
+	declaration ast.Vertex,
+) {
+	since, description, ok := Deprecation(root, declaration)
+	if !ok {
+		return
+	}
+
+	t.violations = append(t.violations, &Violation{
+		Node:        node,
+		Kind:        kind,
+		Name:        name,
+		Since:       since,
+		Description: description,
+	})
+}
+
+func (t *deprecatedTraverser) rootOf(path string) *ast.Root {
+	if path == t.rooter.Path() {
+		return t.rooter.Root()
+	}
+
+	return wrkspc.Current.FIROf(path)
+}
+
+// Deprecation returns whether the declaration is marked deprecated, by a
+// @deprecated tag or the #[Deprecated] attribute, and the version and reason
+// given. The root is the file the declaration is in, to resolve the attribute.
+func Deprecation(
+	root *ast.Root,
+	declaration ast.Vertex,
+) (since string, description string, ok bool) {
+	doc := symbol.NewDoxed(declaration).FindDoc(symbol.FilterDocKind(phpdoxer.KindDeprecated))
+	if typedDoc, isDeprecated := doc.(*phpdoxer.NodeDeprecated); isDeprecated {
+		if typedDoc.Version != nil {
+			since = typedDoc.Version.String()
+		}
+
+		return since, typedDoc.Description, true
+	}
+
+	var fqnt *fqn.Traverser
+	for _, attrGroup := range attrGroups(declaration) {
+		for _, attr := range attrGroup.(*ast.AttributeGroup).Attrs {
+			typedAttr := attr.(*ast.Attribute)
+
+			// Only resolve the names when an attribute could be the one.
+			parts := strings.Split(nodeident.Get(typedAttr.Name), `\`)
+			if !strings.EqualFold(parts[len(parts)-1], "Deprecated") {
+				continue
+			}
+
+			if fqnt == nil {
+				fqnt = fqn.NewTraverser()
+				root.Accept(traverser.NewTraverser(fqnt))
+			}
+
+			qualified := fqnt.ResultFor(typedAttr.Name)
+			if qualified == nil || !strings.EqualFold(qualified.String(), deprecatedAttribute) {
+				continue
+			}
+
+			for i, arg := range typedAttr.Args {
+				typedArg, isArg := arg.(*ast.Argument)
+				if !isArg {
+					continue
+				}
+
+				value, isString := typedArg.Expr.(*ast.ScalarString)
+				if !isString {
+					continue
+				}
+
+				var argName string
+				if typedArg.Name != nil {
+					argName = nodeident.Get(typedArg.Name)
+				}
+
+				switch {
+				case argName == "reason" || (argName == "" && i == 0):
+					description = strings.Trim(string(value.Value), `'"`)
+				case argName == "since" || (argName == "" && i == 2):
+					since = strings.Trim(string(value.Value), `'"`)
+				}
+			}
+
+			return since, description, true
+		}
+	}
+
+	return "", "", false
+}
+
+const deprecatedAttribute = `\JetBrains\PhpStorm\Deprecated`
+
+func attrGroups(node ast.Vertex) []ast.Vertex {
+	switch typedNode := node.(type) {
+	case *ast.StmtFunction:
+		return typedNode.AttrGroups
+	case *ast.StmtClassMethod:
+		return typedNode.AttrGroups
+	case *ast.StmtClass:
+		return typedNode.AttrGroups
+	case *ast.StmtInterface:
+		return typedNode.AttrGroups
+	case *ast.StmtTrait:
+		return typedNode.AttrGroups
+	default:
+		return nil
+	}
+}
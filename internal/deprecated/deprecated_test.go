@@ -0,0 +1,70 @@
+package deprecated_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/laytan/phpls/internal/config"
+	"github.com/laytan/phpls/internal/deprecated"
+	"github.com/laytan/phpls/internal/index"
+	"github.com/laytan/phpls/internal/project"
+	"github.com/laytan/phpls/internal/wrkspc"
+	"github.com/laytan/phpls/pkg/functional"
+	"github.com/laytan/phpls/pkg/pathutils"
+	"github.com/laytan/phpls/pkg/phpversion"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(
+		m,
+		// The cache size logger.
+		goleak.IgnoreTopFunction("github.com/laytan/phpls/internal/wrkspc.New.func1"),
+	)
+}
+
+func TestDiagnose(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.Join(pathutils.Root(), "internal", "deprecated", "testdata")
+
+	err := setup(root, phpversion.EightOne())
+	require.NoError(t, err)
+
+	format := func(v *deprecated.Violation) string {
+		return fmt.Sprintf("%d %s %s", v.Line(), v.Code(), v.Message())
+	}
+
+	expected := []string{
+		`21 deprecated Class \Deprecated\TestData\Legacy is deprecated: Use Current instead.`,
+		`32 deprecated Method old() is deprecated.`,
+		`43 deprecated Function legacy() is deprecated since 1.5: Use current() instead.`,
+		`45 deprecated Class \Deprecated\TestData\Legacy is deprecated: Use Current instead.`,
+		`46 deprecated Class \Deprecated\TestData\Legacy is deprecated: Use Current instead.`,
+		`46 deprecated Method create() is deprecated since 2.1.0.`,
+		`50 deprecated Method old() is deprecated.`,
+		`58 deprecated Function Attribute\qualified() is deprecated: Use current() instead.`,
+	}
+
+	violations := deprecated.Diagnose(wrkspc.NewRooter(filepath.Join(root, "deprecated.php")))
+	require.Equal(t, expected, functional.Map(violations, format))
+}
+
+func setup(root string, phpv *phpversion.PHPVersion) error {
+	config.Current = config.Default()
+	index.Current = index.New(phpv)
+	wrkspc.Current = wrkspc.New(
+		phpv,
+		root,
+		filepath.Join(pathutils.Root(), "third_party", "phpstorm-stubs"),
+	)
+
+	p := project.New()
+	if err := p.ParseWithoutProgress(); err != nil {
+		return fmt.Errorf("[deprecated_test.setup]: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,20 @@
+<?php
+
+namespace Deprecated\TestData\Attribute;
+
+use Attribute;
+
+#[Attribute]
+class Deprecated
+{
+}
+
+#[Deprecated]
+function notDeprecated(): void
+{
+}
+
+#[\JetBrains\PhpStorm\Deprecated('Use current() instead.')]
+function qualified(): void
+{
+}
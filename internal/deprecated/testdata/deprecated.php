@@ -0,0 +1,59 @@
+<?php
+
+namespace Deprecated\TestData;
+
+use JetBrains\PhpStorm\Deprecated;
+
+/**
+ * @deprecated Use Current instead.
+ */
+class Legacy
+{
+    /**
+     * @deprecated 2.1
+     */
+    public static function create(): self
+    {
+        return new self();
+    }
+}
+
+class Current extends Legacy
+{
+    /**
+     * @deprecated
+     */
+    public function old(): void
+    {
+    }
+
+    public function new(): void
+    {
+        $this->old();
+    }
+}
+
+#[Deprecated(reason: 'Use current() instead.', since: '1.5')]
+function legacy(): void
+{
+}
+
+function current(): Current
+{
+    legacy();
+
+    $legacy = new Legacy();
+    Legacy::create();
+
+    $current = new Current();
+    $current->new();
+    $current->old();
+
+    return $current;
+}
+
+function attribute(): void
+{
+    Attribute\notDeprecated();
+    Attribute\qualified();
+}
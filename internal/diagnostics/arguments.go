@@ -0,0 +1,30 @@
+package diagnostics
+
+import (
+	"github.com/laytan/go-lsp-protocol/pkg/lsp/protocol"
+	"github.com/laytan/phpls/internal/arguments"
+	"github.com/laytan/phpls/internal/wrkspc"
+	"github.com/laytan/phpls/pkg/parsing"
+	"github.com/laytan/phpls/pkg/phpversion"
+)
+
+// MakeArguments creates the analyzer reporting calls with the wrong amount of
+// arguments, unknown named arguments and literal arguments that don't match
+// the parameter type.
+func MakeArguments(phpv *phpversion.PHPVersion) *ASTAnalyzer {
+	return MakeAST("arguments", parsing.New(phpv), protocol.SeverityError, diagnoseArguments)
+}
+
+func diagnoseArguments(rooter *wrkspc.Rooter, content string) []protocol.Diagnostic {
+	violations := arguments.Diagnose(rooter)
+	diagnostics := make([]protocol.Diagnostic, 0, len(violations))
+	for _, violation := range violations {
+		diagnostics = append(diagnostics, protocol.Diagnostic{
+			Range:   nodeRange(content, violation.Node),
+			Code:    violation.Code(),
+			Message: violation.Message(),
+		})
+	}
+
+	return diagnostics
+}
@@ -0,0 +1,76 @@
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/laytan/go-lsp-protocol/pkg/lsp/protocol"
+	"github.com/laytan/phpls/internal/wrkspc"
+	"github.com/laytan/phpls/pkg/parsing"
+)
+
+// DiagnoseFunc diagnoses the parsed file, content is the code that was parsed.
+// Diagnostics without a severity get the severity of the analyzer.
+type DiagnoseFunc func(rooter *wrkspc.Rooter, content string) []protocol.Diagnostic
+
+// ASTAnalyzer is an analyzer that parses the file and diagnoses the AST
+// in-process, it does not need any external tools.
+type ASTAnalyzer struct {
+	name     string
+	parser   parsing.Parser
+	severity protocol.DiagnosticSeverity
+	diagnose DiagnoseFunc
+}
+
+var _ Analyzer = &ASTAnalyzer{}
+
+func MakeAST(
+	name string,
+	parser parsing.Parser,
+	severity protocol.DiagnosticSeverity,
+	diagnose DiagnoseFunc,
+) *ASTAnalyzer {
+	return &ASTAnalyzer{
+		name:     name,
+		parser:   parser,
+		severity: severity,
+		diagnose: diagnose,
+	}
+}
+
+func (a *ASTAnalyzer) Name() string {
+	return a.name
+}
+
+func (a *ASTAnalyzer) Analyze(
+	ctx context.Context,
+	path string,
+	code []byte,
+) ([]protocol.Diagnostic, error) {
+	root, err := a.parser.Parse(code)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %q for %s analysis: %w", path, a.name, err)
+	}
+
+	diagnostics := a.diagnose(wrkspc.NewRooter(path, root), string(code))
+
+	// Diagnosing can't be cancelled mid-way, but don't report outdated results.
+	if ctx.Err() != nil {
+		return nil, nil
+	}
+
+	for i := range diagnostics {
+		if diagnostics[i].Severity == 0 {
+			diagnostics[i].Severity = a.severity
+		}
+	}
+
+	return diagnostics, nil
+}
+
+func (a *ASTAnalyzer) AnalyzeSave(
+	ctx context.Context,
+	path string,
+) ([]protocol.Diagnostic, error) {
+	return a.Analyze(ctx, path, []byte(wrkspc.Current.FContentOf(path)))
+}
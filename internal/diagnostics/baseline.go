@@ -0,0 +1,122 @@
+package diagnostics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"path/filepath"
+
+	"github.com/laytan/go-lsp-protocol/pkg/lsp/protocol"
+	"github.com/laytan/phpls/internal/config"
+	"github.com/laytan/phpls/internal/wrkspc"
+	"github.com/laytan/phpls/pkg/baseline"
+)
+
+// GenerateBaseline runs all analyzers over the project files and writes the
+// results to the configured baseline file, replacing the previous baseline.
+// The published diagnostics are updated to leave out the baseline.
+//
+// The progress is reported with the given token, or a new one if nil, and the
+// run stops when the progress is cancelled with CancelProgress.
+// Returns the amount of diagnostics in the new baseline.
+func (r *Runner) GenerateBaseline(ctx context.Context, token protocol.ProgressToken) (int, error) {
+	path := baselinePath()
+	if path == "" {
+		return 0, errors.New("no baseline file is configured")
+	}
+
+	b := baseline.New()
+	if err := r.analyzeProject(
+		ctx,
+		token,
+		"generating diagnostics baseline",
+		func(string) bool { return true },
+		func(path string, content string, diagnostics, saveDiagnostics []protocol.Diagnostic) {
+			b.Set(relPath(path), content, append(diagnostics, saveDiagnostics...))
+		},
+	); err != nil {
+		return 0, err
+	}
+
+	if err := b.Save(path); err != nil {
+		return 0, fmt.Errorf("saving baseline: %w", err)
+	}
+
+	r.baselineMu.Lock()
+	r.baseline = b
+	r.baselineMu.Unlock()
+
+	r.republish(ctx)
+
+	return b.Len(), nil
+}
+
+// withoutBaseline filters the diagnostics that are in the baseline out, the
+// baseline is loaded the first time it is needed. The content is the code the
+// diagnostics were created from.
+func (r *Runner) withoutBaseline(
+	path string,
+	content string,
+	diagnostics []protocol.Diagnostic,
+) []protocol.Diagnostic {
+	bpath := baselinePath()
+	if bpath == "" || len(diagnostics) == 0 {
+		return diagnostics
+	}
+
+	r.baselineMu.Lock()
+	if r.baseline == nil {
+		b, err := baseline.Load(bpath)
+		if err != nil {
+			log.Printf("[ERROR]: loading baseline, reporting all diagnostics: %v", err)
+			b = baseline.New()
+		}
+
+		r.baseline = b
+	}
+	b := r.baseline
+	r.baselineMu.Unlock()
+
+	return b.Filter(relPath(path), content, diagnostics)
+}
+
+// republish publishes all the files again, with the current baseline applied.
+func (r *Runner) republish(ctx context.Context) {
+	r.diagnosticsMu.Lock()
+	paths := make([]string, 0, len(r.diagnostics))
+	for path, fd := range r.diagnostics {
+		fd.published = false
+		paths = append(paths, path)
+	}
+	r.diagnosticsMu.Unlock()
+
+	for _, path := range paths {
+		if err := r.Publish(ctx, path); err != nil {
+			log.Printf("[ERROR]: republishing diagnostics of %q: %v", path, err)
+		}
+	}
+}
+
+func baselinePath() string {
+	if config.Current.Diagnostics.Baseline == "" {
+		return ""
+	}
+
+	if filepath.IsAbs(config.Current.Diagnostics.Baseline) {
+		return config.Current.Diagnostics.Baseline
+	}
+
+	return filepath.Join(wrkspc.Current.Root(), config.Current.Diagnostics.Baseline)
+}
+
+// relPath returns the path relative to the project root, so the baseline can
+// be shared.
+func relPath(path string) string {
+	rel, err := filepath.Rel(wrkspc.Current.Root(), path)
+	if err != nil {
+		return path
+	}
+
+	return filepath.ToSlash(rel)
+}
@@ -0,0 +1,54 @@
+package diagnostics
+
+import (
+	"log"
+	"sync"
+
+	"github.com/laytan/go-lsp-protocol/pkg/lsp/protocol"
+	"github.com/laytan/phpls/internal/compat"
+	"github.com/laytan/phpls/internal/wrkspc"
+	"github.com/laytan/phpls/pkg/parsing"
+	"github.com/laytan/phpls/pkg/phpversion"
+	"github.com/laytan/phpls/pkg/stubs"
+	"github.com/laytan/phpls/pkg/stubs/stubtransform"
+)
+
+// MakeCompatibility creates the analyzer reporting syntax and built-in symbols
+// that are not available in the configured minimum or maximum PHP version.
+func MakeCompatibility(minv *phpversion.PHPVersion, maxv *phpversion.PHPVersion) *ASTAnalyzer {
+	var availabilityOnce sync.Once
+	var availability map[string]*stubtransform.Availability
+
+	return MakeAST(
+		"compatibility",
+		parsing.New(maxv),
+		protocol.SeverityError,
+		func(rooter *wrkspc.Rooter, content string) []protocol.Diagnostic {
+			// Parsing all the stubs takes a while, only do it when actually needed.
+			availabilityOnce.Do(func() {
+				var err error
+				availability, err = stubs.Availability()
+				if err != nil {
+					log.Printf("[ERROR]: retrieving availability of built-in symbols: %v", err)
+				}
+			})
+
+			violations := compat.Diagnose(rooter, compat.Options{
+				MinVersion:   minv,
+				MaxVersion:   maxv,
+				Availability: availability,
+			})
+
+			diagnostics := make([]protocol.Diagnostic, 0, len(violations))
+			for _, violation := range violations {
+				diagnostics = append(diagnostics, protocol.Diagnostic{
+					Range:   nodeRange(content, violation.Node),
+					Code:    violation.Code,
+					Message: violation.Message,
+				})
+			}
+
+			return diagnostics
+		},
+	)
+}
@@ -0,0 +1,30 @@
+package diagnostics
+
+import (
+	"github.com/laytan/go-lsp-protocol/pkg/lsp/protocol"
+	"github.com/laytan/phpls/internal/contracts"
+	"github.com/laytan/phpls/internal/wrkspc"
+	"github.com/laytan/phpls/pkg/parsing"
+	"github.com/laytan/phpls/pkg/phpversion"
+)
+
+// MakeContracts creates the analyzer reporting unimplemented abstract methods,
+// overrides that are incompatible with the overridden method and overridden or
+// extended finals.
+func MakeContracts(phpv *phpversion.PHPVersion) *ASTAnalyzer {
+	return MakeAST("contracts", parsing.New(phpv), protocol.SeverityError, diagnoseContracts)
+}
+
+func diagnoseContracts(rooter *wrkspc.Rooter, content string) []protocol.Diagnostic {
+	violations := contracts.Diagnose(rooter)
+	diagnostics := make([]protocol.Diagnostic, 0, len(violations))
+	for _, violation := range violations {
+		diagnostics = append(diagnostics, protocol.Diagnostic{
+			Range:   nodeRange(content, violation.Node),
+			Code:    violation.Code(),
+			Message: violation.Message(),
+		})
+	}
+
+	return diagnostics
+}
@@ -0,0 +1,305 @@
+package diagnostics
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/laytan/go-lsp-protocol/pkg/lsp/protocol"
+	"github.com/laytan/phpls/internal/config"
+	"github.com/laytan/phpls/internal/wrkspc"
+	"github.com/laytan/phpls/pkg/position"
+	"github.com/laytan/phpls/pkg/report"
+)
+
+const (
+	customFormatCheckstyle = "CHECKSTYLE"
+	customFormatSarif      = "SARIF"
+	customFormatJSONLines  = "JSON_LINES"
+	customFormatRegex      = "REGEX"
+
+	customFilePlaceholder  = "{file}"
+	customStdinPlaceholder = "{stdin}"
+)
+
+// CustomAnalyzer runs a configured command and parses its output in one of
+// the supported report formats.
+type CustomAnalyzer struct {
+	config config.Custom
+}
+
+var _ Analyzer = &CustomAnalyzer{}
+
+func MakeCustom(cfg config.Custom) *CustomAnalyzer {
+	return &CustomAnalyzer{config: cfg}
+}
+
+func (c *CustomAnalyzer) Name() string {
+	return c.config.Name
+}
+
+func (c *CustomAnalyzer) Analyze(
+	ctx context.Context,
+	path string,
+	code []byte,
+) ([]protocol.Diagnostic, error) {
+	if c.usesStdin() {
+		return c.analyze(ctx, path, path, code)
+	}
+
+	// The code is written next to the path, so configuration of the tool applies.
+	dir, name := filepath.Split(path)
+	fh, err := os.CreateTemp(dir, ".phpls-tmp.*."+name)
+	if err != nil {
+		return nil, fmt.Errorf("creating temp file for %s: %w", c.Name(), err)
+	}
+
+	if _, err := fh.Write(code); err != nil {
+		return nil, fmt.Errorf("writing to temp file %q: %w", fh.Name(), err)
+	}
+
+	defer func() {
+		go func() {
+			p := fh.Name()
+			if err := fh.Close(); err != nil {
+				log.Println(fmt.Errorf("[ERROR]: %s closing temp file %q: %w", c.Name(), p, err))
+			}
+
+			if err := os.Remove(p); err != nil {
+				log.Println(fmt.Errorf("[ERROR]: %s removing temp file %q: %w", c.Name(), p, err))
+			}
+		}()
+	}()
+
+	return c.analyze(ctx, path, fh.Name(), code)
+}
+
+func (c *CustomAnalyzer) AnalyzeSave(
+	ctx context.Context,
+	path string,
+) ([]protocol.Diagnostic, error) {
+	return c.analyze(ctx, path, path, []byte(wrkspc.Current.FContentOf(path)))
+}
+
+// analyze runs the command on the given file, which is the path or a temporary
+// copy of it, the code is written to stdin if the command asks for it.
+func (c *CustomAnalyzer) analyze(
+	ctx context.Context,
+	path string,
+	file string,
+	code []byte,
+) ([]protocol.Diagnostic, error) {
+	args := make([]string, 0, len(c.config.Command))
+	for _, arg := range c.config.Command {
+		arg = strings.ReplaceAll(arg, customStdinPlaceholder, "")
+		arg = strings.ReplaceAll(arg, customFilePlaceholder, file)
+		if arg != "" {
+			args = append(args, arg)
+		}
+	}
+
+	if len(args) == 0 {
+		return nil, fmt.Errorf("%s has no command configured", c.Name())
+	}
+
+	cmd := exec.CommandContext( // nolint:gosec // The command is configured by the user.
+		ctx,
+		args[0],
+		args[1:]...,
+	)
+	cmd.Dir = wrkspc.Current.Root()
+	if c.usesStdin() {
+		cmd.Stdin = bytes.NewReader(code)
+	}
+
+	// Linters tend to exit with a non-zero code when problems are found,
+	// so the exit code is ignored as long as the output can be parsed.
+	out, err := cmd.Output()
+	if ctx.Err() != nil {
+		log.Printf("[DEBUG]: %s cancelled: %v", c.Name(), ctx.Err())
+		return nil, nil
+	}
+
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return nil, fmt.Errorf("running %s: %w", c.Name(), err)
+	}
+
+	problems, perr := c.parse(out)
+	if perr != nil {
+		if exitErr != nil {
+			return nil, fmt.Errorf("running %s: %w: %s", c.Name(), err, exitErr.Stderr)
+		}
+
+		return nil, fmt.Errorf("parsing %s output: %w", c.Name(), perr)
+	}
+
+	content := string(code)
+	diagnostics := make([]protocol.Diagnostic, 0, len(problems))
+	for _, problem := range problems {
+		if !isProblemFile(problem.File, path, file) {
+			continue
+		}
+
+		diagnostic := protocol.Diagnostic{
+			Range:    problemRange(content, problem),
+			Severity: c.severity(problem.Severity),
+			Message:  problem.Message,
+		}
+
+		// An empty code would still be shown by clients.
+		if problem.Code != "" {
+			diagnostic.Code = problem.Code
+		}
+
+		diagnostics = append(diagnostics, diagnostic)
+	}
+
+	return diagnostics, nil
+}
+
+func (c *CustomAnalyzer) parse(out []byte) ([]*report.Problem, error) {
+	switch c.config.Format {
+	case customFormatCheckstyle:
+		return report.ParseCheckstyle(out) // nolint:wrapcheck // Wrapped by caller.
+	case customFormatSarif:
+		return report.ParseSarif(out) // nolint:wrapcheck // Wrapped by caller.
+	case customFormatJSONLines:
+		return report.ParseJSONLines(out) // nolint:wrapcheck // Wrapped by caller.
+	case customFormatRegex:
+		return report.ParseRegex(c.config.Pattern, out) // nolint:wrapcheck // Wrapped by caller.
+	default:
+		return nil, fmt.Errorf("unsupported format %q", c.config.Format)
+	}
+}
+
+func (c *CustomAnalyzer) usesStdin() bool {
+	for _, arg := range c.config.Command {
+		if strings.Contains(arg, customStdinPlaceholder) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// severity maps the severity of the tool using the configured severities,
+// falling back to the common names and eventually warnings.
+func (c *CustomAnalyzer) severity(severity string) protocol.DiagnosticSeverity {
+	mapped := []struct {
+		severities []string
+		severity   protocol.DiagnosticSeverity
+	}{
+		{c.config.Error, protocol.SeverityError},
+		{c.config.Warning, protocol.SeverityWarning},
+		{c.config.Information, protocol.SeverityInformation},
+		{c.config.Hint, protocol.SeverityHint},
+	}
+
+	for _, m := range mapped {
+		for _, s := range m.severities {
+			if strings.EqualFold(s, severity) {
+				return m.severity
+			}
+		}
+	}
+
+	switch strings.ToLower(severity) {
+	case "error":
+		return protocol.SeverityError
+	case "info", "information", "note":
+		return protocol.SeverityInformation
+	case "hint":
+		return protocol.SeverityHint
+	default:
+		return protocol.SeverityWarning
+	}
+}
+
+// isProblemFile returns whether the reported file refers to the analyzed file,
+// tools report relative paths, absolute paths, URIs or nothing at all for stdin.
+func isProblemFile(reported string, path string, file string) bool {
+	reported = strings.TrimPrefix(reported, "file://")
+	switch reported {
+	case "", "-", "STDIN", "php://stdin", path, file:
+		return true
+	}
+
+	if filepath.IsAbs(reported) {
+		return false
+	}
+
+	reported = string(filepath.Separator) + filepath.Clean(reported)
+	return strings.HasSuffix(path, reported) || strings.HasSuffix(file, reported)
+}
+
+// problemRange converts the 1-based lines and columns of the problem to a LSP
+// range, the whole line is used when there is no column and the token at the
+// column when there is no end column.
+func problemRange(content string, problem *report.Problem) protocol.Range {
+	line := uint32(1)
+	if problem.Line > 0 {
+		line = uint32(problem.Line)
+	}
+
+	if problem.Column <= 0 {
+		return protocol.Range{
+			Start: protocol.Position{Line: line - 1},
+			End:   lineEnd(content, line-1),
+		}
+	}
+
+	start := protocol.Position{Line: line - 1, Character: uint32(problem.Column) - 1}
+	end := start
+
+	if problem.EndLine > 0 {
+		end.Line = uint32(problem.EndLine) - 1
+	}
+
+	switch {
+	case problem.EndColumn > 0:
+		end.Character = uint32(problem.EndColumn)
+	case end.Line == start.Line:
+		end = tokenEnd(content, start)
+	default:
+		end = lineEnd(content, end.Line)
+	}
+
+	return protocol.Range{Start: start, End: end}
+}
+
+// tokenEnd returns the end of the identifier or variable at the position, or
+// the end of the line if there is none, so the range is never empty.
+func tokenEnd(content string, pos protocol.Position) protocol.Position {
+	start := position.FromLSPPosition(content, pos)
+	end := start
+	for end < len(content) && isTokenByte(content[end]) {
+		end++
+	}
+
+	if end == start {
+		return lineEnd(content, pos.Line)
+	}
+
+	return position.ToLSPPosition(content, end)
+}
+
+func isTokenByte(b byte) bool {
+	return b == '_' || b == '$' || b == '\\' || b >= 0x80 ||
+		('a' <= b && b <= 'z') || ('A' <= b && b <= 'Z') || ('0' <= b && b <= '9')
+}
+
+// lineEnd returns the position of the end of the 0-based line.
+func lineEnd(content string, line uint32) protocol.Position {
+	return position.ToLSPPosition(
+		content,
+		position.FromLSPPosition(content, protocol.Position{Line: line, Character: math.MaxUint32}),
+	)
+}
@@ -0,0 +1,79 @@
+package diagnostics_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/laytan/go-lsp-protocol/pkg/lsp/protocol"
+	"github.com/laytan/phpls/internal/config"
+	"github.com/laytan/phpls/internal/diagnostics"
+	"github.com/laytan/phpls/internal/wrkspc"
+	"github.com/laytan/phpls/pkg/pathutils"
+	"github.com/laytan/phpls/pkg/phpversion"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomAnalyzerRanges(t *testing.T) {
+	root := t.TempDir()
+	config.Current = config.Default()
+	wrkspc.Current = wrkspc.New(
+		phpversion.EightOne(),
+		root,
+		filepath.Join(pathutils.Root(), "third_party", "phpstorm-stubs"),
+	)
+
+	analyzer := diagnostics.MakeCustom(config.Custom{
+		Name: "custom",
+		Command: []string{
+			"cat",
+			filepath.Join(pathutils.Root(), "internal", "diagnostics", "testdata", "custom.jsonl"),
+			"{stdin}",
+		},
+		Format: "JSON_LINES",
+	})
+
+	path := filepath.Join(root, "test.php")
+	code := []byte("<?php\n\n$unused = 1;\nundefined_function();\n")
+
+	out, err := analyzer.Analyze(context.Background(), path, code)
+	require.NoError(t, err)
+
+	rng := func(startLine, startChar, endLine, endChar uint32) protocol.Range {
+		return protocol.Range{
+			Start: protocol.Position{Line: startLine, Character: startChar},
+			End:   protocol.Position{Line: endLine, Character: endChar},
+		}
+	}
+
+	require.Equal(t, []protocol.Diagnostic{
+		{
+			Range:    rng(2, 0, 2, 7),
+			Severity: protocol.SeverityWarning,
+			Message:  "variable without an end column",
+		},
+		{
+			Range:    rng(2, 8, 2, 12),
+			Severity: protocol.SeverityWarning,
+			Code:     "operator",
+			Message:  "operator without an end column",
+		},
+		{
+			Range:    rng(3, 0, 3, 18),
+			Severity: protocol.SeverityWarning,
+			Code:     "call",
+			Message:  "call without an end column",
+		},
+		{
+			Range:    rng(3, 0, 3, 9),
+			Severity: protocol.SeverityWarning,
+			Code:     "ranged",
+			Message:  "with an end column",
+		},
+		{
+			Range:    rng(3, 0, 3, 21),
+			Severity: protocol.SeverityWarning,
+			Message:  "without a column",
+		},
+	}, out)
+}
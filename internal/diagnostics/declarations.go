@@ -0,0 +1,81 @@
+package diagnostics
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/laytan/go-lsp-protocol/pkg/lsp/protocol"
+	"github.com/laytan/phpls/internal/declarations"
+	"github.com/laytan/phpls/internal/wrkspc"
+	"github.com/laytan/phpls/pkg/parsing"
+	"github.com/laytan/phpls/pkg/phpversion"
+)
+
+// MakeDeclarations creates the analyzer reporting symbols that are declared in
+// multiple project files, and classes that don't match the PSR-4 autoload
+// mapping.
+func MakeDeclarations(phpv *phpversion.PHPVersion) *ASTAnalyzer {
+	return MakeAST("declarations", parsing.New(phpv), protocol.SeverityWarning, diagnoseDeclarations)
+}
+
+func diagnoseDeclarations(rooter *wrkspc.Rooter, content string) []protocol.Diagnostic {
+	violations := declarations.Diagnose(rooter)
+	diagnostics := make([]protocol.Diagnostic, 0, len(violations))
+	for _, violation := range violations {
+		diagnostic := protocol.Diagnostic{
+			Range:   nodeRange(content, violation.Node),
+			Code:    violation.Code(),
+			Message: violation.Message(),
+		}
+
+		switch violation.Kind {
+		case declarations.KindDuplicate:
+			diagnostic.Severity = protocol.SeverityError
+		case declarations.KindNamespaceMismatch:
+			// Without a namespace the diagnostic is at the class, it can't be
+			// replaced with the expected namespace.
+			if violation.Actual != "" {
+				diagnostic.Data = &NamespaceData{Namespace: violation.Expected}
+			}
+		}
+
+		diagnostics = append(diagnostics, diagnostic)
+	}
+
+	return diagnostics
+}
+
+// NamespaceData is the data of a PSR-4 namespace diagnostic, used to provide
+// the code action that corrects the namespace.
+type NamespaceData struct {
+	// The namespace the PSR-4 autoload mapping expects.
+	Namespace string `json:"namespace"`
+}
+
+// NamespaceDiagnostic returns the data of the diagnostic if it is a PSR-4
+// namespace diagnostic with a namespace to correct, the data is decoded if the
+// diagnostic came from the client.
+func NamespaceDiagnostic(d *protocol.Diagnostic) (*NamespaceData, bool) {
+	if d.Source != "phpls-declarations" || d.Code != "psr4-namespace" || d.Data == nil {
+		return nil, false
+	}
+
+	if data, ok := d.Data.(*NamespaceData); ok {
+		return data, true
+	}
+
+	raw, err := json.Marshal(d.Data)
+	if err != nil {
+		log.Println(fmt.Errorf("[diagnostics.NamespaceDiagnostic]: encoding data: %w", err))
+		return nil, false
+	}
+
+	data := &NamespaceData{}
+	if err := json.Unmarshal(raw, data); err != nil {
+		log.Println(fmt.Errorf("[diagnostics.NamespaceDiagnostic]: decoding data: %w", err))
+		return nil, false
+	}
+
+	return data, data.Namespace != ""
+}
@@ -0,0 +1,31 @@
+package diagnostics
+
+import (
+	"github.com/laytan/go-lsp-protocol/pkg/lsp/protocol"
+	"github.com/laytan/phpls/internal/deprecated"
+	"github.com/laytan/phpls/internal/wrkspc"
+	"github.com/laytan/phpls/pkg/parsing"
+	"github.com/laytan/phpls/pkg/phpversion"
+)
+
+// MakeDeprecated creates the analyzer reporting usages of classes, functions
+// and methods that are marked deprecated, as hints that editors render struck
+// through.
+func MakeDeprecated(phpv *phpversion.PHPVersion) *ASTAnalyzer {
+	return MakeAST("deprecated", parsing.New(phpv), protocol.SeverityHint, diagnoseDeprecated)
+}
+
+func diagnoseDeprecated(rooter *wrkspc.Rooter, content string) []protocol.Diagnostic {
+	violations := deprecated.Diagnose(rooter)
+	diagnostics := make([]protocol.Diagnostic, 0, len(violations))
+	for _, violation := range violations {
+		diagnostics = append(diagnostics, protocol.Diagnostic{
+			Range:   nodeRange(content, violation.Node),
+			Code:    violation.Code(),
+			Message: violation.Message(),
+			Tags:    []protocol.DiagnosticTag{protocol.Deprecated},
+		})
+	}
+
+	return diagnostics
+}
@@ -12,8 +12,13 @@ import (
 
 	"github.com/fsnotify/fsnotify"
 	"github.com/laytan/go-lsp-protocol/pkg/lsp/protocol"
+	"github.com/laytan/php-parser/pkg/ast"
 	"github.com/laytan/phpls/internal/config"
+	"github.com/laytan/phpls/internal/undefined"
+	"github.com/laytan/phpls/internal/wrkspc"
+	"github.com/laytan/phpls/pkg/baseline"
 	"github.com/laytan/phpls/pkg/lsprogress"
+	"github.com/laytan/phpls/pkg/position"
 	"github.com/laytan/phpls/pkg/set"
 )
 
@@ -41,6 +46,10 @@ type Runner struct {
 	watcher    *fsnotify.Watcher
 	watching   map[string]*set.Set[string]
 	watchingMu sync.Mutex
+
+	// Loaded the first time diagnostics are published.
+	baseline   *baseline.Baseline
+	baselineMu sync.Mutex
 }
 
 type fileDiagnostics struct {
@@ -50,6 +59,12 @@ type fileDiagnostics struct {
 	version         int
 	published       bool
 	cancel          context.CancelFunc
+	// Set when the file is not open, the diagnostics are from a workspace run
+	// or from before the file was closed.
+	workspace bool
+	// The code the (save) diagnostics were created from, to match the baseline.
+	content     string
+	saveContent string
 }
 
 func NewRunner(
@@ -72,60 +87,114 @@ func NewRunnerFromConfig(client protocol.Client) *Runner {
 		return nil
 	}
 
-	var analyzers []Analyzer
-	var saveAnalyzers []Analyzer
+	cfg := config.Current.Diagnostics
+	phpv := config.Current.PhpVersion
+	reg := &registry{}
+
+	reg.registerExec("phpcs", cfg.Phpcs.Analyzer, cfg.Phpcs.Binary, func(executable string) Analyzer {
+		return MakePhpcs(executable)
+	})
+	reg.registerExec("phpstan", cfg.Phpstan.Analyzer, cfg.Phpstan.Binary, func(executable string) Analyzer {
+		return MakePhpstan(executable, phpv)
+	})
+	reg.registerExec("psalm", cfg.Psalm.Analyzer, cfg.Psalm.Binary, func(executable string) Analyzer {
+		return &PsalmAnalyzer{Executable: executable}
+	})
+	reg.registerExec(
+		"php-cs-fixer",
+		config.Analyzer(cfg.PhpCsFixer),
+		config.Current.PhpCsFixer.Binary,
+		func(executable string) Analyzer {
+			return &PhpCsFixerAnalyzer{Executable: executable}
+		},
+	)
 
-	if config.Current.Diagnostics.Phpcs.Enabled {
-		if executable, ok := findExec(config.Current.Diagnostics.Phpcs.Binary); ok {
-			analyzer := MakePhpcs(executable)
-			switch config.Current.Diagnostics.Phpcs.Method {
-			case config.DiagnosticsOnSave:
-				log.Printf(
-					"[INFO]: phpcs diagnostics set up on save using binary at %q",
-					executable,
-				)
-				saveAnalyzers = append(saveAnalyzers, analyzer)
-			case config.DiagnosticsOnChange:
-				log.Printf(
-					"[INFO]: phpcs diagnostics set up on change using binary at %q",
-					executable,
-				)
-				analyzers = append(analyzers, analyzer)
-			}
-		} else {
-			log.Printf(
-				"[ERROR]: phpcs is enabled but no executable found in the following configured places: %v",
-				config.Current.Diagnostics.Phpcs.Binary,
-			)
+	reg.register("syntax", cfg.Syntax.Analyzer, MakeSyntax(phpv))
+	reg.register("throws", cfg.Throws.Analyzer, MakeThrows(phpv))
+	reg.register("undefined symbol", cfg.Undefined.Analyzer, MakeUndefined(
+		phpv,
+		cfg.Undefined.Severity,
+		undefined.Options{SuppressMagic: cfg.Undefined.SuppressMagic},
+	))
+	reg.register(
+		"compatibility",
+		config.Analyzer(cfg.Compatibility.SaveAnalyzer),
+		MakeCompatibility(config.Current.PhpMinVersion, phpv),
+	)
+	reg.register("deprecated usage", cfg.Deprecated.Analyzer, MakeDeprecated(phpv))
+	reg.register("unused code", cfg.Unused.Analyzer, MakeUnused(phpv))
+	reg.register("visibility", cfg.Visibility.Analyzer, MakeVisibility(phpv))
+	reg.register("argument", config.Analyzer(cfg.Arguments.SaveAnalyzer), MakeArguments(phpv))
+	reg.register("contract", config.Analyzer(cfg.Contracts.SaveAnalyzer), MakeContracts(phpv))
+	reg.register("PHPDoc", config.Analyzer(cfg.Phpdoc.SaveAnalyzer), MakePhpdoc(phpv))
+	reg.register(
+		"declaration",
+		config.Analyzer(cfg.Declarations.SaveAnalyzer),
+		MakeDeclarations(phpv),
+	)
+
+	for _, custom := range cfg.Custom {
+		method := config.DiagnosticsMethod(custom.Method)
+		if method != config.DiagnosticsOnChange {
+			method = config.DiagnosticsOnSave
 		}
+
+		reg.register(
+			custom.Name,
+			config.Analyzer{Enabled: true, Method: method},
+			MakeCustom(custom),
+		)
 	}
 
-	if config.Current.Diagnostics.Phpstan.Enabled {
-		if executable, ok := findExec(config.Current.Diagnostics.Phpstan.Binary); ok {
-			analyzer := &PhpstanAnalyzer{Executable: executable}
-			switch config.Current.Diagnostics.Phpstan.Method {
-			case config.DiagnosticsOnSave:
-				log.Printf(
-					"[INFO]: phpstan diagnostics set up on save with binary at %q",
-					executable,
-				)
-				saveAnalyzers = append(saveAnalyzers, analyzer)
-			case config.DiagnosticsOnChange:
-				log.Printf(
-					"[INFO]: phpstan diagnostics set up on change with binary at %q",
-					executable,
-				)
-				analyzers = append(analyzers, analyzer)
-			}
-		} else {
-			log.Printf(
-				"[ERROR]: phpstan is enabled but no executable found in the following configured places: %v",
-				config.Current.Diagnostics.Phpstan.Binary,
-			)
-		}
+	return NewRunner(client, reg.analyzers, reg.saveAnalyzers)
+}
+
+// registry collects the configured analyzers by when they run.
+type registry struct {
+	analyzers     []Analyzer
+	saveAnalyzers []Analyzer
+}
+
+// register adds the analyzer if it is enabled, name is used for logging.
+func (r *registry) register(name string, cfg config.Analyzer, analyzer Analyzer) {
+	if !cfg.Enabled {
+		return
 	}
 
-	return NewRunner(client, analyzers, saveAnalyzers)
+	switch cfg.Method {
+	case config.DiagnosticsOnSave:
+		log.Printf("[INFO]: %s diagnostics set up on save", name)
+		r.saveAnalyzers = append(r.saveAnalyzers, analyzer)
+	case config.DiagnosticsOnChange:
+		log.Printf("[INFO]: %s diagnostics set up on change", name)
+		r.analyzers = append(r.analyzers, analyzer)
+	}
+}
+
+// registerExec adds the analyzer of an external tool if it is enabled and an
+// executable is found in the configured places.
+func (r *registry) registerExec(
+	name string,
+	cfg config.Analyzer,
+	binary []string,
+	analyzer func(executable string) Analyzer,
+) {
+	if !cfg.Enabled {
+		return
+	}
+
+	executable, ok := findExec(binary)
+	if !ok {
+		log.Printf(
+			"[ERROR]: %s is enabled but no executable found in the following configured places: %v",
+			name,
+			binary,
+		)
+		return
+	}
+
+	log.Printf("[INFO]: using %s binary at %q", name, executable)
+	r.register(name, cfg, analyzer(executable))
 }
 
 func (r *Runner) Watch(path string) error {
@@ -166,6 +235,12 @@ func (r *Runner) Watch(path string) error {
 }
 
 func (r *Runner) StopWatching(path string) error {
+	r.diagnosticsMu.Lock()
+	if fd, ok := r.diagnostics[path]; ok {
+		fd.workspace = true
+	}
+	r.diagnosticsMu.Unlock()
+
 	r.watchingMu.Lock()
 	defer r.watchingMu.Unlock()
 
@@ -206,6 +281,7 @@ func (r *Runner) Run(ctx context.Context, version int, path string, code []byte)
 		r.analyzers,
 		version,
 		path,
+		string(code),
 		func(actx context.Context, a Analyzer) ([]protocol.Diagnostic, error) {
 			return a.Analyze(actx, path, code) // nolint:wrapcheck // Already wrapped by run.
 		},
@@ -213,7 +289,12 @@ func (r *Runner) Run(ctx context.Context, version int, path string, code []byte)
 	)
 }
 
-func (r *Runner) AddDiagnostics(path string, version int, diagnostics []protocol.Diagnostic) {
+func (r *Runner) AddDiagnostics(
+	path string,
+	version int,
+	content string,
+	diagnostics []protocol.Diagnostic,
+) {
 	r.diagnosticsMu.Lock()
 	defer r.diagnosticsMu.Unlock()
 
@@ -225,12 +306,14 @@ func (r *Runner) AddDiagnostics(path string, version int, diagnostics []protocol
 		}
 
 		fd.diagnostics = append(fd.diagnostics, diagnostics...)
+		fd.content = content
 		fd.published = false
 		return
 	}
 
 	if version > fd.version {
 		fd.diagnostics = diagnostics
+		fd.content = content
 		fd.published = false
 		fd.version = version
 		return
@@ -239,7 +322,12 @@ func (r *Runner) AddDiagnostics(path string, version int, diagnostics []protocol
 	log.Printf("[WARN]: trying to add outdated change diagnostics to %q", path)
 }
 
-func (r *Runner) AddSaveDiagnostics(path string, version int, diagnostics []protocol.Diagnostic) {
+func (r *Runner) AddSaveDiagnostics(
+	path string,
+	version int,
+	content string,
+	diagnostics []protocol.Diagnostic,
+) {
 	r.diagnosticsMu.Lock()
 	defer r.diagnosticsMu.Unlock()
 
@@ -251,12 +339,14 @@ func (r *Runner) AddSaveDiagnostics(path string, version int, diagnostics []prot
 		}
 
 		fd.saveDiagnostics = append(fd.saveDiagnostics, diagnostics...)
+		fd.saveContent = content
 		fd.published = false
 		return
 	}
 
 	if version > fd.version {
 		fd.saveDiagnostics = diagnostics
+		fd.saveContent = content
 		fd.published = false
 		fd.version = version
 		return
@@ -276,8 +366,11 @@ func (r *Runner) Publish(ctx context.Context, path string) error {
 		fd.published = true
 
 		diagnostics := make([]protocol.Diagnostic, 0, len(fd.diagnostics)+len(fd.saveDiagnostics))
-		diagnostics = append(diagnostics, fd.diagnostics...)
-		diagnostics = append(diagnostics, fd.saveDiagnostics...)
+		diagnostics = append(diagnostics, r.withoutBaseline(path, fd.content, fd.diagnostics)...)
+		diagnostics = append(
+			diagnostics,
+			r.withoutBaseline(path, fd.saveContent, fd.saveDiagnostics)...,
+		)
 
 		log.Printf("[INFO]: publishing diagnostic update: %d diagnostics", len(diagnostics))
 
@@ -313,14 +406,16 @@ func (r *Runner) setupPath(path string, version int) {
 	r.diagnosticsMu.Lock()
 	defer r.diagnosticsMu.Unlock()
 
-	if _, ok := r.diagnostics[path]; ok {
+	fd, ok := r.diagnostics[path]
+	if ok && !fd.workspace {
 		return
 	}
 
+	// The results of a workspace run are cleared when the file is opened.
 	r.diagnostics[path] = &fileDiagnostics{
 		path:      path,
 		version:   version,
-		published: true,
+		published: !ok,
 	}
 }
 
@@ -344,8 +439,9 @@ func (r *Runner) run(
 	analyzers []Analyzer,
 	version int,
 	path string,
+	content string,
 	analyzeFunc func(context.Context, Analyzer) ([]protocol.Diagnostic, error),
-	addFunc func(string, int, []protocol.Diagnostic),
+	addFunc func(string, int, string, []protocol.Diagnostic),
 ) (reserr error) {
 	logTime := timeDiagnostics("all")
 	defer logTime()
@@ -390,7 +486,7 @@ Loop:
 				break Loop
 			}
 		case res := <-resultsC:
-			addFunc(path, version, res)
+			addFunc(path, version, content, res)
 
 			waitingFor--
 			if waitingFor == 0 {
@@ -441,11 +537,13 @@ func (r *Runner) runForSave(path string) {
 	}
 	r.diagnosticsMu.Unlock()
 
+	// The save analyzers analyze the saved file.
 	err = r.run(
 		context.Background(),
 		r.saveAnalyzers,
 		version,
 		path,
+		wrkspc.Current.FContentOf(path),
 		func(ctx context.Context, a Analyzer) ([]protocol.Diagnostic, error) {
 			return a.AnalyzeSave( // nolint:wrapcheck // Already wrapped by run.
 				ctx,
@@ -538,6 +636,29 @@ func normalizeDiagnostics(name string, diagnostics []protocol.Diagnostic) []prot
 	return diagnostics
 }
 
+// nodeRange converts the position of the node into a LSP range, using the byte
+// offsets because the columns of some nodes (throw statements) are not reliable.
+func nodeRange(content string, node ast.Vertex) protocol.Range {
+	pos := node.GetPosition()
+	return protocol.Range{
+		Start: position.ToLSPPosition(content, pos.StartPos),
+		End:   position.ToLSPPosition(content, pos.EndPos),
+	}
+}
+
+func toSeverity(severity config.DiagnosticsSeverity) protocol.DiagnosticSeverity {
+	switch severity {
+	case config.DiagnosticsSeverityWarning:
+		return protocol.SeverityWarning
+	case config.DiagnosticsSeverityInformation:
+		return protocol.SeverityInformation
+	case config.DiagnosticsSeverityHint:
+		return protocol.SeverityHint
+	default:
+		return protocol.SeverityError
+	}
+}
+
 func findExec(tries []string) (string, bool) {
 	for _, try := range tries {
 		if path, err := exec.LookPath(try); err == nil {
@@ -2,6 +2,7 @@ package diagnostics
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
@@ -60,6 +61,36 @@ func (p *PhpcsAnalyzer) Close() {
 	p.instance.Close()
 }
 
+// PhpcsData is the data of a phpcs diagnostic, used to provide code actions.
+type PhpcsData struct {
+	Fixable bool `json:"fixable"`
+}
+
+// PhpcsDiagnostic returns the data of the diagnostic if it is a phpcs
+// diagnostic, the data is decoded if the diagnostic came from the client.
+func PhpcsDiagnostic(d *protocol.Diagnostic) (*PhpcsData, bool) {
+	if d.Source != "phpls-phpcs" {
+		return nil, false
+	}
+
+	if data, ok := d.Data.(*PhpcsData); ok {
+		return data, true
+	}
+
+	raw, err := json.Marshal(d.Data)
+	if err != nil {
+		log.Println(fmt.Errorf("[diagnostics.PhpcsDiagnostic]: encoding data: %w", err))
+		return &PhpcsData{}, true
+	}
+
+	data := &PhpcsData{}
+	if err := json.Unmarshal(raw, data); err != nil {
+		log.Println(fmt.Errorf("[diagnostics.PhpcsDiagnostic]: decoding data: %w", err))
+	}
+
+	return data, true
+}
+
 func phpcsMessageToDiagnostic(m *phpcs.ReportMessage) protocol.Diagnostic {
 	pos := protocol.Position{Line: uint32(m.Row) - 1, Character: uint32(m.Column) - 1}
 	return protocol.Diagnostic{
@@ -74,9 +105,8 @@ func phpcsMessageToDiagnostic(m *phpcs.ReportMessage) protocol.Diagnostic {
 
 		// CodeDescription:    &protocol.CodeDescription{},
 		// RelatedInformation: []protocol.DiagnosticRelatedInformation{},
-		//
-		// TODO: if fixable, give user a code action to fix current file.
-		// Data:               nil,
+
+		Data: &PhpcsData{Fixable: m.Fixable},
 	}
 }
 
@@ -0,0 +1,154 @@
+package diagnostics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/laytan/go-lsp-protocol/pkg/lsp/protocol"
+	"github.com/laytan/phpls/internal/wrkspc"
+	"github.com/laytan/phpls/pkg/diff"
+	"github.com/laytan/phpls/pkg/phpcsfixer"
+	"golang.org/x/sync/errgroup"
+)
+
+// PhpCsFixerAnalyzer does a dry run of php-cs-fixer and reports the lines that
+// would be changed, and by which rules.
+type PhpCsFixerAnalyzer struct {
+	Executable string
+}
+
+var _ Analyzer = &PhpCsFixerAnalyzer{}
+
+func (p *PhpCsFixerAnalyzer) Name() string {
+	return "php-cs-fixer"
+}
+
+func (p *PhpCsFixerAnalyzer) Analyze(
+	ctx context.Context,
+	path string,
+	code []byte,
+) ([]protocol.Diagnostic, error) {
+	res, err := phpcsfixer.Fix(ctx, p.Executable, path, code)
+	if errors.Is(err, phpcsfixer.ErrCancelled) {
+		log.Printf("[DEBUG]: php-cs-fixer cancelled: %v", err)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("analyzing with php-cs-fixer: %w", err)
+	}
+
+	if len(res.AppliedFixers) == 0 {
+		return nil, nil
+	}
+
+	content := string(code)
+	d := diff.New(content, string(res.Fixed))
+
+	rules, err := p.attribute(ctx, path, code, res.AppliedFixers, d.Hunks)
+	if errors.Is(err, phpcsfixer.ErrCancelled) {
+		log.Printf("[DEBUG]: php-cs-fixer cancelled: %v", err)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("attributing php-cs-fixer changes to rules: %w", err)
+	}
+
+	diagnostics := make([]protocol.Diagnostic, 0, len(d.Hunks))
+	for i, h := range d.Hunks {
+		msg := "Would be changed by rule " + rules[i][0]
+		if len(rules[i]) > 1 {
+			msg = "Would be changed by rules " + strings.Join(rules[i], ", ")
+		}
+
+		diagnostics = append(diagnostics, protocol.Diagnostic{
+			Range:    d.Edit(h).Range,
+			Severity: protocol.SeverityWarning,
+			Code:     strings.Join(rules[i], ","),
+			Message:  msg,
+		})
+	}
+
+	return diagnostics, nil
+}
+
+func (p *PhpCsFixerAnalyzer) AnalyzeSave(
+	ctx context.Context,
+	path string,
+) ([]protocol.Diagnostic, error) {
+	return p.Analyze(ctx, path, []byte(wrkspc.Current.FContentOf(path)))
+}
+
+// attribute returns the rules that change each hunk, php-cs-fixer only reports
+// the rules applied to the whole file, so when multiple rules are applied,
+// each of them is ran separately to see which lines it changes.
+func (p *PhpCsFixerAnalyzer) attribute(
+	ctx context.Context,
+	path string,
+	code []byte,
+	applied []string,
+	hunks []diff.Hunk,
+) ([][]string, error) {
+	rules := make([][]string, len(hunks))
+	if len(applied) == 1 {
+		for i := range hunks {
+			rules[i] = applied
+		}
+
+		return rules, nil
+	}
+
+	ruleHunks := make([][]diff.Hunk, len(applied))
+	g, gctx := errgroup.WithContext(ctx)
+	for i, rule := range applied {
+		i, rule := i, rule
+		g.Go(func() error {
+			res, err := phpcsfixer.Fix(gctx, p.Executable, path, code, rule)
+			if err != nil {
+				return fmt.Errorf("fixing only %s: %w", rule, err)
+			}
+
+			ruleHunks[i] = diff.New(string(code), string(res.Fixed)).Hunks
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	for i, h := range hunks {
+		for ruleI, rule := range applied {
+			for _, ruleHunk := range ruleHunks[ruleI] {
+				if overlaps(h, ruleHunk) {
+					rules[i] = append(rules[i], rule)
+					break
+				}
+			}
+		}
+
+		// Rules can depend on each other's changes, fall back to all of them.
+		if len(rules[i]) == 0 {
+			rules[i] = applied
+		}
+	}
+
+	return rules, nil
+}
+
+// overlaps returns whether the hunks change any of the same old lines, an
+// insertion is considered to change the line it is inserted at.
+func overlaps(a diff.Hunk, b diff.Hunk) bool {
+	aEnd, bEnd := a.OldEnd, b.OldEnd
+	if aEnd == a.OldStart {
+		aEnd++
+	}
+
+	if bEnd == b.OldStart {
+		bEnd++
+	}
+
+	return a.OldStart < bEnd && b.OldStart < aEnd
+}
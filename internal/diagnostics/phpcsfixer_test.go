@@ -0,0 +1,64 @@
+package diagnostics_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/laytan/go-lsp-protocol/pkg/lsp/protocol"
+	"github.com/laytan/phpls/internal/diagnostics"
+	"github.com/laytan/phpls/pkg/pathutils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPhpCsFixerAnalyzer(t *testing.T) {
+	t.Parallel()
+
+	analyzer := &diagnostics.PhpCsFixerAnalyzer{
+		Executable: filepath.Join(pathutils.Root(), "pkg", "phpcsfixer", "testdata", "php-cs-fixer.sh"),
+	}
+
+	code := "<?php\n\n$a = 1;  \n$b = 2;\necho \"a\";\n$c = 3;\necho \"b\";  \n"
+	out, err := analyzer.Analyze(
+		context.Background(),
+		filepath.Join(t.TempDir(), "test.php"),
+		[]byte(code),
+	)
+	require.NoError(t, err)
+	rng := func(line uint32) protocol.Range {
+		return protocol.Range{
+			Start: protocol.Position{Line: line},
+			End:   protocol.Position{Line: line + 1},
+		}
+	}
+
+	// Each changed line is attributed to the rules that change it.
+	require.Equal(t, []protocol.Diagnostic{
+		{
+			Range:    rng(2),
+			Severity: protocol.SeverityWarning,
+			Code:     "no_trailing_whitespace",
+			Message:  "Would be changed by rule no_trailing_whitespace",
+		},
+		{
+			Range:    rng(4),
+			Severity: protocol.SeverityWarning,
+			Code:     "single_quote",
+			Message:  "Would be changed by rule single_quote",
+		},
+		{
+			Range:    rng(6),
+			Severity: protocol.SeverityWarning,
+			Code:     "no_trailing_whitespace,single_quote",
+			Message:  "Would be changed by rules no_trailing_whitespace, single_quote",
+		},
+	}, out)
+
+	out, err = analyzer.Analyze(
+		context.Background(),
+		filepath.Join(t.TempDir(), "test.php"),
+		[]byte("<?php\n\necho 'a';\n"),
+	)
+	require.NoError(t, err)
+	require.Empty(t, out)
+}
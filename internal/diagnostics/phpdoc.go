@@ -0,0 +1,34 @@
+package diagnostics
+
+import (
+	"github.com/laytan/go-lsp-protocol/pkg/lsp/protocol"
+	"github.com/laytan/phpls/internal/phpdoc"
+	"github.com/laytan/phpls/internal/wrkspc"
+	"github.com/laytan/phpls/pkg/parsing"
+	"github.com/laytan/phpls/pkg/phpversion"
+	"github.com/laytan/phpls/pkg/position"
+)
+
+// MakePhpdoc creates the analyzer reporting unparsable doc types, @param tags
+// not matching the parameters, misplaced @return tags and unknown classes in
+// doc types.
+func MakePhpdoc(phpv *phpversion.PHPVersion) *ASTAnalyzer {
+	return MakeAST("phpdoc", parsing.New(phpv), protocol.SeverityWarning, diagnosePhpdoc)
+}
+
+func diagnosePhpdoc(rooter *wrkspc.Rooter, content string) []protocol.Diagnostic {
+	violations := phpdoc.Diagnose(rooter)
+	diagnostics := make([]protocol.Diagnostic, 0, len(violations))
+	for _, violation := range violations {
+		diagnostics = append(diagnostics, protocol.Diagnostic{
+			Range: protocol.Range{
+				Start: position.ToLSPPosition(content, violation.Position.StartPos),
+				End:   position.ToLSPPosition(content, violation.Position.EndPos),
+			},
+			Code:    violation.Code(),
+			Message: violation.Message(),
+		})
+	}
+
+	return diagnostics
+}
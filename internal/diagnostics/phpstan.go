@@ -5,18 +5,36 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"math"
+	"regexp"
+	"strings"
 
 	"github.com/laytan/go-lsp-protocol/pkg/lsp/protocol"
-	"github.com/laytan/phpls/pkg/functional"
+	"github.com/laytan/php-parser/pkg/ast"
+	"github.com/laytan/php-parser/pkg/visitor"
+	"github.com/laytan/php-parser/pkg/visitor/traverser"
+	"github.com/laytan/phpls/internal/wrkspc"
+	"github.com/laytan/phpls/pkg/nodeident"
+	"github.com/laytan/phpls/pkg/parsing"
 	"github.com/laytan/phpls/pkg/phpstan"
+	"github.com/laytan/phpls/pkg/phpversion"
+	"github.com/laytan/phpls/pkg/position"
 )
 
 type PhpstanAnalyzer struct {
 	Executable string
+	parser     parsing.Parser
 }
 
 var _ Analyzer = &PhpstanAnalyzer{}
 
+func MakePhpstan(executable string, phpv *phpversion.PHPVersion) *PhpstanAnalyzer {
+	return &PhpstanAnalyzer{
+		Executable: executable,
+		parser:     parsing.New(phpv),
+	}
+}
+
 func (p *PhpstanAnalyzer) Name() string {
 	return "phpstan"
 }
@@ -26,40 +44,20 @@ func (p *PhpstanAnalyzer) Analyze(
 	path string,
 	code []byte,
 ) ([]protocol.Diagnostic, error) {
-	return transformPhpstanResult(phpstan.Analyze(ctx, p.Executable, path, code))
+	msgs, err := phpstan.Analyze(ctx, p.Executable, path, code)
+	return p.transformResult(code, msgs, err)
 }
 
 func (p *PhpstanAnalyzer) AnalyzeSave(
 	ctx context.Context,
 	path string,
 ) ([]protocol.Diagnostic, error) {
-	return transformPhpstanResult(phpstan.AnalyzePath(ctx, p.Executable, path))
+	msgs, err := phpstan.AnalyzePath(ctx, p.Executable, path)
+	return p.transformResult([]byte(wrkspc.Current.FContentOf(path)), msgs, err)
 }
 
-func phpstanMessageToDiagnostic(m *phpstan.ReportMessage) protocol.Diagnostic {
-	return protocol.Diagnostic{
-		Range: protocol.Range{
-			Start: protocol.Position{
-				Line:      uint32(m.Ln - 1),
-				Character: 0,
-			},
-			End: protocol.Position{
-				Line:      uint32(m.Ln),
-				Character: 0,
-			},
-		},
-		Severity: protocol.SeverityError,
-		// Code:               nil,
-		// CodeDescription:    &protocol.CodeDescription{},
-		// Source:  "phpls:phpstan",
-		Message: m.Msg,
-		// Tags:               []protocol.DiagnosticTag{},
-		// RelatedInformation: []protocol.DiagnosticRelatedInformation{},
-		// Data:               nil,
-	}
-}
-
-func transformPhpstanResult(
+func (p *PhpstanAnalyzer) transformResult(
+	code []byte,
 	msgs []*phpstan.ReportMessage,
 	err error,
 ) ([]protocol.Diagnostic, error) {
@@ -71,5 +69,153 @@ func transformPhpstanResult(
 		return nil, fmt.Errorf("analyzing with phpstan: %w", err)
 	}
 
-	return functional.Map(msgs, phpstanMessageToDiagnostic), nil
+	if len(msgs) == 0 {
+		return nil, nil
+	}
+
+	// Without an AST, whole lines are marked.
+	root, err := p.parser.Parse(code)
+	if err != nil {
+		log.Printf("[DEBUG]: parsing for phpstan diagnostic ranges: %v", err)
+	}
+
+	content := string(code)
+	diagnostics := make([]protocol.Diagnostic, 0, len(msgs))
+	for _, m := range msgs {
+		var identifier any
+		if m.Identifier != "" {
+			identifier = m.Identifier
+		}
+
+		msg := m.Msg
+		if m.Tip != "" {
+			msg += "\n" + m.Tip
+		}
+
+		diagnostics = append(diagnostics, protocol.Diagnostic{
+			Range:    phpstanRange(content, root, m),
+			Severity: protocol.SeverityError,
+			Code:     identifier,
+			Message:  msg,
+		})
+	}
+
+	return diagnostics, nil
+}
+
+var (
+	// Methods, functions, properties and constants, like Foo::bar(), baz(), Foo::$qux and Foo::QUX.
+	phpstanMemberRgx = regexp.MustCompile(
+		`(?:::\$?|->)(\w+)|([\w\\]+)\(\)|(?i)\b(?:function|constant) ([\w\\]+)(?:[^:\w\\]|$)`,
+	)
+	phpstanVarRgx   = regexp.MustCompile(`\$(\w+)`)
+	phpstanClassRgx = regexp.MustCompile(`(?i)(?:class|interface|trait|enum) ([\w\\]+)|[\w\\]*\\(\w+)`)
+)
+
+// phpstanRange narrows the line of the message down to the node the message is
+// about, by matching the symbols mentioned in the message with the nodes on
+// the line. Members are preferred over variables, which are preferred over classes.
+func phpstanRange(content string, root *ast.Root, m *phpstan.ReportMessage) protocol.Range {
+	if root != nil {
+		v := &phpstanLineVisitor{line: m.Ln}
+		root.Accept(traverser.NewTraverser(v))
+
+		for _, rgx := range []*regexp.Regexp{phpstanMemberRgx, phpstanVarRgx, phpstanClassRgx} {
+			for _, subject := range phpstanSubjects(rgx, m.Msg) {
+				if node := v.smallestMatching(subject); node != nil {
+					return nodeRange(content, node)
+				}
+			}
+		}
+	}
+
+	// Mark the line, without the indentation.
+	line := uint32(m.Ln - 1)
+	start := position.FromLSPPosition(content, protocol.Position{Line: line})
+	end := position.FromLSPPosition(content, protocol.Position{Line: line, Character: math.MaxUint32})
+	indent := len(content[start:end]) - len(strings.TrimLeft(content[start:end], " \t"))
+	return protocol.Range{
+		Start: position.ToLSPPosition(content, start+indent),
+		End:   position.ToLSPPosition(content, end),
+	}
+}
+
+// phpstanSubjects returns the unqualified names matched in the message.
+func phpstanSubjects(rgx *regexp.Regexp, msg string) []string {
+	var subjects []string
+	for _, match := range rgx.FindAllStringSubmatch(msg, -1) {
+		for _, group := range match[1:] {
+			if group == "" {
+				continue
+			}
+
+			subjects = append(subjects, unqualified(group))
+		}
+	}
+
+	return subjects
+}
+
+func unqualified(name string) string {
+	name = strings.TrimLeft(name, `$\`)
+	if i := strings.LastIndex(name, `\`); i != -1 {
+		return name[i+1:]
+	}
+
+	return name
+}
+
+// phpstanLineVisitor collects the identifiers, variables and names that start
+// on the line.
+type phpstanLineVisitor struct {
+	visitor.Null
+
+	line  int
+	nodes []ast.Vertex
+}
+
+func (v *phpstanLineVisitor) EnterNode(node ast.Vertex) bool {
+	pos := node.GetPosition()
+	if pos == nil {
+		return true
+	}
+
+	// Children are inside the position of their parent.
+	if pos.StartLine > v.line || pos.EndLine < v.line {
+		return false
+	}
+
+	if pos.StartLine != v.line {
+		return true
+	}
+
+	switch node.(type) {
+	case *ast.Identifier, *ast.Name, *ast.NameFullyQualified, *ast.NameRelative:
+		v.nodes = append(v.nodes, node)
+	case *ast.ExprVariable:
+		if _, ok := node.(*ast.ExprVariable).Name.(*ast.Identifier); ok {
+			v.nodes = append(v.nodes, node)
+		}
+
+		return false
+	}
+
+	return true
+}
+
+func (v *phpstanLineVisitor) smallestMatching(subject string) ast.Vertex {
+	var smallest ast.Vertex
+	for _, node := range v.nodes {
+		if !strings.EqualFold(unqualified(nodeident.Get(node)), subject) {
+			continue
+		}
+
+		pos := node.GetPosition()
+		if smallest == nil ||
+			pos.EndPos-pos.StartPos < smallest.GetPosition().EndPos-smallest.GetPosition().StartPos {
+			smallest = node
+		}
+	}
+
+	return smallest
 }
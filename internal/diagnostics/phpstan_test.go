@@ -0,0 +1,93 @@
+package diagnostics_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/laytan/go-lsp-protocol/pkg/lsp/protocol"
+	"github.com/laytan/phpls/internal/diagnostics"
+	"github.com/laytan/phpls/pkg/pathutils"
+	"github.com/laytan/phpls/pkg/phpversion"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPhpstanAnalyzer(t *testing.T) {
+	t.Parallel()
+
+	testdata := filepath.Join(pathutils.Root(), "pkg", "phpstan", "testdata")
+	code, err := os.ReadFile(filepath.Join(testdata, "ranges.php"))
+	require.NoError(t, err)
+
+	analyzer := diagnostics.MakePhpstan(
+		filepath.Join(testdata, "phpstan.sh"),
+		phpversion.EightOne(),
+	)
+	out, err := analyzer.Analyze(
+		context.Background(),
+		filepath.Join(t.TempDir(), "ranges.php"),
+		code,
+	)
+	require.NoError(t, err)
+	rng := func(line, start, end uint32) protocol.Range {
+		return protocol.Range{
+			Start: protocol.Position{Line: line, Character: start},
+			End:   protocol.Position{Line: line, Character: end},
+		}
+	}
+
+	// Each message is narrowed down to the node it is about.
+	expected := []struct {
+		code    string
+		message string
+		rng     protocol.Range
+	}{
+		{
+			code:    "property.notFound",
+			message: "Access to an undefined property App\\User::$nmae.\nLearn more: https://phpstan.org/blog/solving-phpstan-access-to-undefined-property",
+			rng:     rng(8, 22, 26),
+		},
+		{
+			code:    "method.notFound",
+			message: "Call to an undefined method App\\Greeter::greetUser().",
+			rng:     rng(13, 25, 34),
+		},
+		{
+			code:    "function.notFound",
+			message: "Function undefined_function not found.\nLearn more at https://phpstan.org/user-guide/discovering-symbols",
+			rng:     rng(13, 42, 60),
+		},
+		{
+			code:    "variable.undefined",
+			message: "Undefined variable: $totl",
+			rng:     rng(19, 15, 20),
+		},
+		{
+			code:    "class.notFound",
+			message: "Instantiated class App\\Usr not found.",
+			rng:     rng(24, 19, 22),
+		},
+		{
+			code:    "classConstant.notFound",
+			message: "Access to undefined constant App\\User::MISSING.",
+			rng:     rng(29, 21, 28),
+		},
+		{
+			// Nothing to narrow down to, the line without indentation is marked.
+			code:    "deadCode.unreachable",
+			message: "Unreachable statement - code above always terminates.",
+			rng:     rng(35, 8, 27),
+		},
+	}
+
+	require.Len(t, out, len(expected))
+	for i, e := range expected {
+		require.Equal(t, protocol.Diagnostic{
+			Range:    e.rng,
+			Severity: protocol.SeverityError,
+			Code:     e.code,
+			Message:  e.message,
+		}, out[i])
+	}
+}
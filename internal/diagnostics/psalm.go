@@ -0,0 +1,83 @@
+package diagnostics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/laytan/go-lsp-protocol/pkg/lsp/protocol"
+	"github.com/laytan/phpls/internal/wrkspc"
+	"github.com/laytan/phpls/pkg/position"
+	"github.com/laytan/phpls/pkg/psalm"
+)
+
+type PsalmAnalyzer struct {
+	Executable string
+}
+
+var _ Analyzer = &PsalmAnalyzer{}
+
+func (p *PsalmAnalyzer) Name() string {
+	return "psalm"
+}
+
+func (p *PsalmAnalyzer) Analyze(
+	ctx context.Context,
+	path string,
+	code []byte,
+) ([]protocol.Diagnostic, error) {
+	issues, err := psalm.Analyze(ctx, p.Executable, path, code)
+	return transformPsalmResult(string(code), issues, err)
+}
+
+func (p *PsalmAnalyzer) AnalyzeSave(
+	ctx context.Context,
+	path string,
+) ([]protocol.Diagnostic, error) {
+	issues, err := psalm.AnalyzePath(ctx, p.Executable, path)
+	return transformPsalmResult(wrkspc.Current.FContentOf(path), issues, err)
+}
+
+func psalmIssueToDiagnostic(content string, issue *psalm.Issue) protocol.Diagnostic {
+	diagnostic := protocol.Diagnostic{
+		Range: protocol.Range{
+			Start: position.ToLSPPosition(content, issue.From),
+			End:   position.ToLSPPosition(content, issue.To),
+		},
+		Severity: protocol.SeverityError,
+		Code:     issue.Type,
+		Message:  issue.Msg,
+	}
+
+	if issue.Severity == psalm.Info {
+		diagnostic.Severity = protocol.SeverityInformation
+	}
+
+	if issue.Link != "" {
+		diagnostic.CodeDescription = &protocol.CodeDescription{Href: protocol.URI(issue.Link)}
+	}
+
+	return diagnostic
+}
+
+func transformPsalmResult(
+	content string,
+	issues []*psalm.Issue,
+	err error,
+) ([]protocol.Diagnostic, error) {
+	if errors.Is(err, psalm.ErrCancelled) {
+		log.Printf("[DEBUG]: psalm cancelled: %v", err)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("analyzing with psalm: %w", err)
+	}
+
+	diagnostics := make([]protocol.Diagnostic, 0, len(issues))
+	for _, issue := range issues {
+		diagnostics = append(diagnostics, psalmIssueToDiagnostic(content, issue))
+	}
+
+	return diagnostics, nil
+}
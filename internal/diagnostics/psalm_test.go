@@ -0,0 +1,48 @@
+package diagnostics_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/laytan/go-lsp-protocol/pkg/lsp/protocol"
+	"github.com/laytan/phpls/internal/diagnostics"
+	"github.com/laytan/phpls/pkg/pathutils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPsalmAnalyzer(t *testing.T) {
+	t.Parallel()
+
+	analyzer := &diagnostics.PsalmAnalyzer{
+		Executable: filepath.Join(pathutils.Root(), "pkg", "psalm", "testdata", "psalm_issues.sh"),
+	}
+
+	path := filepath.Join(t.TempDir(), "test.php")
+	code := []byte("<?php\n\n$unused = 1;\nundefined_function();\n")
+
+	out, err := analyzer.Analyze(context.Background(), path, code)
+	require.NoError(t, err)
+	require.Equal(t, []protocol.Diagnostic{
+		{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 2, Character: 0},
+				End:   protocol.Position{Line: 2, Character: 7},
+			},
+			Severity:        protocol.SeverityInformation,
+			Code:            "UnusedVariable",
+			CodeDescription: &protocol.CodeDescription{Href: "https://psalm.dev/024"},
+			Message:         "$unused is never referenced or the value is not used",
+		},
+		{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 3, Character: 0},
+				End:   protocol.Position{Line: 3, Character: 20},
+			},
+			Severity:        protocol.SeverityError,
+			Code:            "UndefinedFunction",
+			CodeDescription: &protocol.CodeDescription{Href: "https://psalm.dev/021"},
+			Message:         "Function undefined_function does not exist",
+		},
+	}, out)
+}
@@ -0,0 +1,70 @@
+package diagnostics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/laytan/go-lsp-protocol/pkg/lsp/protocol"
+	"github.com/laytan/phpls/internal/wrkspc"
+	"github.com/laytan/phpls/pkg/parsing"
+	"github.com/laytan/phpls/pkg/phpversion"
+	"github.com/laytan/phpls/pkg/position"
+)
+
+// SyntaxAnalyzer reports the syntax errors the parser encounters, it does not
+// need any external tools.
+type SyntaxAnalyzer struct {
+	parser parsing.Parser
+}
+
+var _ Analyzer = &SyntaxAnalyzer{}
+
+func MakeSyntax(phpv *phpversion.PHPVersion) *SyntaxAnalyzer {
+	return &SyntaxAnalyzer{
+		parser: parsing.New(phpv),
+	}
+}
+
+func (s *SyntaxAnalyzer) Name() string {
+	return "syntax"
+}
+
+func (s *SyntaxAnalyzer) Analyze(
+	ctx context.Context,
+	path string,
+	code []byte,
+) ([]protocol.Diagnostic, error) {
+	// A panicking parser still reports where it failed as a syntax error.
+	_, syntaxErrs, err := s.parser.ParseWithErrors(code)
+	if err != nil && !errors.Is(err, parsing.ErrNoContent) && !errors.Is(err, parsing.ErrPanic) {
+		return nil, fmt.Errorf("parsing %q for syntax errors: %w", path, err)
+	}
+
+	content := string(code)
+	diagnostics := make([]protocol.Diagnostic, 0, len(syntaxErrs))
+	for _, syntaxErr := range syntaxErrs {
+		var rng protocol.Range
+		if syntaxErr.Pos != nil {
+			rng = protocol.Range{
+				Start: position.ToLSPPosition(content, syntaxErr.Pos.StartPos),
+				End:   position.ToLSPPosition(content, syntaxErr.Pos.EndPos),
+			}
+		}
+
+		diagnostics = append(diagnostics, protocol.Diagnostic{
+			Range:    rng,
+			Severity: protocol.SeverityError,
+			Message:  syntaxErr.Msg,
+		})
+	}
+
+	return diagnostics, nil
+}
+
+func (s *SyntaxAnalyzer) AnalyzeSave(
+	ctx context.Context,
+	path string,
+) ([]protocol.Diagnostic, error) {
+	return s.Analyze(ctx, path, []byte(wrkspc.Current.FContentOf(path)))
+}
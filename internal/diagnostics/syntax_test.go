@@ -0,0 +1,72 @@
+package diagnostics_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/laytan/go-lsp-protocol/pkg/lsp/protocol"
+	"github.com/laytan/phpls/internal/diagnostics"
+	"github.com/laytan/phpls/pkg/phpversion"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyntaxAnalyzer(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name   string
+		input  string
+		expect []protocol.Diagnostic
+	}{
+		{
+			name:   "valid",
+			input:  "<?php\n\necho 'hello';\n",
+			expect: []protocol.Diagnostic{},
+		},
+		{
+			name:  "one line incomplete",
+			input: "<?php echo ?>",
+			expect: []protocol.Diagnostic{
+				{
+					Range: protocol.Range{
+						Start: protocol.Position{Line: 0, Character: 11},
+						End:   protocol.Position{Line: 0, Character: 13},
+					},
+					Severity: protocol.SeverityError,
+					Message:  "syntax error: unexpected ';'",
+				},
+			},
+		},
+		{
+			name: "multi line weird whiles",
+			input: `
+<?php
+    do
+     }   echo 'dowhile test';
+    while (false);
+?>`,
+			expect: []protocol.Diagnostic{
+				{
+					Range: protocol.Range{
+						Start: protocol.Position{Line: 2, Character: 4},
+						End:   protocol.Position{Line: 2, Character: 6},
+					},
+					Severity: protocol.SeverityError,
+					Message:  `syntax error: unable to parse the code after "do"`,
+				},
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			out, err := diagnostics.MakeSyntax(phpversion.EightOne()).
+				Analyze(context.Background(), "test.php", []byte(scenario.input))
+			require.NoError(t, err)
+			require.Equal(t, scenario.expect, out)
+		})
+	}
+}
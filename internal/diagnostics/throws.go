@@ -0,0 +1,30 @@
+package diagnostics
+
+import (
+	"github.com/laytan/go-lsp-protocol/pkg/lsp/protocol"
+	"github.com/laytan/phpls/internal/throws"
+	"github.com/laytan/phpls/internal/wrkspc"
+	"github.com/laytan/phpls/pkg/parsing"
+	"github.com/laytan/phpls/pkg/phpversion"
+)
+
+// MakeThrows creates the analyzer reporting thrown exceptions that are not
+// caught or documented with @throws, at each site they are thrown from.
+func MakeThrows(phpv *phpversion.PHPVersion) *ASTAnalyzer {
+	return MakeAST("throws", parsing.New(phpv), protocol.SeverityWarning, diagnoseThrows)
+}
+
+func diagnoseThrows(rooter *wrkspc.Rooter, content string) []protocol.Diagnostic {
+	var diagnostics []protocol.Diagnostic
+	for _, violation := range throws.Diagnose(rooter) {
+		for _, site := range violation.Sites {
+			diagnostics = append(diagnostics, protocol.Diagnostic{
+				Range:   nodeRange(content, site.Node),
+				Code:    violation.Code(),
+				Message: violation.SiteMessage(site),
+			})
+		}
+	}
+
+	return diagnostics
+}
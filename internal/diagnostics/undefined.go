@@ -0,0 +1,38 @@
+package diagnostics
+
+import (
+	"github.com/laytan/go-lsp-protocol/pkg/lsp/protocol"
+	"github.com/laytan/phpls/internal/config"
+	"github.com/laytan/phpls/internal/undefined"
+	"github.com/laytan/phpls/internal/wrkspc"
+	"github.com/laytan/phpls/pkg/parsing"
+	"github.com/laytan/phpls/pkg/phpversion"
+)
+
+// MakeUndefined creates the analyzer reporting references to classes,
+// functions, constants and members that are not defined anywhere in the
+// project or stubs.
+func MakeUndefined(
+	phpv *phpversion.PHPVersion,
+	severity config.DiagnosticsSeverity,
+	opts undefined.Options,
+) *ASTAnalyzer {
+	return MakeAST(
+		"undefined",
+		parsing.New(phpv),
+		toSeverity(severity),
+		func(rooter *wrkspc.Rooter, content string) []protocol.Diagnostic {
+			violations := undefined.Diagnose(rooter, opts)
+			diagnostics := make([]protocol.Diagnostic, 0, len(violations))
+			for _, violation := range violations {
+				diagnostics = append(diagnostics, protocol.Diagnostic{
+					Range:   nodeRange(content, violation.Node),
+					Code:    violation.Code(),
+					Message: violation.Message(),
+				})
+			}
+
+			return diagnostics
+		},
+	)
+}
@@ -0,0 +1,30 @@
+package diagnostics
+
+import (
+	"github.com/laytan/go-lsp-protocol/pkg/lsp/protocol"
+	"github.com/laytan/phpls/internal/unused"
+	"github.com/laytan/phpls/internal/wrkspc"
+	"github.com/laytan/phpls/pkg/parsing"
+	"github.com/laytan/phpls/pkg/phpversion"
+)
+
+// MakeUnused creates the analyzer reporting unused variables, parameters,
+// imports and private members, as hints that editors render faded out.
+func MakeUnused(phpv *phpversion.PHPVersion) *ASTAnalyzer {
+	return MakeAST("unused", parsing.New(phpv), protocol.SeverityHint, diagnoseUnused)
+}
+
+func diagnoseUnused(rooter *wrkspc.Rooter, content string) []protocol.Diagnostic {
+	violations := unused.Diagnose(rooter, content)
+	diagnostics := make([]protocol.Diagnostic, 0, len(violations))
+	for _, violation := range violations {
+		diagnostics = append(diagnostics, protocol.Diagnostic{
+			Range:   nodeRange(content, violation.Node),
+			Code:    violation.Code(),
+			Message: violation.Message(),
+			Tags:    []protocol.DiagnosticTag{protocol.Unnecessary},
+		})
+	}
+
+	return diagnostics
+}
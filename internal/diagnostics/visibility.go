@@ -0,0 +1,30 @@
+package diagnostics
+
+import (
+	"github.com/laytan/go-lsp-protocol/pkg/lsp/protocol"
+	"github.com/laytan/phpls/internal/visibility"
+	"github.com/laytan/phpls/internal/wrkspc"
+	"github.com/laytan/phpls/pkg/parsing"
+	"github.com/laytan/phpls/pkg/phpversion"
+)
+
+// MakeVisibility creates the analyzer reporting accesses of private and
+// protected members from scopes that can't see them, and static members
+// accessed as instance members or the other way around.
+func MakeVisibility(phpv *phpversion.PHPVersion) *ASTAnalyzer {
+	return MakeAST("visibility", parsing.New(phpv), protocol.SeverityError, diagnoseVisibility)
+}
+
+func diagnoseVisibility(rooter *wrkspc.Rooter, content string) []protocol.Diagnostic {
+	violations := visibility.Diagnose(rooter)
+	diagnostics := make([]protocol.Diagnostic, 0, len(violations))
+	for _, violation := range violations {
+		diagnostics = append(diagnostics, protocol.Diagnostic{
+			Range:   nodeRange(content, violation.Node),
+			Code:    violation.Code(),
+			Message: violation.Message(),
+		})
+	}
+
+	return diagnostics
+}
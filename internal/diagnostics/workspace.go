@@ -0,0 +1,201 @@
+package diagnostics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"runtime"
+	"sync/atomic"
+	"time"
+
+	"github.com/laytan/go-lsp-protocol/pkg/lsp/protocol"
+	"github.com/laytan/phpls/internal/wrkspc"
+	"golang.org/x/sync/errgroup"
+)
+
+// The amount of files analyzed at the same time when analyzing the project.
+var workspaceGoRoutinesLimit = runtime.NumCPU()
+
+// AnalyzeWorkspace runs all analyzers over the project files and publishes the
+// results, also for files that are not open. Open files are skipped, they are
+// already analyzed on change and save.
+//
+// The progress is reported with the given token, or a new one if nil, and the
+// run stops when the progress is cancelled with CancelProgress.
+// Returns the amount of files analyzed.
+func (r *Runner) AnalyzeWorkspace(ctx context.Context, token protocol.ProgressToken) (int, error) {
+	var analyzed atomic.Int64
+
+	err := r.analyzeProject(
+		ctx,
+		token,
+		"analyzing workspace",
+		func(path string) bool { return !r.isOpen(path) },
+		func(path string, content string, diagnostics, saveDiagnostics []protocol.Diagnostic) {
+			analyzed.Add(1)
+
+			if !r.setWorkspaceDiagnostics(path, content, diagnostics, saveDiagnostics) {
+				return
+			}
+
+			if err := r.Publish(ctx, path); err != nil {
+				log.Printf("[ERROR]: publishing workspace diagnostics of %q: %v", path, err)
+			}
+		},
+	)
+
+	return int(analyzed.Load()), err
+}
+
+// CancelProgress cancels the project wide analysis that reports progress with
+// the token, returns false if there is no such analysis running.
+func (r *Runner) CancelProgress(token protocol.ProgressToken) bool {
+	return r.progress.Cancel(token)
+}
+
+// analyzeProject runs all analyzers over the project files that pass the
+// filter, in parallel, calling done with the results of each file.
+func (r *Runner) analyzeProject(
+	ctx context.Context,
+	token protocol.ProgressToken,
+	title string,
+	filter func(path string) bool,
+	done func(path string, content string, diagnostics, saveDiagnostics []protocol.Diagnostic),
+) error {
+	files, err := wrkspc.Current.ProjectFiles()
+	if err != nil {
+		return fmt.Errorf("collecting project files: %w", err)
+	}
+
+	filtered := files[:0]
+	for _, file := range files {
+		if filter(file) {
+			filtered = append(filtered, file)
+		}
+	}
+	files = filtered
+
+	finished := &atomic.Uint64{}
+	workCtx, stop, err := r.progress.TrackCancellable(
+		ctx,
+		token,
+		func() float64 { return float64(finished.Load()) },
+		func() float64 { return float64(len(files)) },
+		title,
+		time.Millisecond*100,
+	)
+	if err != nil {
+		return fmt.Errorf("tracking %s progress: %w", title, err)
+	}
+
+	g, gctx := errgroup.WithContext(workCtx)
+	g.SetLimit(workspaceGoRoutinesLimit)
+	for _, file := range files {
+		file := file
+		g.Go(func() error {
+			defer finished.Add(1)
+
+			if gctx.Err() != nil {
+				return gctx.Err()
+			}
+
+			content := wrkspc.Current.FContentOf(file)
+			diagnostics, saveDiagnostics, err := r.analyzeAll(gctx, file, content)
+			if gctx.Err() != nil {
+				return gctx.Err()
+			}
+			if err != nil {
+				log.Printf("[ERROR]: %s, analyzing %q: %v", title, file, err)
+			}
+
+			done(file, content, diagnostics, saveDiagnostics)
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		if errors.Is(err, context.Canceled) {
+			err = fmt.Errorf("%s cancelled: %w", title, err)
+		}
+
+		if stopErr := stop(err); stopErr != nil {
+			log.Printf("[ERROR]: stopping %s progress: %v", title, stopErr)
+		}
+
+		return err
+	}
+
+	if err := stop(nil); err != nil {
+		log.Printf("[ERROR]: stopping %s progress: %v", title, err)
+	}
+
+	return nil
+}
+
+// analyzeAll runs both the on change and on save analyzers for the path.
+func (r *Runner) analyzeAll(
+	ctx context.Context,
+	path string,
+	content string,
+) (diagnostics []protocol.Diagnostic, saveDiagnostics []protocol.Diagnostic, err error) {
+	var errs []error
+
+	for _, a := range r.analyzers {
+		res, err := a.Analyze(ctx, path, []byte(content))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", a.Name(), err))
+			continue
+		}
+
+		diagnostics = append(diagnostics, normalizeDiagnostics(a.Name(), res)...)
+	}
+
+	for _, a := range r.saveAnalyzers {
+		res, err := a.AnalyzeSave(ctx, path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", a.Name(), err))
+			continue
+		}
+
+		saveDiagnostics = append(saveDiagnostics, normalizeDiagnostics(a.Name(), res)...)
+	}
+
+	return diagnostics, saveDiagnostics, errors.Join(errs...)
+}
+
+// isOpen returns whether the file is open, and thus analyzed on change and save.
+func (r *Runner) isOpen(path string) bool {
+	r.diagnosticsMu.Lock()
+	defer r.diagnosticsMu.Unlock()
+
+	fd, ok := r.diagnostics[path]
+	return ok && !fd.workspace
+}
+
+// setWorkspaceDiagnostics stores the results of a workspace run, returns false
+// if the file has been opened in the meantime.
+func (r *Runner) setWorkspaceDiagnostics(
+	path string,
+	content string,
+	diagnostics []protocol.Diagnostic,
+	saveDiagnostics []protocol.Diagnostic,
+) bool {
+	r.diagnosticsMu.Lock()
+	defer r.diagnosticsMu.Unlock()
+
+	if fd, ok := r.diagnostics[path]; ok && !fd.workspace {
+		return false
+	}
+
+	r.diagnostics[path] = &fileDiagnostics{
+		path:            path,
+		diagnostics:     diagnostics,
+		saveDiagnostics: saveDiagnostics,
+		content:         content,
+		saveContent:     content,
+		workspace:       true,
+	}
+
+	return true
+}
@@ -22,6 +22,7 @@ import (
 // - Union -> recursively unpacked
 // - Intersection -> recursively unpacked
 // - Arrays -> their value type
+// - Generics -> qualified, see Qualify
 //
 // Precedence, union & intersection can result in multiple classes, so a slice is returned.
 // TODO: return fqn.FQN's instead of typeclasslikes.
@@ -41,10 +42,15 @@ func ApplyContext(
 			}}
 		}
 
-		return []*phpdoxer.TypeClassLike{{
+		cls := &phpdoxer.TypeClassLike{
 			Name:           fqnt.ResultFor(createName(currPos, typed.Name)).String(),
 			FullyQualified: true,
-		}}
+		}
+		for _, gen := range typed.GenericOver {
+			cls.GenericOver = append(cls.GenericOver, Qualify(fqnt, currFqn, currPos, gen))
+		}
+
+		return []*phpdoxer.TypeClassLike{cls}
 	case *phpdoxer.TypeConstant:
 		if typed.Class != nil {
 			return nil
@@ -74,6 +80,38 @@ func ApplyContext(
 	return nil
 }
 
+// Qualify returns a copy of the type with the class-likes it is composed of
+// fully qualified, "self", "static" and "$this" are replaced by the current class.
+func Qualify(
+	fqnt *fqn.Traverser,
+	currFqn *fqn.FQN,
+	currPos *position.Position,
+	doc phpdoxer.Type,
+) phpdoxer.Type {
+	return phpdoxer.Replace(doc, func(cls *phpdoxer.TypeClassLike) phpdoxer.Type {
+		if cls.FullyQualified {
+			return nil
+		}
+
+		qualified := currFqn
+		switch cls.Name {
+		case "self", "static", "$this":
+		default:
+			qualified = fqnt.ResultFor(createName(currPos, cls.Name))
+		}
+
+		if qualified == nil {
+			return nil
+		}
+
+		return &phpdoxer.TypeClassLike{
+			Name:           qualified.String(),
+			FullyQualified: true,
+			GenericOver:    cls.GenericOver,
+		}
+	})
+}
+
 func createName(pos *position.Position, name string) *ast.Name {
 	return &ast.Name{
 		Position: pos,
@@ -34,6 +34,8 @@ type DownResolvement struct {
 	ExprType   Type
 	Identifier string
 	Position   *position.Position
+	// The arguments of a call, used to infer the templates of the callee.
+	Arguments []ast.Vertex
 }
 
 type UpResolvement struct {
@@ -45,13 +47,17 @@ type UpResolvement struct {
 type Resolver interface {
 	Down(node ast.Vertex) (resolvement *DownResolvement, next ast.Vertex, done bool)
 }
+
+// ClassResolver resolves inside the context, the fully qualified class the
+// expression resolved to so far, with the types its templates are bound to.
 type ClassResolver interface {
 	Resolver
 	Up(
-		ctx *fqn.FQN,
+		scopes *Scopes,
+		ctx *phpdoxer.TypeClassLike,
 		privacy phprivacy.Privacy,
 		toResolve *DownResolvement,
-	) (result *Resolved, nextCtx *fqn.FQN, done bool)
+	) (result *Resolved, nextCtx *phpdoxer.TypeClassLike, done bool)
 }
 
 type StartResolver interface {
@@ -59,7 +65,7 @@ type StartResolver interface {
 	Up(
 		scopes *Scopes,
 		toResolve *DownResolvement,
-	) (result *Resolved, nextCtx *fqn.FQN, privacy phprivacy.Privacy, done bool)
+	) (result *Resolved, nextCtx *phpdoxer.TypeClassLike, privacy phprivacy.Privacy, done bool)
 }
 
 func AllResolvers() *map[Type]Resolver {
@@ -84,6 +90,18 @@ func Resolve(
 	node ast.Vertex,
 	scopes *Scopes,
 ) (result *Resolved, lastClass *fqn.FQN, left int) {
+	result, last, left := resolve(node, scopes)
+	if last != nil {
+		lastClass = fqn.New(last.Name)
+	}
+
+	return result, lastClass, left
+}
+
+func resolve(
+	node ast.Vertex,
+	scopes *Scopes,
+) (result *Resolved, lastClass *phpdoxer.TypeClassLike, left int) {
 	symbols := stack.New[*DownResolvement]()
 	Down(AllResolvers(), symbols, node)
 
@@ -92,7 +110,7 @@ func Resolve(
 	}
 
 	start := symbols.Pop()
-	var next *fqn.FQN
+	var next *phpdoxer.TypeClassLike
 	var privacy phprivacy.Privacy
 	for _, starter := range starters {
 		what.Happens("Up: %T", starter)
@@ -125,7 +143,7 @@ func Resolve(
 
 		resolver := resolvers[curr.ExprType]
 		what.Happens("Up: %T", resolver)
-		res, n, ok := resolver.Up(next, privacy, curr)
+		res, n, ok := resolver.Up(scopes, next, privacy, curr)
 		if !ok {
 			return res, n, symbols.Length() + 1
 		}
@@ -2,6 +2,7 @@ package expr
 
 import (
 	"github.com/laytan/php-parser/pkg/ast"
+	"github.com/laytan/phpls/pkg/fqn"
 	"github.com/laytan/phpls/pkg/phprivacy"
 )
 
@@ -37,3 +38,28 @@ func determinePrivacy(
 
 	return actPrivacy
 }
+
+// DeterminePrivacy returns the privacy members of the class can be accessed
+// with from the class scope, private from the class itself, protected from
+// classes inheriting it and public from anywhere else.
+func DeterminePrivacy(scopes *Scopes, qualified *fqn.FQN) (phprivacy.Privacy, error) {
+	return (&nameResolver{}).DeterminePrivacy(scopes, qualified)
+}
+
+// MemberPrivacy returns the privacy the members of a class, of the given kind,
+// can be accessed with when walking the inheritance chain of a class that is
+// accessed with startPrivacy.
+//
+// first is whether the class is the class accessed itself, firstClass is
+// whether no other class has been walked through yet.
+func MemberPrivacy(
+	startPrivacy phprivacy.Privacy,
+	kind ast.Type,
+	first bool,
+	firstClass bool,
+) phprivacy.Privacy {
+	return determinePrivacy(startPrivacy, kind, &iteration{
+		first:      first,
+		firstClass: firstClass,
+	})
+}
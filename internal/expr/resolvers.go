@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"strings"
 
 	"github.com/laytan/php-parser/pkg/ast"
 	"github.com/laytan/phpls/internal/index"
@@ -11,7 +12,9 @@ import (
 	"github.com/laytan/phpls/internal/wrkspc"
 	"github.com/laytan/phpls/pkg/fqn"
 	"github.com/laytan/phpls/pkg/nodeident"
+	"github.com/laytan/phpls/pkg/phpdoxer"
 	"github.com/laytan/phpls/pkg/phprivacy"
+	"github.com/laytan/phpls/pkg/set"
 )
 
 var resolvers = map[Type]ClassResolver{
@@ -39,15 +42,16 @@ func (p *propertyResolver) Down(
 }
 
 // Up finds the non-static property toResolve.Identifier inside the ctx class and
-// its inherited classes.
+// its inherited classes, falling back to @property tags and mixins, see findInherited.
 //
 // The first arg will contain the property node&path, the 2nd will be the
 // type of this property, which is nil if it is not a class.
 func (p *propertyResolver) Up(
-	ctx *fqn.FQN,
+	scopes *Scopes,
+	ctx *phpdoxer.TypeClassLike,
 	privacy phprivacy.Privacy,
 	toResolve *DownResolvement,
-) (*Resolved, *fqn.FQN, bool) {
+) (*Resolved, *phpdoxer.TypeClassLike, bool) {
 	if toResolve.ExprType != TypeProperty {
 		return nil, nil, false
 	}
@@ -57,51 +61,37 @@ func (p *propertyResolver) Up(
 		return nil, nil, false
 	}
 
-	prop := cls.FindProperty(
-		symbol.FilterName[*symbol.Property]("$"+toResolve.Identifier),
-		symbol.FilterNotStatic[*symbol.Property](),
-		symbol.FilterCanBeAccessedFrom[*symbol.Property](
-			determinePrivacy(privacy, cls.Kind(), &iteration{
-				first:      true,
-				firstClass: true,
-			}),
-		),
-	)
-	if prop != nil {
-		resolved, clsType := resolveProp(cls, prop)
-		return resolved, clsType, true
-	}
-
-	isFirstClass := true
-	iter := cls.InheritsIter()
-	for inhCls, done, err := iter(); !done; inhCls, done, err = iter() {
-		if err != nil {
-			log.Println(fmt.Errorf("[expr.propertyResolver.Up]: %w", err))
-			continue
-		}
-
-		prop := inhCls.FindProperty(
-			symbol.FilterName[*symbol.Property]("$"+toResolve.Identifier),
-			symbol.FilterNotStatic[*symbol.Property](),
-			symbol.FilterCanBeAccessedFrom[*symbol.Property](
-				determinePrivacy(privacy, inhCls.Kind(), &iteration{
-					first:      false,
-					firstClass: isFirstClass,
-				}),
-			),
-		)
-
-		if inhCls.Kind() == ast.TypeStmtClass {
-			isFirstClass = false
-		}
-
-		if prop != nil {
-			resolved, clsType := resolveProp(inhCls, prop)
+	name := "$" + toResolve.Identifier
+	return findInherited(
+		cls,
+		privacy,
+		set.New[string](),
+		func(cls *symbol.ClassLike, privacy phprivacy.Privacy) (*Resolved, *phpdoxer.TypeClassLike, bool) {
+			prop := cls.FindProperty(
+				symbol.FilterName[*symbol.Property](name),
+				symbol.FilterNotStatic[*symbol.Property](),
+				symbol.FilterCanBeAccessedFrom[*symbol.Property](privacy),
+			)
+			if prop == nil {
+				return nil, nil, false
+			}
+
+			resolved, clsType := resolveProp(cls, prop)
 			return resolved, clsType, true
-		}
-	}
-
-	return nil, nil, false
+		},
+		func(cls *symbol.ClassLike, privacy phprivacy.Privacy) (*Resolved, *phpdoxer.TypeClassLike, bool) {
+			prop := cls.FindVirtualProperty(
+				symbol.FilterName[*symbol.VirtualProperty](name),
+				symbol.FilterCanBeAccessedFrom[*symbol.VirtualProperty](privacy),
+			)
+			if prop == nil {
+				return nil, nil, false
+			}
+
+			resolved, clsType := resolveVirtualProp(cls, prop)
+			return resolved, clsType, true
+		},
+	)
 }
 
 type methodResolver struct{}
@@ -118,24 +108,26 @@ func (p *methodResolver) Down(
 		ExprType:   TypeMethod,
 		Identifier: nodeident.Get(propNode),
 		Position:   propNode.Position,
+		Arguments:  propNode.Args,
 	}, propNode.Var, true
 }
 
 // Up finds the non-static method toResolve.Identifier inside the ctx class and
-// its inherited classes.
+// its inherited classes, falling back to @method tags and mixins, see findInherited.
 //
 // The first arg will contain the method node&path, the 2nd will be the return
 // type of this method, which is nil if it is not a class.
 func (p *methodResolver) Up(
-	ctx *fqn.FQN,
+	scopes *Scopes,
+	ctx *phpdoxer.TypeClassLike,
 	privacy phprivacy.Privacy,
 	toResolve *DownResolvement,
-) (*Resolved, *fqn.FQN, bool) {
+) (*Resolved, *phpdoxer.TypeClassLike, bool) {
 	if toResolve.ExprType != TypeMethod {
 		return nil, nil, false
 	}
 
-	return methodUp(ctx, privacy, toResolve, symbol.FilterNotStatic[*symbol.Method]())
+	return methodUp(scopes, ctx, privacy, toResolve, false)
 }
 
 type staticMethodResolver struct{}
@@ -157,6 +149,7 @@ func (p *staticMethodResolver) Down(
 			ExprType:   TypeMethod,
 			Identifier: nodeident.Get(propNode),
 			Position:   propNode.Position,
+			Arguments:  propNode.Args,
 		}, propNode.Class, true
 	}
 
@@ -164,24 +157,21 @@ func (p *staticMethodResolver) Down(
 		ExprType:   TypeStaticMethod,
 		Identifier: nodeident.Get(propNode),
 		Position:   propNode.Position,
+		Arguments:  propNode.Args,
 	}, propNode.Class, true
 }
 
 func (p *staticMethodResolver) Up(
-	ctx *fqn.FQN,
+	scopes *Scopes,
+	ctx *phpdoxer.TypeClassLike,
 	privacy phprivacy.Privacy,
 	toResolve *DownResolvement,
-) (*Resolved, *fqn.FQN, bool) {
+) (*Resolved, *phpdoxer.TypeClassLike, bool) {
 	if toResolve.ExprType != TypeStaticMethod {
 		return nil, nil, false
 	}
 
-	return methodUp(
-		ctx,
-		privacy,
-		toResolve,
-		symbol.FilterStatic[*symbol.Method](),
-	)
+	return methodUp(scopes, ctx, privacy, toResolve, true)
 }
 
 type classConstResolver struct{}
@@ -212,10 +202,11 @@ func (c *classConstResolver) Down(
 }
 
 func (c *classConstResolver) Up(
-	ctx *fqn.FQN,
+	scopes *Scopes,
+	ctx *phpdoxer.TypeClassLike,
 	privacy phprivacy.Privacy,
 	toResolve *DownResolvement,
-) (result *Resolved, nextCtx *fqn.FQN, done bool) {
+) (result *Resolved, nextCtx *phpdoxer.TypeClassLike, done bool) {
 	if toResolve.ExprType != TypeClassConstant {
 		return nil, nil, false
 	}
@@ -271,29 +262,88 @@ func (c *classConstResolver) Up(
 }
 
 func methodUp(
-	ctx *fqn.FQN,
+	scopes *Scopes,
+	ctx *phpdoxer.TypeClassLike,
 	privacy phprivacy.Privacy,
 	toResolve *DownResolvement,
-	extraFilter symbol.FilterFunc[*symbol.Method],
-) (*Resolved, *fqn.FQN, bool) {
+	static bool,
+) (*Resolved, *phpdoxer.TypeClassLike, bool) {
 	cls := expandCtx(ctx)
 	if cls == nil {
 		return nil, nil, false
 	}
 
-	m := cls.FindMethod(
-		symbol.FilterName[*symbol.Method](toResolve.Identifier),
-		symbol.FilterCanBeAccessedFrom[*symbol.Method](
-			determinePrivacy(privacy, cls.Kind(), &iteration{
-				first:      true,
-				firstClass: true,
-			}),
-		),
-		extraFilter,
+	staticFilter := symbol.FilterNotStatic[*symbol.Method]()
+	virtualStaticFilter := symbol.FilterNotStatic[*symbol.VirtualMethod]()
+	if static {
+		staticFilter = symbol.FilterStatic[*symbol.Method]()
+		virtualStaticFilter = symbol.FilterStatic[*symbol.VirtualMethod]()
+	}
+
+	return findInherited(
+		cls,
+		privacy,
+		set.New[string](),
+		func(cls *symbol.ClassLike, privacy phprivacy.Privacy) (*Resolved, *phpdoxer.TypeClassLike, bool) {
+			m := cls.FindMethod(
+				symbol.FilterName[*symbol.Method](toResolve.Identifier),
+				symbol.FilterCanBeAccessedFrom[*symbol.Method](privacy),
+				staticFilter,
+			)
+			if m == nil {
+				return nil, nil, false
+			}
+
+			resolved, clsType := resolveMethod(scopes, cls, m, toResolve)
+			return resolved, clsType, true
+		},
+		func(cls *symbol.ClassLike, privacy phprivacy.Privacy) (*Resolved, *phpdoxer.TypeClassLike, bool) {
+			m := cls.FindVirtualMethod(
+				symbol.FilterName[*symbol.VirtualMethod](toResolve.Identifier),
+				symbol.FilterCanBeAccessedFrom[*symbol.VirtualMethod](privacy),
+				virtualStaticFilter,
+			)
+			if m == nil {
+				return nil, nil, false
+			}
+
+			resolved, clsType := resolveVirtualMethod(cls, m)
+			return resolved, clsType, true
+		},
 	)
+}
 
-	if m != nil {
-		resolved, clsType := resolveMethod(cls, m)
+// memberFinder finds a member in the class, that can be accessed with the
+// given privacy.
+type memberFinder func(
+	cls *symbol.ClassLike,
+	privacy phprivacy.Privacy,
+) (*Resolved, *phpdoxer.TypeClassLike, bool)
+
+// findInherited finds a member in the class and the classes it inherits from,
+// in the precedence PHPStan uses. Each finder is tried on all the classes before
+// the next finder is tried, so native members take precedence over virtual
+// members declared in PHPDoc.
+//
+// The mixins of the classes are searched last, visited contains the classes
+// that have already been searched, mixins can delegate to each other.
+func findInherited(
+	cls *symbol.ClassLike,
+	privacy phprivacy.Privacy,
+	visited *set.Set[string],
+	finders ...memberFinder,
+) (*Resolved, *phpdoxer.TypeClassLike, bool) {
+	visited.Add(cls.GetFQN().String())
+
+	classes := []*symbol.ClassLike{cls}
+	privacies := []phprivacy.Privacy{
+		determinePrivacy(privacy, cls.Kind(), &iteration{
+			first:      true,
+			firstClass: true,
+		}),
+	}
+
+	if resolved, clsType, ok := finders[0](cls, privacies[0]); ok {
 		return resolved, clsType, true
 	}
 
@@ -301,7 +351,7 @@ func methodUp(
 	iter := cls.InheritsIter()
 	for inhCls, done, err := iter(); !done; inhCls, done, err = iter() {
 		if err != nil {
-			log.Println(fmt.Errorf("[expr.methodResolver.Up]: %w", err))
+			log.Println(fmt.Errorf("[expr.findInherited]: %w", err))
 			continue
 		}
 
@@ -309,30 +359,47 @@ func methodUp(
 			isFirstClass = false
 		}
 
-		m := inhCls.FindMethod(
-			symbol.FilterName[*symbol.Method](toResolve.Identifier),
-			symbol.FilterCanBeAccessedFrom[*symbol.Method](
-				determinePrivacy(privacy, inhCls.Kind(), &iteration{
-					first:      false,
-					firstClass: isFirstClass,
-				}),
-			),
-			extraFilter,
-		)
+		classes = append(classes, inhCls)
+		privacies = append(privacies, determinePrivacy(privacy, inhCls.Kind(), &iteration{
+			first:      false,
+			firstClass: isFirstClass,
+		}))
 
-		if m != nil {
-			resolved, clsType := resolveMethod(inhCls, m)
+		if resolved, clsType, ok := finders[0](inhCls, privacies[len(privacies)-1]); ok {
 			return resolved, clsType, true
 		}
 	}
 
+	for _, finder := range finders[1:] {
+		for i, c := range classes {
+			if resolved, clsType, ok := finder(c, privacies[i]); ok {
+				return resolved, clsType, true
+			}
+		}
+	}
+
+	// Mixin members are delegated to from outside the mixin, using __call or
+	// __get, so only the public members can be accessed.
+	for _, c := range classes {
+		for _, mixin := range c.Mixins() {
+			if visited.Has(mixin.GetFQN().String()) {
+				continue
+			}
+
+			resolved, clsType, ok := findInherited(mixin, phprivacy.PrivacyPublic, visited, finders...)
+			if ok {
+				return resolved, clsType, true
+			}
+		}
+	}
+
 	return nil, nil, false
 }
 
 func resolveProp(
 	cls *symbol.ClassLike,
 	prop *symbol.Property,
-) (*Resolved, *fqn.FQN) {
+) (*Resolved, *phpdoxer.TypeClassLike) {
 	resolvement := &Resolved{
 		Node: prop.Node(),
 		Path: cls.Path(),
@@ -351,25 +418,77 @@ func resolveProp(
 		return resolvement, nil
 	}
 
-	qualified := fqn.New(typ[0].Name)
-	return resolvement, qualified
+	return resolvement, typ[0]
 }
 
 func resolveMethod(
+	scopes *Scopes,
 	cls *symbol.ClassLike,
 	m *symbol.Method,
-) (*Resolved, *fqn.FQN) {
+	toResolve *DownResolvement,
+) (*Resolved, *phpdoxer.TypeClassLike) {
 	resolvement := &Resolved{
 		Node: m.Node(),
 		Path: cls.Path(),
 	}
 
-	res, err := m.ReturnsClass()
+	// Only resolve the arguments when the method has templates to infer.
+	var args []phpdoxer.Type
+	if m.FindDoc(symbol.FilterDocKind(phpdoxer.KindTemplate)) != nil {
+		args = argumentTypes(scopes, toResolve.Arguments)
+	}
+
+	res, err := m.ReturnsClassBound(cls, args)
 	if err != nil && !errors.Is(err, symbol.ErrNoReturn) {
 		log.Println(fmt.Errorf("resolving method %s return: %w", m.Name(), err))
 	}
 	if len(res) > 0 {
-		return resolvement, fqn.New(res[0].Name)
+		return resolvement, res[0]
+	}
+
+	return resolvement, nil
+}
+
+func resolveVirtualProp(
+	cls *symbol.ClassLike,
+	prop *symbol.VirtualProperty,
+) (*Resolved, *phpdoxer.TypeClassLike) {
+	resolvement := &Resolved{
+		Node: prop.Vertex(),
+		Path: cls.Path(),
+	}
+
+	typ, err := prop.ClsType()
+	if err != nil {
+		if !errors.Is(err, symbol.ErrNoPropertyType) {
+			log.Println(fmt.Errorf("resolving virtual prop type: %w", err))
+		}
+
+		return resolvement, nil
+	}
+
+	if len(typ) == 0 {
+		return resolvement, nil
+	}
+
+	return resolvement, typ[0]
+}
+
+func resolveVirtualMethod(
+	cls *symbol.ClassLike,
+	m *symbol.VirtualMethod,
+) (*Resolved, *phpdoxer.TypeClassLike) {
+	resolvement := &Resolved{
+		Node: m.Vertex(),
+		Path: cls.Path(),
+	}
+
+	res, err := m.ReturnsClassBound(cls)
+	if err != nil && !errors.Is(err, symbol.ErrNoReturn) {
+		log.Println(fmt.Errorf("resolving virtual method %s return: %w", m.Name(), err))
+	}
+	if len(res) > 0 {
+		return resolvement, res[0]
 	}
 
 	return resolvement, nil
@@ -378,7 +497,7 @@ func resolveMethod(
 func resolveConst(
 	cls *symbol.ClassLike,
 	cnst *symbol.ClassConst,
-) (*Resolved, *fqn.FQN) {
+) (*Resolved, *phpdoxer.TypeClassLike) {
 	resolvement := &Resolved{
 		Node: cnst.Node(),
 		Path: cls.Path(),
@@ -387,19 +506,70 @@ func resolveConst(
 	return resolvement, nil
 }
 
-func expandCtx(ctx *fqn.FQN) *symbol.ClassLike {
-	iNode, ok := index.Current.Find(ctx)
+// expandCtx returns the class of the context, with its templates bound.
+func expandCtx(ctx *phpdoxer.TypeClassLike) *symbol.ClassLike {
+	qualified := fqn.New(ctx.Name)
+	iNode, ok := index.Current.Find(qualified)
 	if !ok {
 		log.Println(fmt.Errorf("[expr.expandCtx(%v)]: can't find in index", ctx))
 		return nil
 	}
 
 	rooter := wrkspc.NewRooter(iNode.Path)
-	cls, err := symbol.NewClassLikeFromFQN(rooter, ctx)
+	cls, err := symbol.NewClassLikeFromFQN(rooter, qualified)
 	if err != nil {
 		log.Println(fmt.Errorf("[expr.expandCtx(%v)]: %w", ctx, err))
 		return nil
 	}
 
+	if len(ctx.GenericOver) > 0 {
+		return cls.Bind(ctx.GenericOver)
+	}
+
 	return cls
 }
+
+// argumentTypes resolves the types of the positional arguments, an argument
+// type is nil if it can't be resolved.
+func argumentTypes(scopes *Scopes, args []ast.Vertex) []phpdoxer.Type {
+	types := make([]phpdoxer.Type, 0, len(args))
+	for _, arg := range args {
+		typedArg, ok := arg.(*ast.Argument)
+		if !ok || typedArg.Name != nil || typedArg.VariadicTkn != nil {
+			break
+		}
+
+		types = append(types, argumentType(scopes, typedArg.Expr))
+	}
+
+	return types
+}
+
+func argumentType(scopes *Scopes, arg ast.Vertex) phpdoxer.Type {
+	// Foo::class is a class-string of Foo.
+	if fetch, ok := arg.(*ast.ExprClassConstFetch); ok {
+		if !strings.EqualFold(nodeident.Get(fetch.Const), "class") {
+			return nil
+		}
+
+		class := fetch.Class
+		if ident := nodeident.Get(class); ident == "self" || ident == "static" {
+			class = &ast.ExprVariable{
+				Position: class.GetPosition(),
+				Name:     &ast.Identifier{Value: []byte(ident)},
+			}
+		}
+
+		if _, cls, left := resolve(class, scopes); left == 0 && cls != nil {
+			return &phpdoxer.TypeString{Constraint: phpdoxer.StringConstraintClass, GenericOver: cls}
+		}
+
+		return nil
+	}
+
+	if _, cls, left := resolve(arg, scopes); left == 0 && cls != nil {
+		return cls
+	}
+
+	return nil
+}
@@ -50,7 +50,7 @@ func (p *variableResolver) Down(
 func (p *variableResolver) Up(
 	scopes *Scopes,
 	toResolve *DownResolvement,
-) (*Resolved, *fqn.FQN, phprivacy.Privacy, bool) {
+) (*Resolved, *phpdoxer.TypeClassLike, phprivacy.Privacy, bool) {
 	wrk := wrkspc.Current
 	switch toResolve.Identifier {
 	case "$this", "self", "static":
@@ -59,7 +59,7 @@ func (p *variableResolver) Up(
 			Parts:    nameParts(nodeident.Get(scopes.Class)),
 		}); ok {
 			return &Resolved{Path: node.Path, Node: node.ToIRNode(wrk.FIROf(node.Path))},
-				node.FQN,
+				classOf(node.FQN),
 				phprivacy.PrivacyPrivate,
 				true
 		}
@@ -70,7 +70,7 @@ func (p *variableResolver) Up(
 	case "parent":
 		node := parentOf(scopes)
 		return &Resolved{Path: node.Path, Node: node.ToIRNode(wrk.FIROf(node.Path))},
-			node.FQN,
+			classOf(node.FQN),
 			phprivacy.PrivacyProtected,
 			true
 
@@ -112,15 +112,13 @@ func (p *variableResolver) Up(
 
 		if len(typ) > 0 {
 			return &Resolved{
-					Path: scopes.Path,
-					Node: ta.Assignment,
-				}, fqn.New(
-					typ[0].Name,
-				), phprivacy.PrivacyPublic, true
+				Path: scopes.Path,
+				Node: ta.Assignment,
+			}, typ[0], phprivacy.PrivacyPublic, true
 		}
 
 		if nodevar.IsAssignment(ta.Scope.GetType()) {
-			if res, lastClass, left := Resolve(nodevar.AssignmentExpr(ta.Scope), scopes); left == 0 {
+			if res, lastClass, left := resolve(nodevar.AssignmentExpr(ta.Scope), scopes); left == 0 {
 				return res, lastClass, phprivacy.PrivacyPublic, true
 			}
 
@@ -148,7 +146,7 @@ func (p *variableResolver) Up(
 			return &Resolved{
 				Node: ta.Assignment,
 				Path: scopes.Path,
-			}, fqn.New(typ[0].Name), phprivacy.PrivacyPublic, true
+			}, typ[0], phprivacy.PrivacyPublic, true
 
 		default:
 			log.Printf("TODO: resolve variable out of type %T", ta.Scope)
@@ -178,7 +176,7 @@ func (p *nameResolver) Down(
 func (p *nameResolver) Up(
 	scopes *Scopes,
 	toResolve *DownResolvement,
-) (*Resolved, *fqn.FQN, phprivacy.Privacy, bool) {
+) (*Resolved, *phpdoxer.TypeClassLike, phprivacy.Privacy, bool) {
 	if toResolve.ExprType != TypeName {
 		return nil, nil, 0, false
 	}
@@ -201,7 +199,7 @@ func (p *nameResolver) Up(
 	}
 
 	return &Resolved{Path: res.Path, Node: res.ToIRNode(wrkspc.Current.FIROf(res.Path))},
-		qualified,
+		classOf(qualified),
 		privacy,
 		true
 }
@@ -255,13 +253,14 @@ func (p *functionResolver) Down(
 		ExprType:   TypeFunction,
 		Identifier: nodeident.Get(propNode),
 		Position:   propNode.Position,
+		Arguments:  propNode.Args,
 	}, nil, true
 }
 
 func (p *functionResolver) Up(
 	scopes *Scopes,
 	toResolve *DownResolvement,
-) (*Resolved, *fqn.FQN, phprivacy.Privacy, bool) {
+) (*Resolved, *phpdoxer.TypeClassLike, phprivacy.Privacy, bool) {
 	if toResolve.ExprType != TypeFunction {
 		return nil, nil, 0, false
 	}
@@ -274,21 +273,22 @@ func (p *functionResolver) Up(
 		return nil, nil, 0, false
 	}
 
-	typeOfFunc := func(n ast.Vertex) *fqn.FQN {
-		ret, _, err := symbol.NewFunction(
-			wrkspc.NewRooter(scopes.Path, scopes.Root),
-			n.(*ast.StmtFunction),
-		).Returns()
+	typeOfFunc := func(n ast.Vertex, rooter *wrkspc.Rooter) *phpdoxer.TypeClassLike {
+		function := symbol.NewFunction(rooter, n.(*ast.StmtFunction))
 
+		// Only resolve the arguments when the function has templates to infer.
+		var args []phpdoxer.Type
+		if function.FindDoc(symbol.FilterDocKind(phpdoxer.KindTemplate)) != nil {
+			args = argumentTypes(scopes, toResolve.Arguments)
+		}
+
+		ret, err := function.ReturnsClassBound(nil, args)
 		if err != nil && !errors.Is(err, symbol.ErrNoReturn) {
 			log.Println(fmt.Errorf("getting return type of %v: %w", n, err))
 		}
 
-		if retCls, ok := ret.(*phpdoxer.TypeClassLike); ok {
-			return fqner.FullyQualifyName(scopes.Root, &ast.Name{
-				Position: n.GetPosition(),
-				Parts:    nameParts(retCls.Name),
-			})
+		if len(ret) > 0 {
+			return ret[0]
 		}
 
 		return nil
@@ -304,7 +304,7 @@ func (p *functionResolver) Up(
 			return &Resolved{
 				Node: ft.Function,
 				Path: scopes.Path,
-			}, typeOfFunc(ft.Function), phprivacy.PrivacyPublic, true
+			}, typeOfFunc(ft.Function, wrkspc.NewRooter(scopes.Path, scopes.Root)), phprivacy.PrivacyPublic, true
 		}
 	}
 
@@ -317,7 +317,7 @@ func (p *functionResolver) Up(
 		return &Resolved{
 			Node: n,
 			Path: def.Path,
-		}, typeOfFunc(n), phprivacy.PrivacyPublic, true
+		}, typeOfFunc(n, wrkspc.NewRooter(def.Path)), phprivacy.PrivacyPublic, true
 	}
 
 	// Check for global functions.
@@ -332,7 +332,7 @@ func (p *functionResolver) Up(
 	return &Resolved{
 		Node: n,
 		Path: def.Path,
-	}, typeOfFunc(n), phprivacy.PrivacyPublic, true
+	}, typeOfFunc(n, wrkspc.NewRooter(def.Path)), phprivacy.PrivacyPublic, true
 }
 
 type newResolver struct{}
@@ -361,7 +361,7 @@ func (newresolver *newResolver) Down(
 func (newresolver *newResolver) Up(
 	scopes *Scopes,
 	toResolve *DownResolvement,
-) (resolved *Resolved, nextCtx *fqn.FQN, privacy phprivacy.Privacy, done bool) {
+) (resolved *Resolved, nextCtx *phpdoxer.TypeClassLike, privacy phprivacy.Privacy, done bool) {
 	if toResolve.ExprType != TypeNew {
 		return nil, nil, 0, false
 	}
@@ -380,7 +380,7 @@ func (newresolver *newResolver) Up(
 				Path: def.Path,
 				Node: def.ToIRNode(wrkspc.Current.FIROf(def.Path)),
 			},
-			qualified,
+			classOf(qualified),
 			phprivacy.PrivacyPublic,
 			true
 	}
@@ -422,6 +422,14 @@ func parentOf(scopes *Scopes) *index.INode {
 	return nil
 }
 
+func classOf(qualified *fqn.FQN) *phpdoxer.TypeClassLike {
+	if qualified == nil {
+		return nil
+	}
+
+	return &phpdoxer.TypeClassLike{Name: qualified.String(), FullyQualified: true}
+}
+
 func nameParts(name string) []ast.Vertex {
 	return functional.Map(
 		strings.Split(name, "\\"),
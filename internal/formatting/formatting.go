@@ -0,0 +1,98 @@
+// Package formatting provides the formatters that can be selected in the
+// configuration.
+package formatting
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os/exec"
+
+	"github.com/laytan/go-lsp-protocol/pkg/lsp/protocol"
+	"github.com/laytan/phpls/internal/config"
+	"github.com/laytan/phpls/internal/wrkspc"
+	"github.com/laytan/phpls/pkg/diff"
+	"github.com/laytan/phpls/pkg/phpcs/phpcbf"
+	"github.com/laytan/phpls/pkg/phpcsfixer"
+)
+
+type Formatter interface {
+	Name() string
+	// Format returns the formatted code, the path is used to resolve the
+	// configuration of the formatter.
+	Format(ctx context.Context, path string, code []byte) ([]byte, error)
+}
+
+// NewFromConfig returns the configured formatter, nil if it is not available.
+//
+// The phpcbf instance is reused when phpcbf is configured, it is also used
+// for fixing individual sniffs.
+func NewFromConfig(cbf *phpcbf.Instance) Formatter {
+	switch config.Current.Formatter {
+	case config.FormatterPhpCsFixer:
+		for _, try := range config.Current.PhpCsFixer.Binary {
+			if path, err := exec.LookPath(try); err == nil {
+				log.Printf("[INFO]: formatting with php-cs-fixer using binary at %q", path)
+				return &PhpCsFixer{Executable: path}
+			}
+		}
+
+		log.Printf(
+			"[ERROR]: php-cs-fixer is the configured formatter but no executable found in the following configured places: %v",
+			config.Current.PhpCsFixer.Binary,
+		)
+		return nil
+
+	default:
+		if !cbf.HasExecutable() {
+			return nil
+		}
+
+		log.Println("[INFO]: formatting with phpcbf")
+		return &Phpcbf{Instance: cbf}
+	}
+}
+
+// Edits formats the file and returns the edits of the lines that changed.
+func Edits(ctx context.Context, f Formatter, path string) ([]protocol.TextEdit, error) {
+	code := wrkspc.Current.FContentOf(path)
+	formatted, err := f.Format(ctx, path, []byte(code))
+	if err != nil {
+		return nil, fmt.Errorf("formatting %q code with %s: %w", path, f.Name(), err)
+	}
+
+	return diff.New(code, string(formatted)).Edits(), nil
+}
+
+type Phpcbf struct {
+	Instance *phpcbf.Instance
+}
+
+var _ Formatter = &Phpcbf{}
+
+func (p *Phpcbf) Name() string {
+	return "phpcbf"
+}
+
+func (p *Phpcbf) Format(_ context.Context, _ string, code []byte) ([]byte, error) {
+	return p.Instance.Format(code)
+}
+
+type PhpCsFixer struct {
+	Executable string
+}
+
+var _ Formatter = &PhpCsFixer{}
+
+func (p *PhpCsFixer) Name() string {
+	return "php-cs-fixer"
+}
+
+func (p *PhpCsFixer) Format(ctx context.Context, path string, code []byte) ([]byte, error) {
+	res, err := phpcsfixer.Fix(ctx, p.Executable, path, code)
+	if err != nil {
+		return nil, fmt.Errorf("fixing with php-cs-fixer: %w", err)
+	}
+
+	return res.Fixed, nil
+}
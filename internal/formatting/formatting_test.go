@@ -0,0 +1,31 @@
+package formatting_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/laytan/phpls/internal/formatting"
+	"github.com/laytan/phpls/pkg/pathutils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPhpCsFixer(t *testing.T) {
+	t.Parallel()
+
+	f := &formatting.PhpCsFixer{
+		Executable: filepath.Join(pathutils.Root(), "pkg", "phpcsfixer", "testdata", "php-cs-fixer.sh"),
+	}
+
+	formatted, err := f.Format(
+		context.Background(),
+		filepath.Join(t.TempDir(), "test.php"),
+		[]byte("<?php\n\n$a = 1;  \necho \"a\";\n"),
+	)
+	require.NoError(t, err)
+	require.Equal(t, "<?php\n\n$a = 1;\necho 'a';\n", string(formatted))
+
+	f.Executable = filepath.Join(pathutils.Root(), "pkg", "phpcsfixer", "testdata", "php-cs-fixer_error.sh")
+	_, err = f.Format(context.Background(), filepath.Join(t.TempDir(), "test.php"), []byte("<?php\n"))
+	require.ErrorContains(t, err, "fixing with php-cs-fixer")
+}
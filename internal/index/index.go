@@ -1,6 +1,7 @@
 package index
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"path/filepath"
@@ -41,6 +42,10 @@ type Index interface {
 	// Giving this no kinds or ir.KindRoot will return any kind.
 	Find(key *fqn.FQN) (*INode, bool)
 
+	// Finds all the symbols with the given FQN, there can be multiple when a
+	// symbol is declared in multiple files.
+	FindAll(key *fqn.FQN) []*INode
+
 	// Finds a prefix/completes a string.
 	// Do not call this with a namespaced symbol, only the class or function name.
 	//
@@ -88,6 +93,10 @@ func (i *index) Index(path string, content string) error {
 	}()
 
 	root, err := i.parser(path).Parse([]byte(content))
+	if errors.Is(err, parsing.ErrPanic) {
+		log.Printf("Could not parse %s into an AST: %v", path, err)
+		return nil
+	}
 	if err != nil {
 		return fmt.Errorf(errParseFmt, path, err)
 	}
@@ -133,6 +142,10 @@ func (i *index) Find(key *fqn.FQN) (*INode, bool) {
 	return nil, false
 }
 
+func (i *index) FindAll(key *fqn.FQN) []*INode {
+	return i.symbolTrie.FullSearch(key)
+}
+
 func (i *index) FindPrefix(prefix string, max int, kind ...ast.Type) []*INode {
 	results := i.symbolTrie.NameSearch(prefix, max)
 
@@ -193,7 +206,8 @@ func (i *index) Delete(path string) error {
 
 	j := 0
 	for node := range nodes {
-		i.symbolTrie.Delete(node.FQN, symboltrie.DelAll[*INode])
+		// The same symbol can be declared in other files, only delete this one.
+		i.symbolTrie.Delete(node.FQN, func(n *INode) bool { return n.Path == path })
 		j++
 	}
 
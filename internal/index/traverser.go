@@ -5,8 +5,10 @@ import (
 
 	"github.com/laytan/php-parser/pkg/ast"
 	"github.com/laytan/php-parser/pkg/visitor"
+	"github.com/laytan/php-parser/pkg/visitor/traverser"
 	"github.com/laytan/phpls/pkg/fqn"
 	"github.com/laytan/phpls/pkg/nodeident"
+	"github.com/laytan/phpls/pkg/set"
 )
 
 type INodeTraverser struct {
@@ -20,6 +22,25 @@ func NewIndexTraverser() *INodeTraverser {
 	return &INodeTraverser{}
 }
 
+// Declarations returns the FQNs of the symbols declared in the given root,
+// these might not be in the index yet when the file is being edited.
+func Declarations(path string, root ast.Vertex) *set.Set[string] {
+	declared := set.New[string]()
+
+	nodes := make(chan *INode)
+	go func() {
+		t := NewIndexTraverser()
+		t.Reset(path, nodes)
+		root.Accept(traverser.NewTraverser(t))
+	}()
+
+	for node := range nodes {
+		declared.Add(node.FQN.String())
+	}
+
+	return declared
+}
+
 func (t *INodeTraverser) EnterNode(node ast.Vertex) bool {
 	switch typedNode := node.(type) {
 	case *ast.StmtNamespace:
@@ -30,12 +51,20 @@ func (t *INodeTraverser) EnterNode(node ast.Vertex) bool {
 
 		return true
 
-	case *ast.StmtFunction, *ast.StmtClass, *ast.StmtInterface, *ast.StmtTrait:
+	case *ast.StmtFunction, *ast.StmtClass, *ast.StmtInterface, *ast.StmtTrait, *ast.StmtEnum:
 		fqn := fqn.New(t.currentNamespace + nodeident.Get(node))
 		t.nodes <- NewINode(fqn, t.currentPath, node)
 
 		return false
 
+	case *ast.StmtConstList:
+		for _, constant := range typedNode.Consts {
+			fqn := fqn.New(t.currentNamespace + nodeident.Get(constant))
+			t.nodes <- NewINode(fqn, t.currentPath, constant)
+		}
+
+		return false
+
 	case *ast.ExprFunctionCall:
 		// Index a function call to define() as a constant.
 		if nodeident.Get(typedNode) == "define" {
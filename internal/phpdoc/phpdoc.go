@@ -0,0 +1,396 @@
+package phpdoc
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/laytan/php-parser/pkg/ast"
+	"github.com/laytan/php-parser/pkg/position"
+	"github.com/laytan/php-parser/pkg/token"
+	"github.com/laytan/php-parser/pkg/visitor"
+	"github.com/laytan/php-parser/pkg/visitor/traverser"
+	"github.com/laytan/phpls/internal/index"
+	"github.com/laytan/phpls/internal/symbol"
+	"github.com/laytan/phpls/pkg/fqn"
+	"github.com/laytan/phpls/pkg/nodeident"
+	"github.com/laytan/phpls/pkg/nodescopes"
+	"github.com/laytan/phpls/pkg/phpdoxer"
+	"github.com/laytan/phpls/pkg/set"
+)
+
+type rooter interface {
+	Root() *ast.Root
+	Path() string
+}
+
+type Kind int
+
+const (
+	KindUnparsable Kind = iota
+	KindInvalidVersion
+	KindUnknownParam
+	KindMissingParam
+	KindReturnOnConstructor
+	KindReturnOnVoid
+	KindUnknownClass
+)
+
+// Class-like names in doc types that don't refer to a class.
+var keywords = set.NewFromSlice([]string{"self", "static", "$this", "parent", "list", "numeric"})
+
+type Violation struct {
+	// The position of the tag, or of the doc comment for missing tags.
+	Position *position.Position
+	Kind     Kind
+	// The tag, like "param".
+	Tag string
+	// The unparsable type or version, the parameter, or the unknown class.
+	Name string
+	// The documented function, like "function foo()" or "method bar()".
+	Function string
+}
+
+func (v *Violation) Message() string {
+	switch v.Kind {
+	case KindUnparsable:
+		return fmt.Sprintf("Unable to parse type %q of @%s.", v.Name, v.Tag)
+	case KindInvalidVersion:
+		return fmt.Sprintf("Invalid version %q in @%s.", v.Name, v.Tag)
+	case KindUnknownParam:
+		return fmt.Sprintf("@param %s does not match any parameter of %s.", v.Name, v.Function)
+	case KindMissingParam:
+		return fmt.Sprintf("Missing @param for parameter %s of %s.", v.Name, v.Function)
+	case KindReturnOnConstructor:
+		return "Unexpected @return on a constructor."
+	case KindReturnOnVoid:
+		return fmt.Sprintf("Unexpected @return on %s, it returns void.", v.Function)
+	default:
+		return fmt.Sprintf("Unknown class %s in @%s.", v.Name, v.Tag)
+	}
+}
+
+func (v *Violation) Code() string {
+	switch v.Kind {
+	case KindUnparsable:
+		return "invalid-doc-type"
+	case KindInvalidVersion:
+		return "invalid-doc-version"
+	case KindUnknownParam:
+		return "unknown-doc-param"
+	case KindMissingParam:
+		return "missing-doc-param"
+	case KindReturnOnConstructor, KindReturnOnVoid:
+		return "invalid-doc-return"
+	default:
+		return "unknown-doc-class"
+	}
+}
+
+func (v *Violation) Line() int {
+	return v.Position.StartLine
+}
+
+// Diagnose validates the PHPDoc of functions, methods and properties.
+//
+// It reports types that can't be parsed, @param tags that don't match a
+// parameter, parameters missing a @param when other parameters have one,
+// @return tags on constructors and void functions, and classes in types that
+// can't be found.
+func Diagnose(root rooter) []*Violation {
+	fqnt := fqn.NewTraverser()
+	root.Root().Accept(traverser.NewTraverser(fqnt))
+
+	t := &phpdocTraverser{
+		fqnt:    fqnt,
+		classes: []ast.Vertex{root.Root()},
+	}
+	root.Root().Accept(traverser.NewTraverser(t))
+
+	sort.SliceStable(t.violations, func(i, j int) bool {
+		return t.violations[i].Position.StartPos < t.violations[j].Position.StartPos
+	})
+
+	return t.violations
+}
+
+type phpdocTraverser struct {
+	visitor.Null
+
+	fqnt    *fqn.Traverser
+	classes []ast.Vertex
+
+	violations []*Violation
+}
+
+func (t *phpdocTraverser) EnterNode(node ast.Vertex) bool {
+	if nodescopes.IsClassLike(node.GetType()) {
+		t.classes = append(t.classes, node)
+	}
+
+	switch typedNode := node.(type) {
+	case *ast.StmtFunction:
+		t.function(
+			node,
+			fmt.Sprintf("function %s()", nodeident.Get(typedNode.Name)),
+			typedNode.Params,
+			typedNode.ReturnType,
+		)
+
+	case *ast.StmtClassMethod:
+		t.function(
+			node,
+			fmt.Sprintf("method %s()", nodeident.Get(typedNode.Name)),
+			typedNode.Params,
+			typedNode.ReturnType,
+		)
+
+	case *ast.StmtPropertyList:
+		for _, doc := range t.docs(node) {
+			t.types(node, doc)
+		}
+	}
+
+	return true
+}
+
+func (t *phpdocTraverser) LeaveNode(node ast.Vertex) {
+	if nodescopes.IsClassLike(node.GetType()) {
+		t.classes = t.classes[:len(t.classes)-1]
+	}
+}
+
+// doc is a parsed doc comment and the token it was parsed from.
+type doc struct {
+	token *token.Token
+	nodes []phpdoxer.Node
+}
+
+// position returns the position of the doc node in the file.
+func (d *doc) position(node phpdoxer.Node) *position.Position {
+	start, end := node.Range()
+
+	// The range of a tag includes the whitespace up to the next tag, or the
+	// end of the comment.
+	tag := strings.TrimSuffix(string(d.token.Value[start:end]), "*/")
+	return d.offsets(start, start+len(strings.TrimRight(tag, " \t\r\n*")))
+}
+
+func (d *doc) offsets(start int, end int) *position.Position {
+	value := string(d.token.Value)
+	startLine := d.token.Position.StartLine + strings.Count(value[:start], "\n")
+	return &position.Position{
+		StartLine: startLine,
+		EndLine:   startLine + strings.Count(value[start:end], "\n"),
+		StartPos:  d.token.Position.StartPos + start,
+		EndPos:    d.token.Position.StartPos + end,
+	}
+}
+
+func (t *phpdocTraverser) docs(node ast.Vertex) []*doc {
+	var docs []*doc
+	for _, tok := range symbol.NodeCommentTokens(node) {
+		if !strings.HasPrefix(string(tok.Value), "/**") {
+			continue
+		}
+
+		nodes, err := phpdoxer.ParseDoc(string(tok.Value))
+		if err != nil {
+			continue
+		}
+
+		docs = append(docs, &doc{token: tok, nodes: nodes})
+	}
+
+	return docs
+}
+
+func (t *phpdocTraverser) function(
+	node ast.Vertex,
+	name string,
+	paramNodes []ast.Vertex,
+	returnType ast.Vertex,
+) {
+	params := make([]string, 0, len(paramNodes))
+	for _, param := range paramNodes {
+		params = append(params, nodeident.Get(param.(*ast.Parameter).Var))
+	}
+
+	isConstructor := strings.EqualFold(nodeident.Get(node), "__construct")
+	isVoid := returnType != nil && strings.EqualFold(nodeident.Get(returnType), "void")
+
+	for _, d := range t.docs(node) {
+		t.types(node, d)
+
+		documented := set.New[string]()
+		for _, n := range d.nodes {
+			switch typedNode := n.(type) {
+			case *phpdoxer.NodeParam:
+				// Without a name, the type could not be split from the rest.
+				if typedNode.Name == "" {
+					t.violations = append(t.violations, &Violation{
+						Position: d.position(n),
+						Kind:     KindUnparsable,
+						Tag:      "param",
+						Name:     typedNode.Description,
+					})
+					continue
+				}
+
+				paramName := strings.TrimLeft(typedNode.Name, "&.")
+				documented.Add(paramName)
+				if !contains(params, paramName) {
+					t.violations = append(t.violations, &Violation{
+						Position: d.position(n),
+						Kind:     KindUnknownParam,
+						Tag:      "param",
+						Name:     typedNode.Name,
+						Function: name,
+					})
+				}
+
+			case *phpdoxer.NodeReturn:
+				switch {
+				case isConstructor:
+					t.violations = append(t.violations, &Violation{
+						Position: d.position(n),
+						Kind:     KindReturnOnConstructor,
+						Tag:      "return",
+						Function: name,
+					})
+				case isVoid && typedNode.Type.Kind() != phpdoxer.KindVoid:
+					t.violations = append(t.violations, &Violation{
+						Position: d.position(n),
+						Kind:     KindReturnOnVoid,
+						Tag:      "return",
+						Function: name,
+					})
+				}
+			}
+		}
+
+		// Only docs that document parameters are expected to document all of them.
+		if documented.Size() == 0 {
+			continue
+		}
+
+		for _, param := range params {
+			if documented.Has(param) {
+				continue
+			}
+
+			t.violations = append(t.violations, &Violation{
+				Position: d.offsets(0, len(d.token.Value)),
+				Kind:     KindMissingParam,
+				Tag:      "param",
+				Name:     param,
+				Function: name,
+			})
+		}
+	}
+}
+
+// types checks the types of the tags in the doc, node is the documented node.
+func (t *phpdocTraverser) types(node ast.Vertex, d *doc) {
+	templates := t.templates(node)
+
+	for _, n := range d.nodes {
+		var tag string
+		var typ phpdoxer.Type
+		switch typedNode := n.(type) {
+		case *phpdoxer.NodeParam:
+			tag, typ = "param", typedNode.Type
+		case *phpdoxer.NodeReturn:
+			tag, typ = "return", typedNode.Type
+		case *phpdoxer.NodeVar:
+			tag, typ = "var", typedNode.Type
+		case *phpdoxer.NodeThrows:
+			tag, typ = "throws", typedNode.Type
+		case *phpdoxer.NodeUnknown:
+			// Since and removed fall back to an unknown node with an invalid version.
+			if typedNode.At == "since" || typedNode.At == "removed" {
+				version, _, _ := strings.Cut(typedNode.Value, " ")
+				t.violations = append(t.violations, &Violation{
+					Position: d.position(n),
+					Kind:     KindInvalidVersion,
+					Tag:      typedNode.At,
+					Name:     version,
+				})
+			}
+			continue
+		default:
+			continue
+		}
+
+		if typ == nil {
+			continue
+		}
+
+		if unknown, ok := typ.(*phpdoxer.TypeUnknown); ok {
+			t.violations = append(t.violations, &Violation{
+				Position: d.position(n),
+				Kind:     KindUnparsable,
+				Tag:      tag,
+				Name:     unknown.Value,
+			})
+			continue
+		}
+
+		for _, cls := range phpdoxer.ClassLikes(typ) {
+			if keywords.Has(strings.ToLower(cls.Name)) || templates.Has(cls.Name) {
+				continue
+			}
+
+			qualified := fqn.New(cls.Name)
+			if !cls.FullyQualified {
+				qualified = t.fqnt.ResultFor2(node.GetPosition(), cls.Name)
+			}
+
+			if qualified == nil {
+				continue
+			}
+
+			if _, ok := index.Current.Find(qualified); ok {
+				continue
+			}
+
+			t.violations = append(t.violations, &Violation{
+				Position: d.position(n),
+				Kind:     KindUnknownClass,
+				Tag:      tag,
+				Name:     qualified.String(),
+			})
+		}
+	}
+}
+
+// templates returns the names of the templates declared on the node and the
+// class it is in, these are used as class-like types.
+func (t *phpdocTraverser) templates(node ast.Vertex) *set.Set[string] {
+	templates := set.New[string]()
+	for _, scope := range []ast.Vertex{t.classes[len(t.classes)-1], node} {
+		if scope.GetType() == ast.TypeRoot {
+			continue
+		}
+
+		for _, d := range t.docs(scope) {
+			for _, n := range d.nodes {
+				if template, ok := n.(*phpdoxer.NodeTemplate); ok {
+					templates.Add(template.Name)
+				}
+			}
+		}
+	}
+
+	return templates
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,73 @@
+package phpdoc_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/laytan/phpls/internal/config"
+	"github.com/laytan/phpls/internal/index"
+	"github.com/laytan/phpls/internal/phpdoc"
+	"github.com/laytan/phpls/internal/project"
+	"github.com/laytan/phpls/internal/wrkspc"
+	"github.com/laytan/phpls/pkg/functional"
+	"github.com/laytan/phpls/pkg/pathutils"
+	"github.com/laytan/phpls/pkg/phpversion"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(
+		m,
+		// The cache size logger.
+		goleak.IgnoreTopFunction("github.com/laytan/phpls/internal/wrkspc.New.func1"),
+	)
+}
+
+func TestDiagnose(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.Join(pathutils.Root(), "internal", "phpdoc", "testdata")
+
+	err := setup(root, phpversion.EightOne())
+	require.NoError(t, err)
+
+	format := func(v *phpdoc.Violation) string {
+		return fmt.Sprintf("%d %s %s", v.Line(), v.Code(), v.Message())
+	}
+
+	expected := []string{
+		`15 missing-doc-param Missing @param for parameter $b of function mismatch().`,
+		`17 unknown-doc-param @param $c does not match any parameter of function mismatch().`,
+		`24 invalid-doc-type Unable to parse type "array<int, string $a" of @param.`,
+		`25 invalid-doc-type Unable to parse type "Foo|" of @return.`,
+		`33 invalid-doc-return Unexpected @return on function void(), it returns void.`,
+		`47 unknown-doc-class Unknown class \PHPDoc\TestData\Missing in @param.`,
+		`50 unknown-doc-class Unknown class \PHPDoc\TestData\Unknown in @return.`,
+		`51 unknown-doc-class Unknown class \PHPDoc\TestData\Missing in @throws.`,
+		`59 invalid-doc-version Invalid version "nope" in @since.`,
+		`79 unknown-doc-class Unknown class \PHPDoc\TestData\Missing in @var.`,
+		`83 invalid-doc-return Unexpected @return on a constructor.`,
+	}
+
+	violations := phpdoc.Diagnose(wrkspc.NewRooter(filepath.Join(root, "phpdoc.php")))
+	require.Equal(t, expected, functional.Map(violations, format))
+}
+
+func setup(root string, phpv *phpversion.PHPVersion) error {
+	config.Current = config.Default()
+	index.Current = index.New(phpv)
+	wrkspc.Current = wrkspc.New(
+		phpv,
+		root,
+		filepath.Join(pathutils.Root(), "third_party", "phpstorm-stubs"),
+	)
+
+	p := project.New()
+	if err := p.ParseWithoutProgress(); err != nil {
+		return fmt.Errorf("[phpdoc_test.setup]: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,7 @@
+<?php
+
+namespace PHPDoc\TestData\Other;
+
+class Imported
+{
+}
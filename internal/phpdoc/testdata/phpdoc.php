@@ -0,0 +1,98 @@
+<?php
+
+namespace PHPDoc\TestData;
+
+use PHPDoc\TestData\Other\Imported;
+
+/**
+ * @param int $a
+ * @param string $b The second parameter.
+ */
+function documented(int $a, string $b): void
+{
+}
+
+/**
+ * @param int $a
+ * @param string $c
+ */
+function mismatch(int $a, string $b): void
+{
+}
+
+/**
+ * @param array<int, string $a
+ * @return Foo|
+ */
+function unparsable($a)
+{
+    return $a;
+}
+
+/**
+ * @return int
+ */
+function void(): void
+{
+}
+
+/**
+ * @return void
+ */
+function explicitVoid(): void
+{
+}
+
+/**
+ * @param Missing $a
+ * @param Imported $b
+ * @param \PHPDoc\TestData\Model|null $c
+ * @return array<int, Unknown>
+ * @throws \PHPDoc\TestData\Missing
+ */
+function classes($a, $b, $c): array
+{
+    return [];
+}
+
+/**
+ * @since nope
+ */
+function since(): void
+{
+}
+
+/**
+ * @template T
+ * @param T $value
+ * @param int &$ref
+ * @param mixed ...$rest
+ * @return T
+ */
+function template($value, &$ref, ...$rest)
+{
+    return $value;
+}
+
+class Model
+{
+    /** @var Missing */
+    public $missing;
+
+    /**
+     * @return static
+     */
+    public function __construct()
+    {
+    }
+
+    /**
+     * Undocumented parameters are fine if none are documented.
+     *
+     * @return self
+     */
+    public function fluent(int $a): self
+    {
+        return $this;
+    }
+}
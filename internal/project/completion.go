@@ -179,6 +179,10 @@ func NodeCompletionKind(kind ast.Type) protocol.CompletionItemKind {
 		return protocol.ClassCompletion
 	case ast.TypeStmtInterface:
 		return protocol.InterfaceCompletion
+	case ast.TypeStmtEnum:
+		return protocol.EnumCompletion
+	case ast.TypeStmtConstant:
+		return protocol.ConstantCompletion
 	case ast.TypeStmtClassMethod:
 		return protocol.MethodCompletion
 	case ast.TypeParameter: // Parameter is a property, because this is only called with constructor promoted properties.
@@ -461,6 +465,18 @@ func AddExprCompletions(
 				break
 			}
 			item.Detail = ptype.String()
+		case *symbol.VirtualMethod:
+			item.Kind = protocol.MethodCompletion
+			item.Detail = "mixed"
+			if rtype := tm.Returns(); rtype != nil {
+				item.Detail = rtype.String()
+			}
+		case *symbol.VirtualProperty:
+			item.Kind = protocol.PropertyCompletion
+			item.Detail = "mixed"
+			if ptype := tm.Type(); ptype != nil {
+				item.Detail = ptype.String()
+			}
 		}
 
 		list.Items = append(list.Items, item)
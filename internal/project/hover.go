@@ -11,16 +11,24 @@ import (
 	"github.com/laytan/phpls/internal/symbol"
 	"github.com/laytan/phpls/internal/throws"
 	"github.com/laytan/phpls/internal/wrkspc"
+	"github.com/laytan/phpls/pkg/nodescopes"
 	"github.com/laytan/phpls/pkg/position"
 	"github.com/laytan/phpls/pkg/traversers"
 )
 
 func (p *Project) Hover(currpos *position.Position) string {
-	nodes, root := nodeToHover(p, currpos)
+	nodes, root, pos := nodeToHover(p, currpos)
 	if len(nodes) == 0 || root == nil {
 		return ""
 	}
 
+	// Virtual members are declared in the doc comment of the class.
+	if last := nodes[len(nodes)-1]; nodescopes.IsClassLike(last.GetType()) {
+		if virtual := virtualMemberHover(root, last, pos); len(virtual) > 0 {
+			return wrapWithPhpMarkdown(strings.Join(virtual, "\n"))
+		}
+	}
+
 	top := []string{}
 	out := []string{}
 
@@ -97,15 +105,18 @@ Nodes:
 	return strings.Join(top, "\n") + "\n" + outStr
 }
 
-func nodeToHover(p *Project, currpos *position.Position) ([]ast.Vertex, *ast.Root) {
-	napper := func(pos *position.Position) ([]ast.Vertex, *ast.Root) {
+// nodeToHover returns the nodes at the definition of the symbol at the
+// position, or at the position itself if it has no definition. The root and
+// position of the definition are returned too.
+func nodeToHover(p *Project, currpos *position.Position) ([]ast.Vertex, *ast.Root, *position.Position) {
+	napper := func(pos *position.Position) ([]ast.Vertex, *ast.Root, *position.Position) {
 		content, root := wrkspc.Current.FAllOf(pos.Path)
 		apos := position.LocToPos(content, pos.Row, pos.Col)
 		nap := traversers.NewNodeAtPos(int(apos))
 		napt := traverser.NewTraverser(nap)
 		root.Accept(napt)
 
-		return nap.Nodes, root
+		return nap.Nodes, root, pos
 	}
 
 	poss, err := p.Definition(currpos)
@@ -117,6 +128,52 @@ func nodeToHover(p *Project, currpos *position.Position) ([]ast.Vertex, *ast.Roo
 	return napper(pos)
 }
 
+// virtualMemberHover returns the tag and signature of the virtual member,
+// declared with @property or @method in the doc comment of the class, that is
+// at the position.
+func virtualMemberHover(root *ast.Root, class ast.Vertex, pos *position.Position) []string {
+	apos := int(position.LocToPos(wrkspc.Current.FContentOf(pos.Path), pos.Row, pos.Col))
+	at := func(node ast.Vertex) bool {
+		nodePos := node.GetPosition()
+		return nodePos.StartPos <= apos && apos < nodePos.EndPos
+	}
+
+	cls := symbol.NewClassLike(wrkspc.NewRooter(pos.Path, root), class)
+	for _, prop := range cls.VirtualProperties() {
+		if !at(prop.Vertex()) {
+			continue
+		}
+
+		doc := prop.Doc()
+		signature := "public " + doc.Name
+		if doc.Type != nil {
+			signature = "public " + doc.Type.String() + " " + doc.Name
+		}
+
+		return []string{fmt.Sprintf("/**\n * %s\n */", doc), signature + ";"}
+	}
+
+	for _, method := range cls.VirtualMethods() {
+		if !at(method.Vertex()) {
+			continue
+		}
+
+		doc := method.Doc()
+		signature := "public function " + doc.Signature()
+		if doc.Static {
+			signature = "public static function " + doc.Signature()
+		}
+
+		if doc.Return != nil {
+			signature += ": " + doc.Return.String()
+		}
+
+		return []string{fmt.Sprintf("/**\n * %s\n */", doc), signature + ";"}
+	}
+
+	return nil
+}
+
 func NodeSignature(node ast.Vertex) string {
 	if node == nil {
 		return ""
@@ -0,0 +1,30 @@
+<?php
+
+namespace Definitions\Test\Generics;
+
+/**
+ * @template TKey
+ * @template TValue
+ */
+class Collection
+{
+    /**
+     * @var TValue
+     */
+    public $last;
+
+    /**
+     * @return TValue
+     */
+    public function first() {}
+
+    /**
+     * @return static
+     */
+    public function filter() {}
+
+    /**
+     * @return Collection<TKey, TValue>
+     */
+    public function values() {}
+}
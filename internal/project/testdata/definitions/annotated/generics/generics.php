@@ -0,0 +1,120 @@
+<?php
+
+namespace Definitions\Test\Generics;
+
+class User
+{
+    public string $name; // @t_out(generics_var, 5) @t_out(generics_extends, 5) @t_out(generics_property, 5) @t_out(generics_static, 5) @t_out(generics_returned, 5) @t_out(generics_implements, 5) @t_out(generics_use, 5) @t_out(generics_class_string, 5) @t_out(generics_function, 5) @t_out(generics_bound, 5) @t_out(generics_shadowed, 5)
+}
+
+/**
+ * @extends Collection<int, User>
+ */
+class UserCollection extends Collection
+{
+}
+
+/**
+ * @template T
+ */
+interface Repository
+{
+    /**
+     * @return T
+     */
+    public function find(int $id);
+}
+
+/**
+ * @implements Repository<User>
+ */
+class UserRepository implements Repository
+{
+    public function find(int $id) {}
+}
+
+/**
+ * @template T
+ */
+trait HasItem
+{
+    /**
+     * @return T
+     */
+    public function item() {}
+}
+
+class Box
+{
+    /**
+     * @use HasItem<User>
+     */
+    use HasItem;
+}
+
+class Container
+{
+    /**
+     * @template T
+     * @param class-string<T> $class
+     * @return T
+     */
+    public function make(string $class) {}
+}
+
+/**
+ * @template T
+ */
+class Wrapper
+{
+    /**
+     * @return T
+     */
+    public function get() {}
+}
+
+/**
+ * @template T
+ * @extends Wrapper<User>
+ */
+class Pair extends Wrapper
+{
+    public function get() {}
+}
+
+/**
+ * @template T of User
+ * @param T $value
+ * @return T
+ */
+function identity($value) {}
+
+/**
+ * @var Collection<int, User>
+ */
+$collection = $somekindofmagic;
+$collection->first()->name; // @t_in(generics_var, 24)
+
+$users = new UserCollection();
+$users->first()->name; // @t_in(generics_extends, 19)
+$users->last->name; // @t_in(generics_property, 16)
+$users->filter()->first()->name; // @t_in(generics_static, 29)
+$users->values()->first()->name; // @t_in(generics_returned, 29)
+
+$repository = new UserRepository();
+$repository->find(1)->name; // @t_in(generics_implements, 24)
+
+$box = new Box();
+$box->item()->name; // @t_in(generics_use, 15)
+
+$container = new Container();
+$container->make(User::class)->name; // @t_in(generics_class_string, 33)
+
+identity(new User())->name; // @t_in(generics_function, 23)
+identity($somekindofmagic)->name; // @t_in(generics_bound, 29)
+
+/**
+ * @var Pair<Container>
+ */
+$pair = $somekindofmagic;
+$pair->get()->name; // @t_in(generics_shadowed, 15)
@@ -0,0 +1,61 @@
+<?php
+
+namespace Definitions\Test\Magic;
+
+class Address
+{
+    public string $street; // @t_out(magic_chained_property, 5)
+}
+
+/**
+ * @template TModel
+ *
+ * @method TModel first() // @t_out(magic_template, 19)
+ */
+class Builder
+{
+}
+
+/**
+ * @property string $name The name. // @t_out(magic_property, 21) @t_out(magic_inherited, 21) @t_out(magic_static_chain, 21)
+ * @property-read int $id // @t_out(magic_property_read, 23) @t_out(magic_static_returns_static, 23)
+ * @property-write string $password // @t_out(magic_property_write, 27)
+ * @property Address $address
+ * @method void save() // @t_out(magic_method, 17)
+ * @method static Builder<User> where(string $column, mixed $value = null) // @t_out(magic_static_method, 33)
+ * @method static static find(int $id)
+ */
+class User
+{
+    public function __get($name)
+    {
+    }
+
+    public function __call($name, $arguments)
+    {
+    }
+
+    public static function __callStatic($name, $arguments)
+    {
+    }
+}
+
+class Admin extends User
+{
+}
+
+$user = new User();
+$user->name; // @t_in(magic_property, 9)
+$user->id; // @t_in(magic_property_read, 9)
+$user->password = 'secret'; // @t_in(magic_property_write, 9)
+$user->save(); // @t_in(magic_method, 9)
+User::where('name'); // @t_in(magic_static_method, 8)
+User::where('name')->first()->name; // @t_in(magic_template, 23) @t_in(magic_static_chain, 32)
+$user->address->street; // @t_in(magic_chained_property, 18)
+User::find(1)->id; // @t_in(magic_static_returns_static, 17)
+$user->save; // @t_nodef(magic_method_as_property, 9)
+User::save(); // @t_nodef(magic_method_not_static, 8)
+
+$admin = new Admin();
+$admin->name; // @t_in(magic_inherited, 10)
+
@@ -0,0 +1,95 @@
+<?php
+
+namespace Definitions\Test\Mixins;
+
+class Post
+{
+    public string $title; // @t_out(mixins_generic, 5)
+}
+
+/**
+ * @template TModel
+ */
+class Builder
+{
+    /**
+     * @return TModel
+     */
+    public function first()
+    {
+    }
+
+    public function where(): static // @t_out(mixins_method, 5) @t_out(mixins_parent, 5)
+    {
+    }
+
+    public function shared(): void
+    {
+    }
+
+    private function secret(): void
+    {
+    }
+
+    public $limit; // @t_out(mixins_property, 5)
+}
+
+class Query
+{
+    public function first(): void // @t_out(mixins_class_before_parent, 5)
+    {
+    }
+
+    public function annotated(): void
+    {
+    }
+}
+
+/**
+ * @mixin Builder<Post>
+ */
+class Model
+{
+    public function shared(): void // @t_out(mixins_native_first, 5)
+    {
+    }
+}
+
+/**
+ * @mixin Query
+ * @method void annotated() // @t_out(mixins_annotated_first, 17)
+ */
+class PostModel extends Model
+{
+}
+
+/**
+ * @mixin Ping
+ */
+class Pong
+{
+}
+
+/**
+ * @mixin Pong
+ */
+class Ping
+{
+}
+
+$model = new Model();
+$model->where(); // @t_in(mixins_method, 10)
+$model->limit; // @t_in(mixins_property, 10)
+$model->first()->title; // @t_in(mixins_generic, 19)
+$model->shared(); // @t_in(mixins_native_first, 10)
+$model->secret(); // @t_nodef(mixins_private, 10)
+
+$post = new PostModel();
+$post->where(); // @t_in(mixins_parent, 9)
+$post->annotated(); // @t_in(mixins_annotated_first, 9)
+
+$query = new PostModel();
+$query->first(); // @t_in(mixins_class_before_parent, 10)
+
+$ping = new Ping();
+$ping->missing(); // @t_nodef(mixins_cycle, 9)
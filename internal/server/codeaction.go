@@ -0,0 +1,49 @@
+package server
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/laytan/go-lsp-protocol/pkg/lsp/protocol"
+	"github.com/laytan/phpls/internal/codeactions"
+	"github.com/laytan/phpls/internal/config"
+	"github.com/laytan/phpls/internal/wrkspc"
+	"github.com/laytan/phpls/pkg/position"
+)
+
+func (s *Server) CodeAction(
+	ctx context.Context,
+	params *protocol.CodeActionParams,
+) ([]protocol.CodeAction, error) {
+	if err := s.isMethodAllowed("CodeAction"); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	defer func() { log.Printf("Retrieving code actions took %s\n", time.Since(start)) }()
+
+	path := position.URIToFile(string(params.TextDocument.URI))
+	content, root := wrkspc.Current.FAllOf(path)
+
+	var fixer codeactions.SniffFixer
+	if s.phpcbf.HasExecutable() {
+		fixer = s.phpcbf
+	}
+
+	actions, err := codeactions.Provide(&codeactions.Params{
+		Path:        path,
+		Content:     content,
+		Root:        root,
+		Range:       params.Range,
+		Diagnostics: params.Context.Diagnostics,
+		PHPVersion:  config.Current.PhpVersion,
+		Phpcbf:      fixer,
+	}, params.Context.Only)
+	if err != nil {
+		// Still return the actions of the providers that did not error.
+		log.Printf("[ERROR]: retrieving code actions: %v", err)
+	}
+
+	return actions, nil
+}
@@ -0,0 +1,104 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/laytan/go-lsp-protocol/pkg/lsp/protocol"
+	"github.com/laytan/phpls/pkg/lsperrors"
+)
+
+const (
+	// Runs all analyzers over the project and writes the results to the
+	// configured baseline file, after which only new diagnostics are reported.
+	commandGenerateBaseline = "phpls.generateBaseline"
+	// Runs all analyzers over the project and publishes the results, also for
+	// files that are not open.
+	commandAnalyzeWorkspace = "phpls.analyzeWorkspace"
+)
+
+var commands = []string{commandGenerateBaseline, commandAnalyzeWorkspace}
+
+func (s *Server) ExecuteCommand(
+	_ context.Context,
+	params *protocol.ExecuteCommandParams,
+) (any, error) {
+	if err := s.isMethodAllowed("ExecuteCommand"); err != nil {
+		return nil, err
+	}
+
+	switch params.Command {
+	case commandGenerateBaseline:
+		return nil, s.generateBaseline()
+	case commandAnalyzeWorkspace:
+		return nil, s.analyzeWorkspace()
+	default:
+		return nil, lsperrors.ErrRequestFailed(fmt.Sprintf("Unknown command %q", params.Command))
+	}
+}
+
+// WorkDoneProgressCancel cancels the project wide analysis of the commands.
+func (s *Server) WorkDoneProgressCancel(
+	_ context.Context,
+	params *protocol.WorkDoneProgressCancelParams,
+) error {
+	if err := s.isMethodAllowed("WorkDoneProgressCancel"); err != nil {
+		return err
+	}
+
+	if s.diag == nil || !s.diag.CancelProgress(params.Token) {
+		return lsperrors.ErrRequestFailed(
+			fmt.Sprintf("No cancellable progress with token %v", params.Token),
+		)
+	}
+
+	return nil
+}
+
+func (s *Server) generateBaseline() error {
+	if s.diag == nil {
+		return lsperrors.ErrRequestFailed("Diagnostics are disabled")
+	}
+
+	s.inBackground("Generating baseline", func(ctx context.Context) (string, error) {
+		total, err := s.diag.GenerateBaseline(ctx, nil)
+		return fmt.Sprintf("Baseline generated with %d diagnostics", total), err
+	})
+	return nil
+}
+
+func (s *Server) analyzeWorkspace() error {
+	if s.diag == nil {
+		return lsperrors.ErrRequestFailed("Diagnostics are disabled")
+	}
+
+	s.inBackground("Analyzing workspace", func(ctx context.Context) (string, error) {
+		total, err := s.diag.AnalyzeWorkspace(ctx, nil)
+		return fmt.Sprintf("Analyzed %d files", total), err
+	})
+	return nil
+}
+
+// inBackground runs the work of a command after replying to it, requests are
+// handled one at a time so a project wide run would block all others.
+//
+// The client's work done token is only valid during the request, so the work
+// reports progress with a token of its own, which the client can cancel. The
+// result of the work is shown as a message.
+func (s *Server) inBackground(title string, work func(ctx context.Context) (string, error)) {
+	go func() {
+		// The work outlives the request, so it does not use its context.
+		ctx := context.Background()
+
+		msg, err := work(ctx)
+		switch {
+		case errors.Is(err, context.Canceled):
+			s.showAndLog(ctx, protocol.Info, title+" cancelled")
+		case err != nil:
+			s.showAndLog(ctx, protocol.Error, fmt.Errorf("%s: %w", title, err))
+		default:
+			s.showAndLog(ctx, protocol.Info, msg)
+		}
+	}()
+}
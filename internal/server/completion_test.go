@@ -495,6 +495,10 @@ func (*mockWrkspc) IsPhpFile(path string) bool {
 	panic("unimplemented")
 }
 
+func (*mockWrkspc) ProjectFiles() ([]string, error) {
+	panic("unimplemented")
+}
+
 func (mockWrkspc) AllOf(path string) (string, *ast.Root, error) {
 	panic("unimplemented")
 }
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/laytan/go-lsp-protocol/pkg/lsp/protocol"
+	"github.com/laytan/phpls/internal/formatting"
 	"github.com/laytan/phpls/pkg/lsperrors"
 	"github.com/laytan/phpls/pkg/position"
 )
@@ -18,7 +19,7 @@ func (s *Server) Formatting(
 		return nil, err
 	}
 
-	if !s.phpcbf.HasExecutable() {
+	if s.formatter == nil {
 		return nil, nil
 	}
 
@@ -27,7 +28,11 @@ func (s *Server) Formatting(
 		go s.showAndLog(ctx, protocol.Info, fmt.Errorf("formatting took %s", time.Since(start)))
 	}()
 
-	edits, err := s.phpcbf.FormatFileEdits(position.URIToFile(string(params.TextDocument.URI)))
+	edits, err := formatting.Edits(
+		ctx,
+		s.formatter,
+		position.URIToFile(string(params.TextDocument.URI)),
+	)
 	if err != nil {
 		err := lsperrors.ErrRequestFailed(err.Error())
 		go s.showAndLog(ctx, protocol.Error, err)
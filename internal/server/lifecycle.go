@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/laytan/go-lsp-protocol/pkg/lsp/protocol"
+	"github.com/laytan/phpls/internal/codeactions"
 	"github.com/laytan/phpls/internal/config"
 	"github.com/laytan/phpls/internal/index"
 	"github.com/laytan/phpls/internal/project"
@@ -105,6 +106,12 @@ func (s *Server) Initialize(
 			DocumentFormattingProvider: &protocol.Or_ServerCapabilities_documentFormattingProvider{
 				Value: true,
 			},
+			CodeActionProvider: &protocol.CodeActionOptions{
+				CodeActionKinds: codeactions.Kinds(),
+			},
+			ExecuteCommandProvider: &protocol.ExecuteCommandOptions{
+				Commands: commands,
+			},
 		},
 		ServerInfo: &protocol.PServerInfoMsg_initialize{
 			Name:    config.Name,
@@ -8,6 +8,7 @@ import (
 	"github.com/laytan/go-lsp-protocol/pkg/jsonrpc2"
 	"github.com/laytan/go-lsp-protocol/pkg/lsp/protocol"
 	"github.com/laytan/phpls/internal/diagnostics"
+	"github.com/laytan/phpls/internal/formatting"
 	"github.com/laytan/phpls/internal/project"
 	"github.com/laytan/phpls/pkg/lsperrors"
 	"github.com/laytan/phpls/pkg/lsprogress"
@@ -15,11 +16,14 @@ import (
 )
 
 func NewServer(client protocol.ClientCloser) *Server {
+	cbf := phpcbf.NewInstance()
+
 	return &Server{
-		client:   client,
-		progress: lsprogress.NewTracker(client),
-		phpcbf:   phpcbf.NewInstance(),
-		diag:     diagnostics.NewRunnerFromConfig(client),
+		client:    client,
+		progress:  lsprogress.NewTracker(client),
+		phpcbf:    cbf,
+		formatter: formatting.NewFromConfig(cbf),
+		diag:      diagnostics.NewRunnerFromConfig(client),
 	}
 }
 
@@ -36,6 +40,8 @@ type Server struct {
 	// NOTE: This can be nil if diagnostics are configured to be disabled!
 	diag   *diagnostics.Runner
 	phpcbf *phpcbf.Instance
+	// NOTE: This can be nil if the configured formatter is not available!
+	formatter formatting.Formatter
 }
 
 var _ protocol.Server = &Server{}
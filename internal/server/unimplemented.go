@@ -44,13 +44,6 @@ func (s *Server) DidSaveNotebookDocument(
 	return errorUnimplemented
 }
 
-func (s *Server) WorkDoneProgressCancel(
-	context.Context,
-	*protocol.WorkDoneProgressCancelParams,
-) error {
-	return errorUnimplemented
-}
-
 func (s *Server) InlayHint(
 	context.Context,
 	*protocol.InlayHintParams,
@@ -302,13 +295,6 @@ func (s *Server) DocumentSymbol(
 	return nil, errorUnimplemented
 }
 
-func (s *Server) CodeAction(
-	context.Context,
-	*protocol.CodeActionParams,
-) ([]protocol.CodeAction, error) {
-	return nil, errorUnimplemented
-}
-
 func (s *Server) ResolveCodeAction(
 	context.Context,
 	*protocol.CodeAction,
@@ -381,13 +367,6 @@ func (s *Server) PrepareRename(
 	return nil, errorUnimplemented
 }
 
-func (s *Server) ExecuteCommand(
-	context.Context,
-	*protocol.ExecuteCommandParams,
-) (any, error) {
-	return nil, errorUnimplemented
-}
-
 func (s *Server) Diagnostic(context.Context, *string) (*string, error) {
 	return nil, errorUnimplemented
 }
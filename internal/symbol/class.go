@@ -9,6 +9,7 @@ import (
 	"github.com/laytan/phpls/internal/wrkspc"
 	"github.com/laytan/phpls/pkg/fqn"
 	"github.com/laytan/phpls/pkg/nodeident"
+	"github.com/laytan/phpls/pkg/phpdoxer"
 	"github.com/laytan/phpls/pkg/traversers"
 )
 
@@ -30,6 +31,9 @@ type ClassLike struct {
 	fullyQualifier
 
 	node ast.Vertex
+
+	// The types the templates are bound to, see Bind.
+	generics []phpdoxer.Type
 }
 
 func NewClassLike(root rooter, node ast.Vertex) *ClassLike {
@@ -162,3 +166,9 @@ func (i *inheritor) Implements() []ast.Vertex {
 	i.ensureTraversed()
 	return i.traverser.implements
 }
+
+// traitUses returns the trait use statements, these can bind templates with @use.
+func (i *inheritor) traitUses() []*ast.StmtTraitUse {
+	i.ensureTraversed()
+	return i.traverser.traitUses
+}
@@ -142,6 +142,14 @@ func TypeHintToDocType(node ast.Vertex) (phpdoxer.Type, error) {
 }
 
 func NodeComments(node ast.Vertex) []string {
+	return functional.Map(NodeCommentTokens(node), func(t *token.Token) string {
+		return string(t.Value)
+	})
+}
+
+// NodeCommentTokens returns the comment tokens of the node, these have the
+// positions of the comments in the source.
+func NodeCommentTokens(node ast.Vertex) []*token.Token {
 	var ff []*token.Token
 	switch tn := node.(type) {
 	case *ast.StmtFunction:
@@ -239,15 +247,17 @@ func NodeComments(node ast.Vertex) []string {
 		ff = tn.ConstTkn.FreeFloating
 	case *ast.ExprVariable:
 		ff = tn.Name.(*ast.Identifier).IdentifierTkn.FreeFloating
+	case *ast.StmtTraitUse:
+		ff = tn.UseTkn.FreeFloating
 	}
 
-	docs := []string{}
+	docs := []*token.Token{}
 	for _, f := range ff {
 		if f.ID != token.T_COMMENT && f.ID != token.T_DOC_COMMENT {
 			continue
 		}
 
-		docs = append(docs, string(f.Value))
+		docs = append(docs, f)
 	}
 
 	return docs
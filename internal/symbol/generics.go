@@ -0,0 +1,231 @@
+package symbol
+
+import (
+	"github.com/laytan/php-parser/pkg/ast"
+	"github.com/laytan/php-parser/pkg/visitor/traverser"
+	"github.com/laytan/phpls/internal/doxcontext"
+	"github.com/laytan/phpls/pkg/fqn"
+	"github.com/laytan/phpls/pkg/phpdoxer"
+	"github.com/laytan/phpls/pkg/set"
+)
+
+// Templates returns the templates declared in the PHPDoc of the class-like, in
+// order of declaration.
+func (c *ClassLike) Templates() []*phpdoxer.NodeTemplate {
+	return templates(c.doxed)
+}
+
+// Bind returns a copy of the class-like with its templates bound to the given
+// fully qualified types, in order of declaration.
+func (c *ClassLike) Bind(generics []phpdoxer.Type) *ClassLike {
+	bound := *c
+	bound.generics = generics
+	return &bound
+}
+
+// Generics returns the types the templates of the class-like are bound to.
+func (c *ClassLike) Generics() []phpdoxer.Type {
+	return c.generics
+}
+
+// Bindings maps the templates of the class-like to the types they are bound
+// to, unbound templates are mapped to their bound (@template T of Foo), or mixed.
+func (c *ClassLike) Bindings() map[string]phpdoxer.Type {
+	return bindings(c.Templates(), c.generics)
+}
+
+// boundBy returns the class-like as bound by the given class-like inheriting
+// it, or the given class-like if it is the same class-like. The class-like
+// itself is returned if it is not inherited by the given class-like.
+func (c *ClassLike) boundBy(inheritor *ClassLike) *ClassLike {
+	if inheritor.GetFQN().String() == c.GetFQN().String() {
+		return inheritor
+	}
+
+	iter := inheritor.InheritsIter()
+	for inhCls, done, err := iter(); !done; inhCls, done, err = iter() {
+		if err != nil {
+			continue
+		}
+
+		if inhCls.GetFQN().String() == c.GetFQN().String() {
+			return inhCls
+		}
+	}
+
+	return c
+}
+
+// inheritedGenerics returns the types this class-like binds the templates of
+// the inherited class-like to, using @extends, @implements or @use tags.
+//
+// The types are qualified and the templates of this class-like are substituted.
+func (c *ClassLike) inheritedGenerics(inherited *ClassLike) []phpdoxer.Type {
+	docs := append([]phpdoxer.Node{}, c.Docs()...)
+	for _, use := range c.inheritor.traitUses() {
+		docs = append(docs, NewDoxed(use).Docs()...)
+	}
+
+	var fqnt *fqn.Traverser
+	for _, doc := range docs {
+		var typ phpdoxer.Type
+		switch typedDoc := doc.(type) {
+		case *phpdoxer.NodeExtends:
+			typ = typedDoc.Type
+		case *phpdoxer.NodeImplements:
+			typ = typedDoc.Type
+		case *phpdoxer.NodeUse:
+			typ = typedDoc.Type
+		}
+
+		cls, ok := typ.(*phpdoxer.TypeClassLike)
+		if !ok || len(cls.GenericOver) == 0 {
+			continue
+		}
+
+		if fqnt == nil {
+			fqnt = fqn.NewTraverser()
+			c.Root().Accept(traverser.NewTraverser(fqnt))
+		}
+
+		qualified := fqn.New(cls.Name)
+		if !cls.FullyQualified {
+			qualified = fqnt.ResultFor2(c.node.GetPosition(), cls.Name)
+		}
+
+		if qualified == nil || qualified.String() != inherited.GetFQN().String() {
+			continue
+		}
+
+		bindings := c.Bindings()
+		generics := make([]phpdoxer.Type, 0, len(cls.GenericOver))
+		for _, gen := range cls.GenericOver {
+			gen = doxcontext.Qualify(fqnt, c.GetFQN(), c.node.GetPosition(), Substitute(gen, bindings))
+			generics = append(generics, gen)
+		}
+
+		return generics
+	}
+
+	return nil
+}
+
+// Substitute returns a copy of the type with the templates in it replaced by
+// the type they are bound to.
+func Substitute(typ phpdoxer.Type, bindings map[string]phpdoxer.Type) phpdoxer.Type {
+	if len(bindings) == 0 {
+		return typ
+	}
+
+	return phpdoxer.Replace(typ, func(cls *phpdoxer.TypeClassLike) phpdoxer.Type {
+		if cls.FullyQualified || len(cls.GenericOver) > 0 {
+			return nil
+		}
+
+		return bindings[cls.Name]
+	})
+}
+
+func templates(d *doxed) (res []*phpdoxer.NodeTemplate) {
+	for _, doc := range d.Docs() {
+		if template, ok := doc.(*phpdoxer.NodeTemplate); ok {
+			res = append(res, template)
+		}
+	}
+
+	return res
+}
+
+func bindings(
+	templates []*phpdoxer.NodeTemplate,
+	generics []phpdoxer.Type,
+) map[string]phpdoxer.Type {
+	res := make(map[string]phpdoxer.Type, len(templates))
+	for i, template := range templates {
+		switch {
+		case i < len(generics) && generics[i] != nil:
+			res[template.Name] = generics[i]
+		case template.Of != nil:
+			res[template.Name] = template.Of
+		default:
+			res[template.Name] = &phpdoxer.TypeMixed{}
+		}
+	}
+
+	return res
+}
+
+// inferTemplates binds the templates of the callable to the types of the
+// arguments passed for parameters typed with the template, like "T" or
+// "class-string<T>", an argument type is nil if it is unknown.
+func (r *canReturn) inferTemplates(args []phpdoxer.Type) map[string]phpdoxer.Type {
+	templates := templates(r.doxed)
+	if len(templates) == 0 {
+		return nil
+	}
+
+	names := set.New[string]()
+	for _, template := range templates {
+		names.Add(template.Name)
+	}
+
+	var params []ast.Vertex
+	switch typedNode := r.node.(type) {
+	case *ast.StmtFunction:
+		params = typedNode.Params
+	case *ast.StmtClassMethod:
+		params = typedNode.Params
+	}
+
+	inferred := make(map[string]phpdoxer.Type, len(templates))
+	for i, param := range params {
+		if i >= len(args) {
+			break
+		}
+
+		if args[i] == nil {
+			continue
+		}
+
+		typ, err := NewParameter(r.rooter, r.node, param.(*ast.Parameter)).ownType()
+		if err != nil {
+			continue
+		}
+
+		infer(typ, args[i], names, inferred)
+	}
+
+	generics := make([]phpdoxer.Type, 0, len(templates))
+	for _, template := range templates {
+		generics = append(generics, inferred[template.Name])
+	}
+
+	return bindings(templates, generics)
+}
+
+func infer(param phpdoxer.Type, arg phpdoxer.Type, names *set.Set[string], inferred map[string]phpdoxer.Type) {
+	switch typed := param.(type) {
+	case *phpdoxer.TypeClassLike:
+		if !typed.FullyQualified && len(typed.GenericOver) == 0 && names.Has(typed.Name) {
+			inferred[typed.Name] = arg
+		}
+
+	case *phpdoxer.TypeConstant:
+		if typed.Class == nil && names.Has(typed.Const) {
+			inferred[typed.Const] = arg
+		}
+
+	case *phpdoxer.TypeString:
+		argStr, ok := arg.(*phpdoxer.TypeString)
+		if ok && typed.GenericOver != nil && argStr.GenericOver != nil && names.Has(typed.GenericOver.Name) {
+			inferred[typed.GenericOver.Name] = argStr.GenericOver
+		}
+
+	case *phpdoxer.TypePrecedence:
+		infer(typed.Type, arg, names, inferred)
+
+	case *phpdoxer.TypeUnion:
+		infer(typed.Left, arg, names, inferred)
+		infer(typed.Right, arg, names, inferred)
+	}
+}
@@ -37,6 +37,9 @@ type (
 //     3c. If the class extends another -> back to 3, otherwise 4.
 //  4. Any interface implementations
 //
+// The templates of the generated classes are bound by the class inheriting
+// them, see ClassLike.Bind.
+//
 // The generator returns true for done when there are no classes left in the chain.
 // If there was an error generating the next class, the newClsLikeErr argument will be set.
 func (c *ClassLike) InheritsIter() InheritsIterFunc {
@@ -96,6 +99,10 @@ func (c *ClassLike) InheritsIter() InheritsIterFunc {
 			return nil, false, err
 		}
 
+		if len(newC.Templates()) > 0 {
+			newC = newC.Bind(c.inheritedGenerics(newC))
+		}
+
 		// Recursively set the iter, this will make sure we go into traits,
 		// then into extends, and then into implements, recursively.
 		iter = newC.InheritsIter()
@@ -111,6 +118,7 @@ type inheritsTraverser struct {
 	uses       []ast.Vertex
 	extends    ast.Vertex
 	implements []ast.Vertex
+	traitUses  []*ast.StmtTraitUse
 
 	currNamespace string
 }
@@ -141,6 +149,7 @@ func (t *inheritsTraverser) EnterNode(node ast.Vertex) bool {
 		switch typedNode := node.(type) {
 		case *ast.StmtTraitUse:
 			t.uses = append(t.uses, functional.Map(typedNode.Traits, nodeToName)...)
+			t.traitUses = append(t.traitUses, typedNode)
 		case *ast.StmtClass:
 			t.implements = append(t.implements, functional.Map(typedNode.Implements, nodeToName)...)
 			if typedNode.Extends != nil {
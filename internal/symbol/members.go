@@ -5,6 +5,7 @@ import (
 	"log"
 
 	"github.com/laytan/php-parser/pkg/ast"
+	"github.com/laytan/phpls/pkg/set"
 )
 
 type Member interface {
@@ -16,8 +17,22 @@ type Member interface {
 func (p *Property) member() {}
 func (m *Method) member()   {}
 
+var (
+	_ Member = &Property{}
+	_ Member = &Method{}
+	_ Member = &VirtualProperty{}
+	_ Member = &VirtualMethod{}
+)
+
+// FindMember finds the first member of the class, falling back to the members
+// of its mixins.
 func (c *ClassLike) FindMember(filters ...FilterFunc[Member]) Member {
 	res := c.FindMembers(true, filters...)
+	if len(res) == 0 {
+		visited := set.NewFromSlice([]string{c.GetFQN().String()})
+		res = findMixinMembers([]*ClassLike{c}, true, filters, visited)
+	}
+
 	if len(res) == 0 {
 		return nil
 	}
@@ -25,8 +40,20 @@ func (c *ClassLike) FindMember(filters ...FilterFunc[Member]) Member {
 	return res[0]
 }
 
+// FindMembers finds the methods and properties of the class, followed by the
+// virtual methods and properties declared in its PHPDoc.
+//
 // TODO: also constants.
 func (c *ClassLike) FindMembers(shortCircuit bool, filters ...FilterFunc[Member]) (res []Member) {
+	res = c.findNativeMembers(shortCircuit, filters)
+	if shortCircuit && len(res) > 0 {
+		return res
+	}
+
+	return append(res, c.findVirtualMembers(shortCircuit, filters)...)
+}
+
+func (c *ClassLike) findNativeMembers(shortCircuit bool, filters []FilterFunc[Member]) (res []Member) {
 	miter := c.MethodsIter()
 MethodsIter:
 	for m, done, err := miter(); !done; m, done, err = miter() {
@@ -70,8 +97,35 @@ PropertiesIter:
 	return res
 }
 
+func (c *ClassLike) findVirtualMembers(shortCircuit bool, filters []FilterFunc[Member]) (res []Member) {
+	var virtuals []Member
+	for _, m := range c.VirtualMethods() {
+		virtuals = append(virtuals, m)
+	}
+
+	for _, p := range c.VirtualProperties() {
+		virtuals = append(virtuals, p)
+	}
+
+VirtualsIter:
+	for _, v := range virtuals {
+		for _, filter := range filters {
+			if !filter(v) {
+				continue VirtualsIter
+			}
+		}
+
+		res = append(res, v)
+		if shortCircuit {
+			return res
+		}
+	}
+
+	return res
+}
+
 func (c *ClassLike) FindMemberInherit(filters ...FilterFunc[Member]) Member {
-	res := c.FindMembers(true, filters...)
+	res := c.FindMembersInherit(true, filters...)
 	if len(res) == 0 {
 		return nil
 	}
@@ -79,10 +133,34 @@ func (c *ClassLike) FindMemberInherit(filters ...FilterFunc[Member]) Member {
 	return res[0]
 }
 
+// FindMembersInherit finds the members of the class and the classes it
+// inherits from, in the precedence PHPStan uses:
+//  1. The methods and properties of the class and inherited classes
+//  2. The virtual methods and properties declared in their PHPDoc
+//  3. The members of the mixins of the class, followed by the mixins of the
+//     inherited classes, each in this same precedence
 func (c *ClassLike) FindMembersInherit(
 	shortCircuit bool,
 	filters ...FilterFunc[Member],
 ) (res []Member) {
+	return c.findMembersInherit(shortCircuit, filters, set.New[string]())
+}
+
+// findMembersInherit is FindMembersInherit, visited contains the classes
+// that have already been searched, mixins can delegate to each other.
+func (c *ClassLike) findMembersInherit(
+	shortCircuit bool,
+	filters []FilterFunc[Member],
+	visited *set.Set[string],
+) (res []Member) {
+	visited.Add(c.GetFQN().String())
+
+	res = c.findNativeMembers(shortCircuit, filters)
+	if shortCircuit && len(res) > 0 {
+		return res
+	}
+
+	classes := []*ClassLike{c}
 	iter := c.InheritsIter()
 	for cls, done, err := iter(); !done; cls, done, err = iter() {
 		if err != nil {
@@ -94,11 +172,44 @@ func (c *ClassLike) FindMembersInherit(
 			continue
 		}
 
-		res = append(res, cls.FindMembers(shortCircuit, filters...)...)
+		classes = append(classes, cls)
+
+		res = append(res, cls.findNativeMembers(shortCircuit, filters)...)
+		if shortCircuit && len(res) > 0 {
+			return res
+		}
+	}
+
+	for _, cls := range classes {
+		res = append(res, cls.findVirtualMembers(shortCircuit, filters)...)
 		if shortCircuit && len(res) > 0 {
 			return res
 		}
 	}
 
+	return append(res, findMixinMembers(classes, shortCircuit, filters, visited)...)
+}
+
+// findMixinMembers finds the members of the mixins of the classes, skipping
+// the mixins that have been visited already.
+func findMixinMembers(
+	classes []*ClassLike,
+	shortCircuit bool,
+	filters []FilterFunc[Member],
+	visited *set.Set[string],
+) (res []Member) {
+	for _, cls := range classes {
+		for _, mixin := range cls.Mixins() {
+			if visited.Has(mixin.GetFQN().String()) {
+				continue
+			}
+
+			res = append(res, mixin.findMembersInherit(shortCircuit, filters, visited)...)
+			if shortCircuit && len(res) > 0 {
+				return res
+			}
+		}
+	}
+
 	return res
 }
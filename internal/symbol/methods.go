@@ -61,7 +61,7 @@ MethodsIter:
 		}
 	}
 
-	return nil
+	return res
 }
 
 type methodsTraverser struct {
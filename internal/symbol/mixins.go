@@ -0,0 +1,66 @@
+package symbol
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/laytan/php-parser/pkg/visitor/traverser"
+	"github.com/laytan/phpls/internal/doxcontext"
+	"github.com/laytan/phpls/internal/index"
+	"github.com/laytan/phpls/internal/wrkspc"
+	"github.com/laytan/phpls/pkg/fqn"
+	"github.com/laytan/phpls/pkg/phpdoxer"
+)
+
+// Mixins returns the class-likes declared with @mixin in the PHPDoc of the
+// class-like, their members are delegated to it, mostly using __call and __get.
+//
+// The templates of the class-like are substituted, and the templates of the
+// mixins are bound: "@mixin Builder<static>".
+func (c *ClassLike) Mixins() []*ClassLike {
+	var fqnt *fqn.Traverser
+	var mixins []*ClassLike
+	for _, doc := range c.Docs() {
+		mixinDoc, ok := doc.(*phpdoxer.NodeMixin)
+		if !ok {
+			continue
+		}
+
+		if fqnt == nil {
+			fqnt = fqn.NewTraverser()
+			c.Root().Accept(traverser.NewTraverser(fqnt))
+		}
+
+		typ := Substitute(mixinDoc.Type, c.Bindings())
+		typ = doxcontext.Qualify(fqnt, c.GetFQN(), c.node.GetPosition(), typ)
+
+		cls, ok := typ.(*phpdoxer.TypeClassLike)
+		if !ok || !cls.FullyQualified {
+			continue
+		}
+
+		qualified := fqn.New(cls.Name)
+		if qualified.String() == c.GetFQN().String() {
+			continue
+		}
+
+		iNode, ok := index.Current.Find(qualified)
+		if !ok {
+			continue
+		}
+
+		mixin, err := NewClassLikeFromFQN(wrkspc.NewRooter(iNode.Path), qualified)
+		if err != nil {
+			log.Println(fmt.Errorf("[symbol.ClassLike.Mixins]: %w", err))
+			continue
+		}
+
+		if len(cls.GenericOver) > 0 {
+			mixin = mixin.Bind(cls.GenericOver)
+		}
+
+		mixins = append(mixins, mixin)
+	}
+
+	return mixins
+}
@@ -14,6 +14,7 @@ type Modified interface {
 	Privacy() phprivacy.Privacy
 	CanBeAccessedFrom(phprivacy.Privacy) bool
 	IsFinal() bool
+	IsAbstract() bool
 	IsStatic() bool
 }
 
@@ -110,3 +111,7 @@ func (m *modified) IsStatic() bool {
 func (m *modified) IsFinal() bool {
 	return m.modifiers.Has("final")
 }
+
+func (m *modified) IsAbstract() bool {
+	return m.modifiers.Has("abstract")
+}
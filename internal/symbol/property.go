@@ -105,6 +105,7 @@ func (p *Property) ClsType() ([]*phpdoxer.TypeClassLike, error) {
 	tv := traverser.NewTraverser(fqnt)
 	cls.Root().Accept(tv)
 
+	doc = Substitute(doc, cls.Bindings())
 	return doxcontext.ApplyContext(fqnt, cls.GetFQN(), p.node.GetPosition(), doc), nil
 }
 
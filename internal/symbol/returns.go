@@ -55,12 +55,91 @@ func (r *canReturn) Returns() (phpdoxer.Type, *ClassLike, error) {
 // ReturnsClass resolves and unpacks the raw type returned from Returns into
 // the classes it represents.
 // See doxcontext.ApplyContext for more.
-func (r *canReturn) ReturnsClass() ([]*phpdoxer.TypeClassLike, error) { //nolint:dupl // Not really duplicated, could extract later.
+func (r *canReturn) ReturnsClass() ([]*phpdoxer.TypeClassLike, error) {
 	ret, cls, err := r.Returns()
 	if err != nil {
 		return nil, fmt.Errorf("getting return type to apply context: %w", err)
 	}
 
+	return r.applyContext(ret, cls)
+}
+
+// ReturnsClassBound is ReturnsClass with the templates in the return type
+// substituted.
+//
+// The templates of the class are bound by the given class-like the method is
+// called on, which is nil for functions. The templates of the callable itself
+// are inferred from the types of the given arguments.
+func (r *canReturn) ReturnsClassBound(
+	on *ClassLike,
+	args []phpdoxer.Type,
+) ([]*phpdoxer.TypeClassLike, error) {
+	ret, cls, err := r.Returns()
+	if err != nil {
+		return nil, fmt.Errorf("getting return type to substitute templates: %w", err)
+	}
+
+	// The templates of the callable shadow the templates of the class.
+	inferred := r.inferTemplates(args)
+	if cls != nil {
+		ret = bindClass(cls, on, ret, inferred)
+	}
+
+	return r.applyContext(Substitute(ret, inferred), cls)
+}
+
+// bindClass substitutes the templates of the class-like that declares the
+// return type, bound by the class-like the method is called on (nil if
+// unknown), and replaces static and $this in it with that class-like. Self is
+// replaced with the declaring class-like, like PHP does.
+//
+// The declaring class is bound separately from on, their templates can have
+// the same name while on binds the declaring class to other types. Templates
+// in shadowed are left to be substituted by the callable.
+func bindClass(
+	declaring *ClassLike,
+	on *ClassLike,
+	ret phpdoxer.Type,
+	shadowed map[string]phpdoxer.Type,
+) phpdoxer.Type {
+	if on != nil {
+		declaring = declaring.boundBy(on)
+
+		// Keep the generics of the class when it returns itself.
+		ret = phpdoxer.Replace(ret, func(typ *phpdoxer.TypeClassLike) phpdoxer.Type {
+			switch typ.Name {
+			case "static", "$this":
+				return &phpdoxer.TypeClassLike{
+					Name:           on.GetFQN().String(),
+					FullyQualified: true,
+					GenericOver:    on.Generics(),
+				}
+			case "self":
+				generics := typ.GenericOver
+				if len(generics) == 0 {
+					generics = declaring.Generics()
+				}
+
+				return &phpdoxer.TypeClassLike{
+					Name:           declaring.GetFQN().String(),
+					FullyQualified: true,
+					GenericOver:    generics,
+				}
+			default:
+				return nil
+			}
+		})
+	}
+
+	bindings := declaring.Bindings()
+	for template := range shadowed {
+		delete(bindings, template)
+	}
+
+	return Substitute(ret, bindings)
+}
+
+func (r *canReturn) applyContext(ret phpdoxer.Type, cls *ClassLike) ([]*phpdoxer.TypeClassLike, error) {
 	fqnt := fqn.NewTraverser()
 	fqntt := traverser.NewTraverser(fqnt)
 	var currFqn *fqn.FQN
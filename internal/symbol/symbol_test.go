@@ -12,6 +12,7 @@ import (
 	"github.com/laytan/phpls/internal/project"
 	"github.com/laytan/phpls/internal/symbol"
 	"github.com/laytan/phpls/internal/wrkspc"
+	"github.com/laytan/phpls/pkg/fqn"
 	"github.com/laytan/phpls/pkg/pathutils"
 	"github.com/laytan/phpls/pkg/phprivacy"
 	"github.com/laytan/phpls/pkg/phpversion"
@@ -91,3 +92,74 @@ func setup(root string, phpv *phpversion.PHPVersion) error {
 
 	return nil
 }
+
+func TestFindMembersInherit(t *testing.T) {
+	err := setup(
+		filepath.Join(pathutils.Root(), "internal", "symbol", "testdata"),
+		phpversion.EightOne(),
+	)
+	require.NoError(t, err)
+
+	path := filepath.Join(pathutils.Root(), "internal", "symbol", "testdata", "members.php")
+	cls, err := symbol.NewClassLikeFromFQN(wrkspc.NewRooter(path), fqn.New(`\Members`))
+	require.NoError(t, err)
+
+	members := cls.FindMembersInherit(false)
+	names := make([]string, 0, len(members))
+	for _, m := range members {
+		names = append(names, m.Name())
+	}
+
+	// Native members, then members declared in PHPDoc, then mixins of the class
+	// followed by the mixins of the parent.
+	require.Equal(t, []string{
+		"native",
+		"inherited",
+		"$name",
+		"virtual",
+		"native",
+		"mixed",
+		"mixed",
+		"parentMixed",
+	}, names)
+
+	mixed := cls.FindMemberInherit(symbol.FilterName[symbol.Member]("mixed"))
+	require.NotNil(t, mixed)
+	// The method of MembersMixin, not of MembersParentMixin.
+	require.Equal(t, 7, mixed.Vertex().GetPosition().StartLine)
+
+	require.NotNil(t, cls.FindMember(symbol.FilterName[symbol.Member]("mixed")))
+	require.Nil(t, cls.FindMember(symbol.FilterName[symbol.Member]("parentMixed")))
+	require.NotNil(t, cls.FindMemberInherit(symbol.FilterName[symbol.Member]("parentMixed")))
+}
+
+func TestReturnsClassBoundSelf(t *testing.T) {
+	err := setup(
+		filepath.Join(pathutils.Root(), "internal", "symbol", "testdata"),
+		phpversion.EightOne(),
+	)
+	require.NoError(t, err)
+
+	rooter := wrkspc.NewRooter(
+		filepath.Join(pathutils.Root(), "internal", "symbol", "testdata", "returns.php"),
+	)
+	node, err := symbol.NewClassLikeFromFQN(rooter, fqn.New(`\ReturnsNode`))
+	require.NoError(t, err)
+	leaf, err := symbol.NewClassLikeFromFQN(rooter, fqn.New(`\ReturnsLeaf`))
+	require.NoError(t, err)
+
+	returns := func(method string) string {
+		m := node.FindMethod(symbol.FilterName[*symbol.Method](method))
+		require.NotNil(t, m)
+
+		res, err := m.ReturnsClassBound(leaf, nil)
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+
+		return res[0].Name
+	}
+
+	// Self is the declaring class, static is the class the method is called on.
+	require.Equal(t, `\ReturnsNode`, returns("copy"))
+	require.Equal(t, `\ReturnsLeaf`, returns("fresh"))
+}
@@ -0,0 +1,33 @@
+<?php
+
+class MembersMixin
+{
+    public function native() {}
+
+    public function mixed() {}
+}
+
+class MembersParentMixin
+{
+    public function mixed() {}
+
+    public function parentMixed() {}
+}
+
+/**
+ * @method void virtual()
+ * @mixin MembersParentMixin
+ */
+class MembersParent
+{
+    public function inherited() {}
+}
+
+/**
+ * @property string $name
+ * @mixin MembersMixin
+ */
+class Members extends MembersParent
+{
+    public function native() {}
+}
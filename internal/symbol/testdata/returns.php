@@ -0,0 +1,18 @@
+<?php
+
+class ReturnsNode
+{
+    /**
+     * @return self
+     */
+    public function copy() {}
+
+    /**
+     * @return static
+     */
+    public function fresh() {}
+}
+
+class ReturnsLeaf extends ReturnsNode
+{
+}
@@ -0,0 +1,224 @@
+package symbol
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/laytan/php-parser/pkg/ast"
+	"github.com/laytan/php-parser/pkg/position"
+	"github.com/laytan/php-parser/pkg/token"
+	"github.com/laytan/php-parser/pkg/visitor/traverser"
+	"github.com/laytan/phpls/internal/doxcontext"
+	"github.com/laytan/phpls/pkg/fqn"
+	"github.com/laytan/phpls/pkg/phpdoxer"
+	"github.com/laytan/phpls/pkg/set"
+)
+
+// VirtualProperty is a property declared in the PHPDoc of a class with
+// @property, @property-read or @property-write.
+type VirtualProperty struct {
+	*modified
+
+	cls  *ClassLike
+	doc  *phpdoxer.NodeProperty
+	node *ast.Identifier
+}
+
+// VirtualMethod is a method declared in the PHPDoc of a class with @method.
+type VirtualMethod struct {
+	*modified
+
+	cls  *ClassLike
+	doc  *phpdoxer.NodeMethod
+	node *ast.Identifier
+}
+
+func (p *VirtualProperty) member() {}
+func (m *VirtualMethod) member()   {}
+
+func (p *VirtualProperty) Name() string {
+	return p.doc.Name
+}
+
+func (p *VirtualProperty) Doc() *phpdoxer.NodeProperty {
+	return p.doc
+}
+
+// Vertex returns an identifier positioned at the name of the property in the
+// doc comment of the class.
+func (p *VirtualProperty) Vertex() ast.Vertex {
+	return p.node
+}
+
+// Type returns the type of the property, nil if it is not given.
+func (p *VirtualProperty) Type() phpdoxer.Type {
+	return p.doc.Type
+}
+
+func (p *VirtualProperty) ClsType() ([]*phpdoxer.TypeClassLike, error) {
+	if p.doc.Type == nil {
+		return nil, fmt.Errorf("virtual property %s has no type: %w", p.Name(), ErrNoPropertyType)
+	}
+
+	return p.cls.applyContext(Substitute(p.doc.Type, p.cls.Bindings())), nil
+}
+
+func (m *VirtualMethod) Name() string {
+	return m.doc.Name
+}
+
+func (m *VirtualMethod) Doc() *phpdoxer.NodeMethod {
+	return m.doc
+}
+
+// Vertex returns an identifier positioned at the name of the method in the
+// doc comment of the class.
+func (m *VirtualMethod) Vertex() ast.Vertex {
+	return m.node
+}
+
+// Returns returns the return type of the method, nil if it is not given.
+func (m *VirtualMethod) Returns() phpdoxer.Type {
+	return m.doc.Return
+}
+
+// ReturnsClassBound resolves the return type into the classes it represents,
+// with the templates of the class substituted.
+//
+// See canReturn.ReturnsClassBound, on is the class-like the method is called on.
+func (m *VirtualMethod) ReturnsClassBound(on *ClassLike) ([]*phpdoxer.TypeClassLike, error) {
+	if m.doc.Return == nil {
+		return nil, fmt.Errorf("virtual method %s has no return type: %w", m.Name(), ErrNoReturn)
+	}
+
+	return m.cls.applyContext(bindClass(m.cls, on, m.doc.Return, nil)), nil
+}
+
+// VirtualProperties returns the properties declared in the PHPDoc of the class.
+func (c *ClassLike) VirtualProperties() []*VirtualProperty {
+	var props []*VirtualProperty
+	c.virtualMembers(func(doc phpdoxer.Node, node *ast.Identifier) {
+		if prop, ok := doc.(*phpdoxer.NodeProperty); ok {
+			props = append(props, &VirtualProperty{
+				modified: newModified(set.New[string]()),
+				cls:      c,
+				doc:      prop,
+				node:     node,
+			})
+		}
+	})
+
+	return props
+}
+
+// VirtualMethods returns the methods declared in the PHPDoc of the class.
+func (c *ClassLike) VirtualMethods() []*VirtualMethod {
+	var methods []*VirtualMethod
+	c.virtualMembers(func(doc phpdoxer.Node, node *ast.Identifier) {
+		if method, ok := doc.(*phpdoxer.NodeMethod); ok {
+			modifiers := set.New[string]()
+			if method.Static {
+				modifiers.Add("static")
+			}
+
+			methods = append(methods, &VirtualMethod{
+				modified: newModified(modifiers),
+				cls:      c,
+				doc:      method,
+				node:     node,
+			})
+		}
+	})
+
+	return methods
+}
+
+func (c *ClassLike) FindVirtualProperty(
+	filters ...FilterFunc[*VirtualProperty],
+) *VirtualProperty {
+	return findFirst(c.VirtualProperties(), filters)
+}
+
+func (c *ClassLike) FindVirtualMethod(filters ...FilterFunc[*VirtualMethod]) *VirtualMethod {
+	return findFirst(c.VirtualMethods(), filters)
+}
+
+func findFirst[T any](values []T, filters []FilterFunc[T]) T {
+Values:
+	for _, v := range values {
+		for _, filter := range filters {
+			if !filter(v) {
+				continue Values
+			}
+		}
+
+		return v
+	}
+
+	var zero T
+	return zero
+}
+
+// virtualMembers calls cb for each @property and @method in the doc comments
+// of the class, with an identifier positioned at the name of the member.
+func (c *ClassLike) virtualMembers(cb func(doc phpdoxer.Node, node *ast.Identifier)) {
+	for _, tok := range NodeCommentTokens(c.node) {
+		value := string(tok.Value)
+		if !strings.HasPrefix(value, "/**") {
+			continue
+		}
+
+		docs, err := phpdoxer.ParseDoc(value)
+		if err != nil {
+			log.Println(fmt.Errorf("[symbol.ClassLike.virtualMembers]: %w", err))
+			continue
+		}
+
+		for _, doc := range docs {
+			var name, search string
+			switch typedDoc := doc.(type) {
+			case *phpdoxer.NodeProperty:
+				name, search = typedDoc.Name, typedDoc.Name
+			case *phpdoxer.NodeMethod:
+				name, search = typedDoc.Name, typedDoc.Name+"("
+			default:
+				continue
+			}
+
+			cb(doc, virtualIdentifier(tok, doc, name, search))
+		}
+	}
+}
+
+// virtualIdentifier creates an identifier for the name, positioned at the
+// first occurrence of search inside the doc node.
+func virtualIdentifier(tok *token.Token, doc phpdoxer.Node, name string, search string) *ast.Identifier {
+	value := string(tok.Value)
+	start, end := doc.Range()
+
+	offset := start
+	if i := strings.Index(value[start:end], search); i != -1 {
+		offset += i
+	}
+
+	line := tok.Position.StartLine + strings.Count(value[:offset], "\n")
+	return &ast.Identifier{
+		Position: &position.Position{
+			StartLine: line,
+			EndLine:   line,
+			StartPos:  tok.Position.StartPos + offset,
+			EndPos:    tok.Position.StartPos + offset + len(name),
+		},
+		Value: []byte(name),
+	}
+}
+
+// applyContext qualifies the class-likes in the type, which is declared in
+// the doc comment of the class.
+func (c *ClassLike) applyContext(typ phpdoxer.Type) []*phpdoxer.TypeClassLike {
+	fqnt := fqn.NewTraverser()
+	c.Root().Accept(traverser.NewTraverser(fqnt))
+
+	return doxcontext.ApplyContext(fqnt, c.GetFQN(), c.node.GetPosition(), typ)
+}
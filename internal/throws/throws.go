@@ -60,27 +60,45 @@ type Violation struct {
 	// Either a function or class method statement.
 	Node   ast.Vertex
 	Throws []*fqn.FQN
+	// The nodes inside Node that cause the exceptions to be thrown.
+	Sites []*Site
 
 	message string
 }
 
+// Site is a throw statement, or a call, that throws exceptions which are
+// not caught or documented.
+type Site struct {
+	Node   ast.Vertex
+	Throws []*fqn.FQN
+}
+
 func (v *Violation) Message() string {
 	if len(v.message) > 0 {
 		return v.message
 	}
 
-	typeStr := ie.IfElse(v.Node.GetType() == ast.TypeStmtFunction, "function", "method")
-	throwsStr := strings.Join(functional.Map(v.Throws, functional.ToString[*fqn.FQN]), ", ")
-
 	v.message = fmt.Sprintf(
 		"The %s %s throws %s but these exceptions are not caught or added to the PHPDoc.",
-		typeStr,
+		v.kind(),
 		nodeident.Get(v.Node),
-		throwsStr,
+		joinFQNs(v.Throws),
 	)
 	return v.message
 }
 
+// SiteMessage returns the message describing a single throwing site of the violation.
+func (v *Violation) SiteMessage(site *Site) string {
+	what := ie.IfElse(site.Node.GetType() == ast.TypeStmtThrow, "This throw", "This call")
+	return fmt.Sprintf(
+		"%s throws %s which is not caught or added to the PHPDoc of the %s %s.",
+		what,
+		joinFQNs(site.Throws),
+		v.kind(),
+		nodeident.Get(v.Node),
+	)
+}
+
 func (v *Violation) Code() string {
 	return "uncaught"
 }
@@ -89,6 +107,14 @@ func (v *Violation) Line() int {
 	return v.Node.GetPosition().StartLine
 }
 
+func (v *Violation) kind() string {
+	return ie.IfElse(v.Node.GetType() == ast.TypeStmtFunction, "function", "method")
+}
+
+func joinFQNs(fqns []*fqn.FQN) string {
+	return strings.Join(functional.Map(fqns, functional.ToString[*fqn.FQN]), ", ")
+}
+
 // Diagnose finds all the functions or methods in the given file that throw
 // exceptions that are not added to the PHPDoc with an @throws tag or caught in
 // that method.
@@ -114,31 +140,34 @@ func Diagnose(root rooter) (results []*Violation) {
 					node:               throwing,
 					ignoreFirstFuncDoc: true,
 				}
-				throws := r.Throws()
-				if len(throws) == 0 {
+				thrown := r.throws(set.New[string]())
+				if len(thrown) == 0 {
 					return
 				}
 
 				// Remove any returned throw that is documented to be thrown.
 				doxed := r.phpDocThrows()
 				if len(doxed) > 0 {
-					for i := len(throws) - 1; i >= 0; i-- {
-						checker := r.catches(throws[i])
+					for throw := range thrown {
+						checker := r.catches(fqn.New(throw))
 
 						for _, d := range doxed {
 							if checker(d) {
-								throws = slices.Delete(throws, i, i+1)
-								if len(throws) == 0 {
-									return
-								}
+								delete(thrown, throw)
+								break
 							}
 						}
 					}
+
+					if len(thrown) == 0 {
+						return
+					}
 				}
 
 				violations <- &Violation{
 					Node:   throwing,
-					Throws: throws,
+					Throws: thrown.fqns(),
+					Sites:  thrown.sites(),
 				}
 			}(throwing)
 		}
@@ -160,7 +189,65 @@ func Diagnose(root rooter) (results []*Violation) {
 }
 
 func (t *Throws) Throws() []*fqn.FQN {
-	return functional.Map(t.throws(set.New[string]()).Slice(), fqn.New)
+	return t.throws(set.New[string]()).fqns()
+}
+
+// thrown maps the FQN of thrown exceptions to the nodes causing them to be thrown.
+type thrown map[string][]ast.Vertex
+
+func (th thrown) add(exception string, site ast.Vertex) {
+	th[exception] = append(th[exception], site)
+}
+
+// merge adds all exceptions of other to th, if site is given, it is used
+// as the site of the merged exceptions.
+func (th thrown) merge(other thrown, site ast.Vertex) {
+	for exception, sites := range other {
+		if site != nil {
+			th.add(exception, site)
+			continue
+		}
+
+		th[exception] = append(th[exception], sites...)
+	}
+}
+
+func (th thrown) fqns() []*fqn.FQN {
+	res := make([]*fqn.FQN, 0, len(th))
+	for exception := range th {
+		res = append(res, fqn.New(exception))
+	}
+
+	slices.SortFunc(res, func(a, b *fqn.FQN) bool { return a.String() < b.String() })
+	return res
+}
+
+// sites inverts the map, returning each throwing node with what it throws,
+// ordered by position.
+func (th thrown) sites() []*Site {
+	bySite := make(map[ast.Vertex]*Site)
+	var res []*Site
+	for _, exception := range th.fqns() {
+		for _, node := range th[exception.String()] {
+			site, ok := bySite[node]
+			if !ok {
+				site = &Site{Node: node}
+				bySite[node] = site
+				res = append(res, site)
+			}
+
+			if slices.IndexFunc(site.Throws, func(f *fqn.FQN) bool {
+				return f.String() == exception.String()
+			}) == -1 {
+				site.Throws = append(site.Throws, exception)
+			}
+		}
+	}
+
+	slices.SortFunc(res, func(a, b *Site) bool {
+		return a.Node.GetPosition().StartPos < b.Node.GetPosition().StartPos
+	})
+	return res
 }
 
 func (t *Throws) seenHash() string {
@@ -172,12 +259,12 @@ func (t *Throws) seenHash() string {
 // something like a map from a function or method to what it throws.
 // if we then have 2 different calls to a method we already have its throws.
 // might be able to keep the cache and invalidate when the file changes.
-func (t *Throws) throws(seen *set.Set[string]) *set.Set[string] {
-	thrownSet := set.New[string]()
+func (t *Throws) throws(seen *set.Set[string]) thrown {
+	thrownMap := thrown{}
 
 	hash := t.seenHash()
 	if seen.Has(hash) {
-		return thrownSet
+		return thrownMap
 	}
 
 	seen.Add(t.seenHash())
@@ -188,7 +275,7 @@ func (t *Throws) throws(seen *set.Set[string]) *set.Set[string] {
 		switch t.node.(type) {
 		case *ast.StmtFunction, *ast.StmtClassMethod:
 			for _, throw := range t.phpDocThrows() {
-				thrownSet.Add(throw.String())
+				thrownMap.add(throw.String(), t.node)
 			}
 		}
 	}
@@ -200,14 +287,14 @@ func (t *Throws) throws(seen *set.Set[string]) *set.Set[string] {
 	for _, result := range tv.Result {
 		switch typedRes := result.(type) {
 		case *ast.StmtTry:
-			tryThrows := set.New[string]()
+			tryThrows := thrown{}
 
 			blockThrows := &Throws{
 				rooter: t.rooter,
 				doxed:  symbol.NewDoxed(result),
 				node:   result,
 			}
-			tryThrows = tryThrows.Union(blockThrows.throws(seen))
+			tryThrows.merge(blockThrows.throws(seen), nil)
 
 			// Go through each catch, first remove all the things
 			// that are caught by the types.
@@ -222,7 +309,7 @@ func (t *Throws) throws(seen *set.Set[string]) *set.Set[string] {
 				t.Root().Accept(fqntt)
 
 				var toRemove []string
-				for tryThrow := range tryThrows.Iterator() {
+				for tryThrow := range tryThrows {
 					checker := t.catches(fqn.New(tryThrow))
 
 					for j := range catch.Types {
@@ -235,7 +322,7 @@ func (t *Throws) throws(seen *set.Set[string]) *set.Set[string] {
 					}
 				}
 				for _, rm := range toRemove {
-					tryThrows.Remove(rm)
+					delete(tryThrows, rm)
 				}
 
 				catchThrows := &Throws{
@@ -243,7 +330,7 @@ func (t *Throws) throws(seen *set.Set[string]) *set.Set[string] {
 					doxed:  symbol.NewDoxed(catch),
 					node:   catch,
 				}
-				tryThrows = tryThrows.Union(catchThrows.throws(seen))
+				tryThrows.merge(catchThrows.throws(seen), nil)
 			}
 
 			if typedRes.Finally != nil {
@@ -252,10 +339,10 @@ func (t *Throws) throws(seen *set.Set[string]) *set.Set[string] {
 					doxed:  symbol.NewDoxed(typedRes.Finally),
 					node:   typedRes.Finally,
 				}
-				tryThrows = tryThrows.Union(finallyThrows.throws(seen))
+				tryThrows.merge(finallyThrows.throws(seen), nil)
 			}
 
-			thrownSet = thrownSet.Union(tryThrows)
+			thrownMap.merge(tryThrows, nil)
 
 		case *ast.StmtThrow: // *ast.ExprThrow, what is that?
 			resolvedRoot, resolvement, err := t.resolve(typedRes.Expr)
@@ -264,8 +351,10 @@ func (t *Throws) throws(seen *set.Set[string]) *set.Set[string] {
 				continue
 			}
 
-			key := fqner.FullyQualifyName(resolvedRoot, resolvement.Node.(*ast.Name))
-			thrownSet.Add(key.String())
+			// The resolved node is either a name or the class-like statement itself.
+			key := fqner.New(wrkspc.NewRooter(resolvement.Path, resolvedRoot), resolvement.Node).
+				GetFQN()
+			thrownMap.add(key.String(), result)
 
 		case *ast.ExprFunctionCall, *ast.ExprMethodCall, *ast.ExprStaticCall:
 			resolvedRoot, resolvement, err := t.resolve(result)
@@ -279,11 +368,11 @@ func (t *Throws) throws(seen *set.Set[string]) *set.Set[string] {
 				doxed:  symbol.NewDoxed(resolvement.Node),
 				node:   resolvement.Node,
 			}
-			thrownSet = thrownSet.Union(blockThrows.throws(seen))
+			thrownMap.merge(blockThrows.throws(seen), result)
 		}
 	}
 
-	return thrownSet
+	return thrownMap
 }
 
 func (t *Throws) phpDocThrows() (throws []*fqn.FQN) {
@@ -17,6 +17,7 @@ import (
 	"github.com/laytan/phpls/pkg/annotated"
 	"github.com/laytan/phpls/pkg/fqn"
 	"github.com/laytan/phpls/pkg/functional"
+	"github.com/laytan/phpls/pkg/nodeident"
 	"github.com/laytan/phpls/pkg/nodescopes"
 	"github.com/laytan/phpls/pkg/pathutils"
 	"github.com/laytan/phpls/pkg/phpversion"
@@ -158,3 +159,33 @@ func setup(root string, phpv *phpversion.PHPVersion) error {
 
 	return nil
 }
+
+func TestDiagnoseSites(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.Join(pathutils.Root(), "internal", "throws", "testdata")
+
+	err := setup(root, phpversion.EightOne())
+	require.NoError(t, err)
+
+	violations := throws.Diagnose(wrkspc.NewRooter(filepath.Join(root, "throws.php")))
+
+	var violation *throws.Violation
+	for _, v := range violations {
+		if nodeident.Get(v.Node) == "test_throws_2" {
+			violation = v
+		}
+	}
+	require.NotNil(t, violation)
+	require.Len(t, violation.Sites, 2)
+
+	call := violation.Sites[0]
+	require.IsType(t, &ast.ExprFunctionCall{}, call.Node)
+	require.Equal(t, 15, call.Node.GetPosition().StartLine)
+	require.Equal(t, []string{`\Throws\TestData\Exception`}, functional.Map(call.Throws, functional.ToString[*fqn.FQN]))
+
+	throw := violation.Sites[1]
+	require.IsType(t, &ast.StmtThrow{}, throw.Node)
+	require.Equal(t, 17, throw.Node.GetPosition().StartLine)
+	require.Equal(t, []string{`\Throws\TestData\Throwable`}, functional.Map(throw.Throws, functional.ToString[*fqn.FQN]))
+}
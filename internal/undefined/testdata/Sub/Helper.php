@@ -0,0 +1,7 @@
+<?php
+
+namespace Undefined\TestData\Sub;
+
+class Helper
+{
+}
@@ -0,0 +1,92 @@
+<?php
+
+namespace Undefined\TestData;
+
+use Undefined\TestData\Sub\Helper;
+
+const DEFINED_CONST = 1;
+
+function defined_function(): void
+{
+}
+
+interface Repository
+{
+    public const TABLE = 'users';
+
+    public function find(int $id): ?User;
+}
+
+class User
+{
+    public $name;
+
+    public static $count = 0;
+
+    public function greet(): string
+    {
+        return $this->name . $this->nmae;
+    }
+}
+
+class UserRepository implements Repository
+{
+    public function find(int $id): ?User
+    {
+        $user = new User();
+        $user->greet();
+        $user->gret();
+        User::$count++;
+        User::$cuont++;
+
+        return $this->missing($user);
+    }
+
+    public function table(): string
+    {
+        return self::TABLE . self::TABEL . static::class;
+    }
+}
+
+/**
+ * @method void virtual()
+ */
+class Magic
+{
+    public function __call($name, $arguments)
+    {
+    }
+
+    public function test(): void
+    {
+        $this->anything();
+        $this->virtual();
+    }
+}
+
+function test(): UserRepostory
+{
+    $repository = new UserRepostory();
+    $repository = new UserRepository();
+    defined_function();
+    undefined_function();
+    strlen_typo('');
+    echo DEFINED_CONST . UNDEFINED_CONST . true;
+    new Helper();
+    new Sub\Helper();
+
+    return $repository;
+}
+
+/**
+ * @mixin User
+ */
+class Delegating
+{
+    public function test(): void
+    {
+        $this->greet();
+        $this->name;
+        $this->missing();
+    }
+}
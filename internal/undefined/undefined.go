@@ -0,0 +1,550 @@
+package undefined
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/laytan/php-parser/pkg/ast"
+	"github.com/laytan/php-parser/pkg/visitor"
+	"github.com/laytan/php-parser/pkg/visitor/traverser"
+	"github.com/laytan/phpls/internal/expr"
+	"github.com/laytan/phpls/internal/index"
+	"github.com/laytan/phpls/internal/symbol"
+	"github.com/laytan/phpls/internal/wrkspc"
+	"github.com/laytan/phpls/pkg/fqn"
+	"github.com/laytan/phpls/pkg/nodeident"
+	"github.com/laytan/phpls/pkg/nodescopes"
+	"github.com/laytan/phpls/pkg/phpdoxer"
+	"github.com/laytan/phpls/pkg/set"
+)
+
+type rooter interface {
+	Root() *ast.Root
+	Path() string
+}
+
+type Kind int
+
+const (
+	KindClass Kind = iota
+	KindFunction
+	KindConstant
+	KindMethod
+	KindProperty
+	KindClassConstant
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindClass:
+		return "class"
+	case KindFunction:
+		return "function"
+	case KindConstant:
+		return "constant"
+	case KindMethod:
+		return "method"
+	case KindProperty:
+		return "property"
+	case KindClassConstant:
+		return "class constant"
+	default:
+		return "symbol"
+	}
+}
+
+type Violation struct {
+	// The node referring to the undefined symbol.
+	Node ast.Vertex
+	Kind Kind
+	// The fully qualified name of the symbol, or of the class for members.
+	FQN *fqn.FQN
+	// The name of the member, empty if the symbol is not a member.
+	Member string
+}
+
+func (v *Violation) Message() string {
+	switch v.Kind {
+	case KindFunction:
+		return fmt.Sprintf("Call to undefined function %s().", v.FQN)
+	case KindMethod:
+		return fmt.Sprintf("Call to undefined method %s::%s().", v.FQN, v.Member)
+	case KindProperty:
+		return fmt.Sprintf("Access to undefined property %s::$%s.", v.FQN, v.Member)
+	case KindClassConstant:
+		return fmt.Sprintf("Access to undefined class constant %s::%s.", v.FQN, v.Member)
+	default:
+		return fmt.Sprintf("Undefined %s %s.", v.Kind, v.FQN)
+	}
+}
+
+func (v *Violation) Code() string {
+	return "undefined-" + strings.ReplaceAll(v.Kind.String(), " ", "-")
+}
+
+func (v *Violation) Line() int {
+	return v.Node.GetPosition().StartLine
+}
+
+type Options struct {
+	// Don't report undefined members of classes that implement the magic
+	// methods handling them, like __call and __get.
+	SuppressMagic bool
+}
+
+// Diagnose finds all references to classes, functions, constants and members
+// in the given file that are not defined in the index or the file itself.
+//
+// Members are only checked when the class of the expression can be resolved,
+// and the class and all its parents can be found.
+func Diagnose(root rooter, opts Options) []*Violation {
+	fqnt := fqn.NewTraverser()
+	root.Root().Accept(traverser.NewTraverser(fqnt))
+
+	t := &undefinedTraverser{
+		rooter:   root,
+		opts:     opts,
+		fqnt:     fqnt,
+		declared: index.Declarations(root.Path(), root.Root()),
+		classes:  []ast.Vertex{root.Root()},
+		blocks:   []ast.Vertex{root.Root()},
+	}
+	root.Root().Accept(traverser.NewTraverser(t))
+
+	return t.violations
+}
+
+// Type hints that are not classes.
+var builtinTypes = set.NewFromSlice([]string{
+	"array", "bool", "callable", "false", "float", "int", "iterable", "mixed",
+	"never", "null", "object", "parent", "self", "static", "string", "true", "void",
+})
+
+// The magic methods that handle undefined members of each kind.
+var magicMethods = map[Kind][]string{
+	KindMethod:   {"__call", "__callStatic"},
+	KindProperty: {"__get", "__set"},
+}
+
+type undefinedTraverser struct {
+	visitor.Null
+
+	rooter   rooter
+	opts     Options
+	fqnt     *fqn.Traverser
+	declared *set.Set[string]
+
+	classes []ast.Vertex
+	blocks  []ast.Vertex
+
+	violations []*Violation
+}
+
+func (t *undefinedTraverser) EnterNode(node ast.Vertex) bool {
+	if nodescopes.IsScope(node.GetType()) {
+		t.blocks = append(t.blocks, node)
+	}
+
+	if nodescopes.IsClassLike(node.GetType()) {
+		t.classes = append(t.classes, node)
+	}
+
+	switch typedNode := node.(type) {
+	case *ast.ExprNew:
+		t.class(typedNode.Class)
+
+	case *ast.ExprInstanceOf:
+		t.class(typedNode.Class)
+
+	case *ast.StmtCatch:
+		for _, typ := range typedNode.Types {
+			t.class(typ)
+		}
+
+	case *ast.StmtClass:
+		if typedNode.Extends != nil {
+			t.class(typedNode.Extends)
+		}
+
+		for _, impl := range typedNode.Implements {
+			t.class(impl)
+		}
+
+	case *ast.StmtInterface:
+		for _, ext := range typedNode.Extends {
+			t.class(ext)
+		}
+
+	case *ast.StmtEnum:
+		for _, impl := range typedNode.Implements {
+			t.class(impl)
+		}
+
+	case *ast.StmtTraitUse:
+		for _, trait := range typedNode.Traits {
+			t.class(trait)
+		}
+
+	case *ast.Parameter:
+		t.typeHint(typedNode.Type)
+
+	case *ast.StmtPropertyList:
+		t.typeHint(typedNode.Type)
+
+	case *ast.StmtFunction:
+		t.typeHint(typedNode.ReturnType)
+
+	case *ast.StmtClassMethod:
+		t.typeHint(typedNode.ReturnType)
+
+	case *ast.ExprClosure:
+		t.typeHint(typedNode.ReturnType)
+
+	case *ast.ExprArrowFunction:
+		t.typeHint(typedNode.ReturnType)
+
+	case *ast.ExprFunctionCall:
+		t.function(typedNode.Function)
+
+	case *ast.ExprConstFetch:
+		t.constant(typedNode.Const)
+
+	case *ast.ExprMethodCall:
+		t.member(KindMethod, typedNode.Var, typedNode.Method)
+
+	case *ast.ExprPropertyFetch:
+		t.member(KindProperty, typedNode.Var, typedNode.Prop)
+
+	case *ast.ExprStaticCall:
+		if t.class(typedNode.Class) {
+			t.member(KindMethod, typedNode.Class, typedNode.Call)
+		}
+
+	case *ast.ExprStaticPropertyFetch:
+		if t.class(typedNode.Class) {
+			t.member(KindProperty, typedNode.Class, typedNode.Prop)
+		}
+
+	case *ast.ExprClassConstFetch:
+		// Foo::class does not need Foo to exist.
+		if strings.EqualFold(nodeident.Get(typedNode.Const), "class") {
+			break
+		}
+
+		if t.class(typedNode.Class) {
+			t.member(KindClassConstant, typedNode.Class, typedNode.Const)
+		}
+	}
+
+	return true
+}
+
+func (t *undefinedTraverser) LeaveNode(node ast.Vertex) {
+	if nodescopes.IsScope(node.GetType()) {
+		t.blocks = t.blocks[:len(t.blocks)-1]
+	}
+
+	if nodescopes.IsClassLike(node.GetType()) {
+		t.classes = t.classes[:len(t.classes)-1]
+	}
+}
+
+func (t *undefinedTraverser) report(violation *Violation) {
+	t.violations = append(t.violations, violation)
+}
+
+func (t *undefinedTraverser) exists(qualified *fqn.FQN) bool {
+	if t.declared.Has(qualified.String()) {
+		return true
+	}
+
+	_, ok := index.Current.Find(qualified)
+	return ok
+}
+
+// class checks the class referenced by the given name, returning whether it
+// is defined, or can't be checked.
+func (t *undefinedTraverser) class(name ast.Vertex) bool {
+	if !fqn.IsResolvable(name) {
+		return true
+	}
+
+	switch strings.ToLower(nodeident.Get(name)) {
+	case "self", "static", "parent":
+		return true
+	}
+
+	qualified := t.fqnt.ResultFor(name)
+	if qualified == nil || t.exists(qualified) {
+		return true
+	}
+
+	t.report(&Violation{Node: name, Kind: KindClass, FQN: qualified})
+	return false
+}
+
+func (t *undefinedTraverser) typeHint(hint ast.Vertex) {
+	switch typedHint := hint.(type) {
+	case nil:
+		return
+
+	case *ast.Nullable:
+		t.typeHint(typedHint.Expr)
+
+	case *ast.Union:
+		for _, typ := range typedHint.Types {
+			t.typeHint(typ)
+		}
+
+	case *ast.Intersection:
+		for _, typ := range typedHint.Types {
+			t.typeHint(typ)
+		}
+
+	default:
+		if fqn.IsResolvable(hint) && builtinTypes.Has(strings.ToLower(nodeident.Get(hint))) {
+			return
+		}
+
+		t.class(hint)
+	}
+}
+
+func (t *undefinedTraverser) function(name ast.Vertex) {
+	if !fqn.IsResolvable(name) {
+		return
+	}
+
+	if qualified, ok := t.global(name); !ok {
+		t.report(&Violation{Node: name, Kind: KindFunction, FQN: qualified})
+	}
+}
+
+func (t *undefinedTraverser) constant(name ast.Vertex) {
+	if !fqn.IsResolvable(name) {
+		return
+	}
+
+	switch strings.ToLower(nodeident.Get(name)) {
+	case "true", "false", "null":
+		return
+	}
+
+	if qualified, ok := t.global(name); !ok {
+		t.report(&Violation{Node: name, Kind: KindConstant, FQN: qualified})
+	}
+}
+
+// global qualifies the function or constant name, unqualified names fall back
+// to the global namespace like PHP does, the returned FQN is the
+// namespaced one in that case.
+func (t *undefinedTraverser) global(name ast.Vertex) (*fqn.FQN, bool) {
+	qualified := t.fqnt.ResultFor(name)
+	if qualified == nil || t.exists(qualified) {
+		return qualified, true
+	}
+
+	ident := nodeident.Get(name)
+	if !strings.Contains(ident, `\`) && t.exists(fqn.New(`\`+ident)) {
+		return qualified, true
+	}
+
+	return qualified, false
+}
+
+// member checks the member of the class the receiver evaluates to.
+func (t *undefinedTraverser) member(kind Kind, receiver ast.Vertex, nameNode ast.Vertex) {
+	name, ok := memberName(nameNode)
+	if !ok {
+		return
+	}
+
+	cls, ok := t.receiverClass(receiver)
+	if !ok {
+		return
+	}
+
+	// Only classes and interfaces can be checked with certainty, traits depend
+	// on where they are used.
+	iNode, ok := index.Current.Find(cls)
+	if !ok || !iNode.MatchesKind(ast.TypeStmtClass, ast.TypeStmtInterface) {
+		return
+	}
+
+	if kind == KindProperty && cls.String() == `\stdClass` {
+		return
+	}
+
+	var clsRooter rooter = wrkspc.NewRooter(iNode.Path)
+	if iNode.Path == t.rooter.Path() {
+		clsRooter = t.rooter
+	}
+
+	classLike, err := symbol.NewClassLikeFromFQN(clsRooter, cls)
+	if err != nil {
+		return
+	}
+
+	if found, certain := t.hasMember(classLike, kind, name); found || !certain {
+		return
+	}
+
+	t.report(&Violation{Node: nameNode, Kind: kind, FQN: cls, Member: name})
+}
+
+func (t *undefinedTraverser) receiverClass(receiver ast.Vertex) (*fqn.FQN, bool) {
+	if nodescopes.IsName(receiver.GetType()) {
+		ident := strings.ToLower(nodeident.Get(receiver))
+		switch ident {
+		case "parent":
+			cls, ok := t.classes[len(t.classes)-1].(*ast.StmtClass)
+			if !ok || cls.Extends == nil {
+				return nil, false
+			}
+
+			fallthrough
+
+		case "self", "static":
+			// Resolved like $this, see the class constant resolver in expr.
+			receiver = &ast.ExprVariable{
+				Position: receiver.GetPosition(),
+				Name: &ast.Identifier{
+					Position: receiver.GetPosition(),
+					Value:    []byte(ident),
+				},
+			}
+		}
+	}
+
+	_, cls, left := expr.Resolve(receiver, &expr.Scopes{
+		Path:  t.rooter.Path(),
+		Root:  t.rooter.Root(),
+		Class: t.classes[len(t.classes)-1],
+		Block: t.blocks[len(t.blocks)-1],
+	})
+	if left != 0 || cls == nil {
+		return nil, false
+	}
+
+	return cls, true
+}
+
+// hasMember returns whether the class or any of the classes it inherits from
+// defines the member, certain is false if not all classes could be checked.
+func (t *undefinedTraverser) hasMember(
+	cls *symbol.ClassLike,
+	kind Kind,
+	name string,
+) (found bool, certain bool) {
+	if t.definesMember(cls, kind, name) {
+		return true, true
+	}
+
+	iter := cls.InheritsIter()
+	for inhCls, done, err := iter(); !done; inhCls, done, err = iter() {
+		if err != nil {
+			return false, false
+		}
+
+		if t.definesMember(inhCls, kind, name) {
+			return true, true
+		}
+	}
+
+	return false, true
+}
+
+func (t *undefinedTraverser) definesMember(cls *symbol.ClassLike, kind Kind, name string) bool {
+	switch kind {
+	case KindMethod:
+		if cls.FindMethod(filterMethodName[*symbol.Method](name)) != nil {
+			return true
+		}
+
+		// Virtual methods, declared with @method in the PHPDoc of the class.
+		if cls.FindVirtualMethod(filterMethodName[*symbol.VirtualMethod](name)) != nil {
+			return true
+		}
+
+	case KindProperty:
+		if cls.FindProperty(symbol.FilterName[*symbol.Property]("$"+name)) != nil {
+			return true
+		}
+
+		// Virtual properties, declared with @property in the PHPDoc of the class.
+		if cls.FindVirtualProperty(symbol.FilterName[*symbol.VirtualProperty]("$"+name)) != nil {
+			return true
+		}
+
+	case KindClassConstant:
+		return cls.FindConstant(symbol.FilterName[*symbol.ClassConst](name)) != nil
+	}
+
+	if t.opts.SuppressMagic {
+		for _, magic := range magicMethods[kind] {
+			if cls.FindMethod(filterMethodName[*symbol.Method](magic)) != nil {
+				return true
+			}
+		}
+	}
+
+	return mixinsDefine(cls, kind, name)
+}
+
+// mixinsDefine returns whether one of the mixins of the class defines the
+// member, a mixin that can't be resolved could define anything.
+func mixinsDefine(cls *symbol.ClassLike, kind Kind, name string) bool {
+	tags := 0
+	for _, doc := range cls.Docs() {
+		if doc.Kind() == phpdoxer.KindMixin {
+			tags++
+		}
+	}
+
+	mixins := cls.Mixins()
+	if len(mixins) < tags {
+		return true
+	}
+
+	for _, mixin := range mixins {
+		if mixin.FindMemberInherit(filterMember(kind, name)) != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// filterMember matches the methods, case insensitive, or the properties with the name.
+func filterMember(kind Kind, name string) symbol.FilterFunc[symbol.Member] {
+	return func(m symbol.Member) bool {
+		switch m.(type) {
+		case *symbol.Method, *symbol.VirtualMethod:
+			return kind == KindMethod && strings.EqualFold(m.Name(), name)
+		default:
+			return kind == KindProperty && m.Name() == "$"+name
+		}
+	}
+}
+
+// Method names are case insensitive.
+func filterMethodName[T symbol.Named](name string) symbol.FilterFunc[T] {
+	return func(m T) bool {
+		return strings.EqualFold(m.Name(), name)
+	}
+}
+
+func memberName(node ast.Vertex) (string, bool) {
+	switch typedNode := node.(type) {
+	case *ast.Identifier:
+		return string(typedNode.Value), true
+
+	case *ast.ExprVariable:
+		// Static properties, $ is not part of the member name.
+		if ident, ok := typedNode.Name.(*ast.Identifier); ok {
+			return strings.TrimPrefix(string(ident.Value), "$"), true
+		}
+	}
+
+	return "", false
+}
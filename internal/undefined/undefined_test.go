@@ -0,0 +1,83 @@
+package undefined_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/laytan/phpls/internal/config"
+	"github.com/laytan/phpls/internal/index"
+	"github.com/laytan/phpls/internal/project"
+	"github.com/laytan/phpls/internal/undefined"
+	"github.com/laytan/phpls/internal/wrkspc"
+	"github.com/laytan/phpls/pkg/functional"
+	"github.com/laytan/phpls/pkg/pathutils"
+	"github.com/laytan/phpls/pkg/phpversion"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(
+		m,
+		// The cache size logger.
+		goleak.IgnoreTopFunction("github.com/laytan/phpls/internal/wrkspc.New.func1"),
+	)
+}
+
+func TestDiagnose(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.Join(pathutils.Root(), "internal", "undefined", "testdata")
+
+	err := setup(root, phpversion.EightOne())
+	require.NoError(t, err)
+
+	format := func(v *undefined.Violation) string {
+		return fmt.Sprintf("%d %s %s", v.Line(), v.Code(), v.Message())
+	}
+
+	expected := []string{
+		`28 undefined-property Access to undefined property \Undefined\TestData\User::$nmae.`,
+		`38 undefined-method Call to undefined method \Undefined\TestData\User::gret().`,
+		`40 undefined-property Access to undefined property \Undefined\TestData\User::$cuont.`,
+		`42 undefined-method Call to undefined method \Undefined\TestData\UserRepository::missing().`,
+		`47 undefined-class-constant Access to undefined class constant \Undefined\TestData\UserRepository::TABEL.`,
+		`67 undefined-class Undefined class \Undefined\TestData\UserRepostory.`,
+		`69 undefined-class Undefined class \Undefined\TestData\UserRepostory.`,
+		`72 undefined-function Call to undefined function \Undefined\TestData\undefined_function().`,
+		`73 undefined-function Call to undefined function \Undefined\TestData\strlen_typo().`,
+		`74 undefined-constant Undefined constant \Undefined\TestData\UNDEFINED_CONST.`,
+		`90 undefined-method Call to undefined method \Undefined\TestData\Delegating::missing().`,
+	}
+
+	path := filepath.Join(root, "undefined.php")
+
+	violations := undefined.Diagnose(wrkspc.NewRooter(path), undefined.Options{SuppressMagic: true})
+	require.Equal(t, expected, functional.Map(violations, format))
+
+	violations = undefined.Diagnose(wrkspc.NewRooter(path), undefined.Options{SuppressMagic: false})
+	require.Contains(
+		t,
+		functional.Map(violations, format),
+		`62 undefined-method Call to undefined method \Undefined\TestData\Magic::anything().`,
+	)
+	require.Len(t, violations, len(expected)+1)
+}
+
+func setup(root string, phpv *phpversion.PHPVersion) error {
+	config.Current = config.Default()
+	index.Current = index.New(phpv)
+	wrkspc.Current = wrkspc.New(
+		phpv,
+		root,
+		filepath.Join(pathutils.Root(), "third_party", "phpstorm-stubs"),
+	)
+
+	p := project.New()
+	if err := p.ParseWithoutProgress(); err != nil {
+		return fmt.Errorf("[undefined_test.setup]: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,34 @@
+<?php
+
+namespace Unused\TestData\DocImports;
+
+use Unused\TestData\Bound;
+use Unused\TestData\Collection;
+use Unused\TestData\Element;
+use Unused\TestData\HasElements;
+use Unused\TestData\Repository;
+use Unused\TestData\NotUsed;
+use Unused\TestData\Prop;
+use Unused\TestData\ReadOnlyProp;
+use Unused\TestData\Returned;
+use Unused\TestData\Param;
+use Unused\TestData\Mixer;
+use Unused\TestData\Seen;
+use Unused\TestData\Aliased;
+
+/**
+ * @template T of Bound
+ * @extends Collection<Element>
+ * @implements Repository<Element>
+ * @property Prop $prop
+ * @property-read ReadOnlyProp $readOnly
+ * @method Returned make(int $count, Param $param)
+ * @mixin Mixer
+ * @see Seen::make()
+ * @psalm-type Alias = array<int, Aliased>
+ */
+class Elements
+{
+    /** @use HasElements<Element> */
+    use \Unused\TestData\Elements;
+}
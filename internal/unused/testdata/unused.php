@@ -0,0 +1,116 @@
+<?php
+
+namespace Unused\TestData;
+
+use Countable;
+use Stringable;
+use Traversable;
+use function array_map;
+use function strlen;
+
+/**
+ * @param Traversable $items
+ */
+function count_items($items, $unusedButPublic): int
+{
+    $count = 0;
+    $unused = 1;
+    $unused = 2;
+    [$first, $second] = [1, 2];
+
+    foreach ($items as $item) {
+        $count += strlen($item);
+    }
+
+    $callback = function ($a) use ($count) {
+        $inner = $a;
+        return $count;
+    };
+
+    return $callback($first);
+}
+
+function dynamic()
+{
+    $a = 1;
+
+    return compact('a');
+}
+
+final class Service
+{
+    private const USED = 1;
+
+    private const UNUSED = 2;
+
+    private int $used = 0;
+
+    private int $unused = 0;
+
+    private static int $counter = 0;
+
+    public function __construct(
+        private string $promoted,
+        private string $unusedPromoted,
+    ) {
+    }
+
+    public function run(string $input, string $ignored): int
+    {
+        self::$counter++;
+
+        return $this->used + self::USED + $this->helper($input) + strlen($this->promoted);
+    }
+
+    private function helper(string $input, int $extra = 0): int
+    {
+        return strlen($input);
+    }
+
+    private function unusedHelper(): void
+    {
+    }
+
+    public function callback(): array
+    {
+        return array_map([$this, 'mapper'], []);
+    }
+
+    private function mapper($value)
+    {
+        return $value;
+    }
+}
+
+class Open
+{
+    public function run(string $input): void
+    {
+    }
+
+    final public function locked(string $input): void
+    {
+    }
+
+    private function byRef(&$out): void
+    {
+        $out = 1;
+    }
+}
+
+class Child extends Open
+{
+    final public function run(string $input): void
+    {
+    }
+}
+
+class Dynamic
+{
+    private int $value = 0;
+
+    public function get(string $name)
+    {
+        return $this->$name;
+    }
+}
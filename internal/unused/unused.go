@@ -0,0 +1,583 @@
+package unused
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/laytan/php-parser/pkg/ast"
+	"github.com/laytan/php-parser/pkg/visitor"
+	"github.com/laytan/php-parser/pkg/visitor/traverser"
+	"github.com/laytan/phpls/internal/symbol"
+	"github.com/laytan/phpls/pkg/imports"
+	"github.com/laytan/phpls/pkg/nodeident"
+	"github.com/laytan/phpls/pkg/nodescopes"
+	"github.com/laytan/phpls/pkg/phprivacy"
+	"github.com/laytan/phpls/pkg/set"
+)
+
+// Functions that access the variables of the scope by name, variables can't
+// be reported as unused when these are called.
+var dynamicScopeFunctions = set.NewFromSlice([]string{
+	"compact",
+	"extract",
+	"get_defined_vars",
+	"func_get_args",
+	"func_get_arg",
+})
+
+var superGlobals = set.NewFromSlice([]string{
+	"$GLOBALS",
+	"$_SERVER",
+	"$_GET",
+	"$_POST",
+	"$_FILES",
+	"$_COOKIE",
+	"$_SESSION",
+	"$_REQUEST",
+	"$_ENV",
+})
+
+type rooter interface {
+	Root() *ast.Root
+	Path() string
+}
+
+type Kind int
+
+const (
+	KindVariable Kind = iota
+	KindParameter
+	KindImport
+	KindMethod
+	KindProperty
+	KindConstant
+)
+
+type Violation struct {
+	// The node of the unused variable, parameter, import or member name.
+	Node ast.Vertex
+	Kind Kind
+	// The name of the symbol, variables and parameters include the $.
+	Name string
+}
+
+func (v *Violation) Message() string {
+	switch v.Kind {
+	case KindVariable:
+		return fmt.Sprintf("Variable %s is assigned but never used.", v.Name)
+	case KindParameter:
+		return fmt.Sprintf("Parameter %s is never used.", v.Name)
+	case KindImport:
+		return fmt.Sprintf("Import %s is never used.", v.Name)
+	case KindMethod:
+		return fmt.Sprintf("Private method %s() is never used.", v.Name)
+	case KindProperty:
+		return fmt.Sprintf("Private property $%s is never used.", v.Name)
+	case KindConstant:
+		return fmt.Sprintf("Private constant %s is never used.", v.Name)
+	default:
+		return fmt.Sprintf("%s is never used.", v.Name)
+	}
+}
+
+func (v *Violation) Code() string {
+	switch v.Kind {
+	case KindVariable:
+		return "unused-variable"
+	case KindParameter:
+		return "unused-parameter"
+	case KindImport:
+		return "unused-import"
+	default:
+		return "unused-private-member"
+	}
+}
+
+func (v *Violation) Line() int {
+	return v.Node.GetPosition().StartLine
+}
+
+// Diagnose finds local variables that are assigned but never read, unused
+// parameters of methods that can't be overridden, unused imports and private
+// class members that are never referenced.
+//
+// The content is the source of the root, used to check the PHPDoc for
+// usages of imports.
+func Diagnose(root rooter, content string) []*Violation {
+	t := &unusedTraverser{
+		rooter: root,
+		writes: set.New[ast.Vertex](),
+		skip:   set.New[ast.Vertex](),
+	}
+	root.Root().Accept(traverser.NewTraverser(t))
+
+	violations := append(t.violations, unusedImports(root.Root(), content)...)
+	sort.SliceStable(violations, func(i, j int) bool {
+		return violations[i].Node.GetPosition().StartPos < violations[j].Node.GetPosition().StartPos
+	})
+
+	return violations
+}
+
+func unusedImports(root *ast.Root, content string) (violations []*Violation) {
+	for _, scope := range imports.Scopes(root, len(content)) {
+		var usage *imports.Usage
+		for _, stmt := range scope.Stmts {
+			imps, ok := imports.Parse(stmt)
+			if !ok {
+				continue
+			}
+
+			if usage == nil {
+				usage = scope.Usage(content)
+			}
+
+			for _, imp := range imps {
+				if !usage.Uses(imp) {
+					violations = append(violations, &Violation{
+						Node: imp.Node,
+						Kind: KindImport,
+						Name: imp.Name,
+					})
+				}
+			}
+		}
+	}
+
+	return violations
+}
+
+// function is a scope with its own variables, a function, method or closure.
+type function struct {
+	// Parameters that are reported when they are never read.
+	params []*ast.Parameter
+	// Parameters and closure uses, these are not reported as unused variables.
+	declared *set.Set[string]
+
+	reads  *set.Set[string]
+	writes []*ast.ExprVariable
+
+	// Variables are accessed by name, nothing can be reported.
+	dynamic bool
+}
+
+// class is a class-like with the private members it declares and the member
+// names referenced in it.
+type class struct {
+	node    ast.Vertex
+	members []*Violation
+
+	methods    *set.Set[string]
+	properties *set.Set[string]
+	constants  *set.Set[string]
+
+	// Members are accessed by a dynamic name, or from a used trait.
+	dynamic bool
+}
+
+type unusedTraverser struct {
+	visitor.Null
+
+	rooter rooter
+
+	// Variables that are assigned to.
+	writes *set.Set[ast.Vertex]
+	// Variables that are not reads of a local variable, parameter declarations
+	// and static property names.
+	skip *set.Set[ast.Vertex]
+
+	functions []*function
+	classes   []*class
+
+	violations []*Violation
+}
+
+func (t *unusedTraverser) EnterNode(node ast.Vertex) bool {
+	if nodescopes.IsClassLike(node.GetType()) {
+		t.classes = append(t.classes, newClass(node))
+	}
+
+	switch typedNode := node.(type) {
+	case *ast.Parameter:
+		t.skip.Add(typedNode.Var)
+
+	case *ast.StmtFunction:
+		t.functions = append(t.functions, newFunction(typedNode.Params))
+
+	case *ast.StmtClassMethod:
+		fn := newFunction(typedNode.Params)
+		if t.reportsParams(typedNode) {
+			for _, param := range typedNode.Params {
+				param := param.(*ast.Parameter)
+				if len(param.Modifiers) == 0 && param.AmpersandTkn == nil {
+					fn.params = append(fn.params, param)
+				}
+			}
+		}
+
+		t.functions = append(t.functions, fn)
+
+	case *ast.ExprClosure:
+		// The uses are read in the surrounding scope, and declared in the closure.
+		fn := newFunction(typedNode.Params)
+		for _, use := range typedNode.Uses {
+			name := nodeident.Get(use.(*ast.ExprClosureUse).Var)
+			t.read(name)
+			fn.declared.Add(name)
+		}
+
+		t.functions = append(t.functions, fn)
+
+	case *ast.StmtTraitUse:
+		t.dynamicClass()
+
+	case *ast.ExprAssign:
+		t.write(typedNode.Var)
+
+	case *ast.ExprVariable:
+		t.variable(typedNode)
+
+	case *ast.ExprFunctionCall:
+		if nodescopes.IsName(typedNode.Function.GetType()) &&
+			dynamicScopeFunctions.Has(strings.ToLower(nodeident.Get(typedNode.Function))) {
+			t.dynamicFunction()
+		}
+
+	case *ast.ExprEval, *ast.ExprInclude, *ast.ExprIncludeOnce, *ast.ExprRequire, *ast.ExprRequireOnce:
+		t.dynamicFunction()
+
+	case *ast.ExprPropertyFetch:
+		t.reference(typedNode.Prop, KindProperty)
+
+	case *ast.ExprNullsafePropertyFetch:
+		t.reference(typedNode.Prop, KindProperty)
+
+	case *ast.ExprStaticPropertyFetch:
+		// The property of self::$prop is a variable, self::$$prop is dynamic.
+		if variable, ok := typedNode.Prop.(*ast.ExprVariable); ok {
+			t.skip.Add(variable)
+			t.reference(variable.Name, KindProperty)
+		}
+
+	case *ast.ExprMethodCall:
+		t.reference(typedNode.Method, KindMethod)
+
+	case *ast.ExprNullsafeMethodCall:
+		t.reference(typedNode.Method, KindMethod)
+
+	case *ast.ExprStaticCall:
+		t.reference(typedNode.Call, KindMethod)
+
+	case *ast.ExprClassConstFetch:
+		t.reference(typedNode.Const, KindConstant)
+
+	case *ast.ScalarString:
+		// Callables like [$this, 'method'] and 'self::method'.
+		name := strings.Trim(nodeident.Get(typedNode), `'"`)
+		if _, method, ok := strings.Cut(name, "::"); ok {
+			name = method
+		}
+
+		for _, c := range t.classes {
+			c.methods.Add(strings.ToLower(name))
+			c.properties.Add(name)
+		}
+	}
+
+	return true
+}
+
+func (t *unusedTraverser) LeaveNode(node ast.Vertex) {
+	switch node.(type) {
+	case *ast.StmtFunction, *ast.StmtClassMethod, *ast.ExprClosure:
+		t.checkFunction(t.functions[len(t.functions)-1])
+		t.functions = t.functions[:len(t.functions)-1]
+	}
+
+	if nodescopes.IsClassLike(node.GetType()) {
+		t.checkClass(t.classes[len(t.classes)-1])
+		t.classes = t.classes[:len(t.classes)-1]
+	}
+}
+
+func newFunction(params []ast.Vertex) *function {
+	fn := &function{
+		declared: set.New[string](),
+		reads:    set.New[string](),
+	}
+
+	for _, param := range params {
+		fn.declared.Add(nodeident.Get(param.(*ast.Parameter).Var))
+	}
+
+	return fn
+}
+
+// newClass collects the private members of the class, members of traits and
+// interfaces are never reported.
+func newClass(node ast.Vertex) *class {
+	c := &class{
+		node:       node,
+		methods:    set.New[string](),
+		properties: set.New[string](),
+		constants:  set.New[string](),
+	}
+
+	var stmts []ast.Vertex
+	switch typedNode := node.(type) {
+	case *ast.StmtClass:
+		stmts = typedNode.Stmts
+	case *ast.StmtEnum:
+		stmts = typedNode.Stmts
+	default:
+		return c
+	}
+
+	for _, stmt := range stmts {
+		switch typedStmt := stmt.(type) {
+		case *ast.StmtClassMethod:
+			name := nodeident.Get(typedStmt.Name)
+			if strings.EqualFold(name, "__construct") {
+				c.promotedProperties(typedStmt)
+			}
+
+			if nodeident.HasModifier(typedStmt.Modifiers, "private") && !strings.HasPrefix(name, "__") {
+				c.members = append(c.members, &Violation{Node: typedStmt.Name, Kind: KindMethod, Name: name})
+			}
+
+		case *ast.StmtPropertyList:
+			if !nodeident.HasModifier(typedStmt.Modifiers, "private") {
+				continue
+			}
+
+			for _, prop := range typedStmt.Props {
+				prop := prop.(*ast.StmtProperty)
+				c.members = append(c.members, &Violation{
+					Node: prop.Var,
+					Kind: KindProperty,
+					Name: strings.TrimPrefix(nodeident.Get(prop.Var), "$"),
+				})
+			}
+
+		case *ast.StmtClassConstList:
+			if !nodeident.HasModifier(typedStmt.Modifiers, "private") {
+				continue
+			}
+
+			for _, constant := range typedStmt.Consts {
+				constant := constant.(*ast.StmtConstant)
+				c.members = append(c.members, &Violation{
+					Node: constant.Name,
+					Kind: KindConstant,
+					Name: nodeident.Get(constant.Name),
+				})
+			}
+		}
+	}
+
+	return c
+}
+
+func (c *class) promotedProperties(constructor *ast.StmtClassMethod) {
+	for _, param := range constructor.Params {
+		param := param.(*ast.Parameter)
+		if nodeident.HasModifier(param.Modifiers, "private") {
+			c.members = append(c.members, &Violation{
+				Node: param.Var,
+				Kind: KindProperty,
+				Name: strings.TrimPrefix(nodeident.Get(param.Var), "$"),
+			})
+		}
+	}
+}
+
+// reportsParams returns whether the unused parameters of the method can be
+// reported, this is the case when the method has a body and its signature
+// can't be dictated by a parent, an interface or its children.
+func (t *unusedTraverser) reportsParams(node *ast.StmtClassMethod) bool {
+	if _, ok := node.Stmt.(*ast.StmtStmtList); !ok {
+		return false
+	}
+
+	name := nodeident.Get(node.Name)
+	if strings.HasPrefix(name, "__") || len(t.classes) == 0 {
+		return false
+	}
+
+	method := symbol.NewMethod(t.rooter, node)
+
+	// A private method is not inherited and does not implement anything.
+	if method.Privacy() == phprivacy.PrivacyPrivate {
+		return true
+	}
+
+	cls, ok := t.classes[len(t.classes)-1].node.(*ast.StmtClass)
+	if !ok || cls.Name == nil {
+		return false
+	}
+
+	if !method.IsFinal() && !nodeident.HasModifier(cls.Modifiers, "final") {
+		return false
+	}
+
+	return !overrides(symbol.NewClassLike(t.rooter, cls), name)
+}
+
+// overrides returns whether the class inherits a method with the name.
+func overrides(cls *symbol.ClassLike, name string) bool {
+	iter := cls.InheritsIter()
+	for inherited, done, err := iter(); !done; inherited, done, err = iter() {
+		if err != nil {
+			continue
+		}
+
+		if inherited.FindMethod(func(m *symbol.Method) bool {
+			return strings.EqualFold(m.Name(), name)
+		}) != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (t *unusedTraverser) write(node ast.Vertex) {
+	switch typedNode := node.(type) {
+	case *ast.ExprVariable:
+		t.writes.Add(typedNode)
+
+	case *ast.ExprList:
+		for _, item := range typedNode.Items {
+			if item, ok := item.(*ast.ExprArrayItem); ok && item.AmpersandTkn == nil {
+				t.write(item.Val)
+			}
+		}
+	}
+}
+
+func (t *unusedTraverser) variable(node *ast.ExprVariable) {
+	if len(t.functions) == 0 {
+		return
+	}
+
+	if _, ok := node.Name.(*ast.Identifier); !ok {
+		t.dynamicFunction()
+		return
+	}
+
+	if t.skip.Has(node) {
+		return
+	}
+
+	if t.writes.Has(node) {
+		fn := t.functions[len(t.functions)-1]
+		fn.writes = append(fn.writes, node)
+		return
+	}
+
+	t.read(nodeident.Get(node))
+}
+
+func (t *unusedTraverser) read(name string) {
+	if len(t.functions) == 0 {
+		return
+	}
+
+	t.functions[len(t.functions)-1].reads.Add(name)
+}
+
+func (t *unusedTraverser) dynamicFunction() {
+	if len(t.functions) == 0 {
+		return
+	}
+
+	t.functions[len(t.functions)-1].dynamic = true
+}
+
+func (t *unusedTraverser) dynamicClass() {
+	if len(t.classes) == 0 {
+		return
+	}
+
+	t.classes[len(t.classes)-1].dynamic = true
+}
+
+// reference adds the member name to the references of all classes the node
+// is in, a member of another instance of the same class can be accessed too.
+func (t *unusedTraverser) reference(name ast.Vertex, kind Kind) {
+	if _, ok := name.(*ast.Identifier); !ok {
+		for _, c := range t.classes {
+			c.dynamic = true
+		}
+
+		return
+	}
+
+	ident := nodeident.Get(name)
+	for _, c := range t.classes {
+		switch kind {
+		case KindMethod:
+			c.methods.Add(strings.ToLower(ident))
+		case KindProperty:
+			c.properties.Add(strings.TrimPrefix(ident, "$"))
+		case KindConstant:
+			c.constants.Add(ident)
+		}
+	}
+}
+
+func (t *unusedTraverser) checkFunction(fn *function) {
+	if fn.dynamic {
+		return
+	}
+
+	reported := set.New[string]()
+	for _, variable := range fn.writes {
+		name := nodeident.Get(variable)
+		if name == "$this" || superGlobals.Has(name) || fn.declared.Has(name) ||
+			fn.reads.Has(name) || reported.Has(name) {
+			continue
+		}
+
+		reported.Add(name)
+		t.violations = append(t.violations, &Violation{
+			Node: variable,
+			Kind: KindVariable,
+			Name: name,
+		})
+	}
+
+	for _, param := range fn.params {
+		name := nodeident.Get(param.Var)
+		if !fn.reads.Has(name) {
+			t.violations = append(t.violations, &Violation{
+				Node: param.Var,
+				Kind: KindParameter,
+				Name: name,
+			})
+		}
+	}
+}
+
+func (t *unusedTraverser) checkClass(c *class) {
+	if c.dynamic {
+		return
+	}
+
+	for _, member := range c.members {
+		var used bool
+		switch member.Kind {
+		case KindMethod:
+			used = c.methods.Has(strings.ToLower(member.Name))
+		case KindProperty:
+			used = c.properties.Has(member.Name)
+		case KindConstant:
+			used = c.constants.Has(member.Name)
+		}
+
+		if !used {
+			t.violations = append(t.violations, member)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package unused_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/laytan/phpls/internal/config"
+	"github.com/laytan/phpls/internal/index"
+	"github.com/laytan/phpls/internal/project"
+	"github.com/laytan/phpls/internal/unused"
+	"github.com/laytan/phpls/internal/wrkspc"
+	"github.com/laytan/phpls/pkg/functional"
+	"github.com/laytan/phpls/pkg/pathutils"
+	"github.com/laytan/phpls/pkg/phpversion"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(
+		m,
+		// The cache size logger.
+		goleak.IgnoreTopFunction("github.com/laytan/phpls/internal/wrkspc.New.func1"),
+	)
+}
+
+func TestDiagnose(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.Join(pathutils.Root(), "internal", "unused", "testdata")
+
+	err := setup(root, phpversion.EightOne())
+	require.NoError(t, err)
+
+	format := func(v *unused.Violation) string {
+		return fmt.Sprintf("%d %s %s", v.Line(), v.Code(), v.Message())
+	}
+
+	expected := []string{
+		`5 unused-import Import Countable is never used.`,
+		`6 unused-import Import Stringable is never used.`,
+		`17 unused-variable Variable $unused is assigned but never used.`,
+		`19 unused-variable Variable $second is assigned but never used.`,
+		`26 unused-variable Variable $inner is assigned but never used.`,
+		`44 unused-private-member Private constant UNUSED is never used.`,
+		`48 unused-private-member Private property $unused is never used.`,
+		`54 unused-private-member Private property $unusedPromoted is never used.`,
+		`58 unused-parameter Parameter $ignored is never used.`,
+		`65 unused-parameter Parameter $extra is never used.`,
+		`70 unused-private-member Private method unusedHelper() is never used.`,
+		`91 unused-parameter Parameter $input is never used.`,
+		`95 unused-private-member Private method byRef() is never used.`,
+	}
+
+	path := filepath.Join(root, "unused.php")
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	violations := unused.Diagnose(wrkspc.NewRooter(path), string(content))
+	require.Equal(t, expected, functional.Map(violations, format))
+}
+
+func TestDiagnoseDocImports(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.Join(pathutils.Root(), "internal", "unused", "testdata")
+
+	err := setup(root, phpversion.EightOne())
+	require.NoError(t, err)
+
+	path := filepath.Join(root, "docimports.php")
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	violations := unused.Diagnose(wrkspc.NewRooter(path), string(content))
+	require.Equal(
+		t,
+		[]string{`Import Unused\TestData\NotUsed is never used.`},
+		functional.Map(violations, (*unused.Violation).Message),
+	)
+}
+
+func setup(root string, phpv *phpversion.PHPVersion) error {
+	config.Current = config.Default()
+	index.Current = index.New(phpv)
+	wrkspc.Current = wrkspc.New(
+		phpv,
+		root,
+		filepath.Join(pathutils.Root(), "third_party", "phpstorm-stubs"),
+	)
+
+	p := project.New()
+	if err := p.ParseWithoutProgress(); err != nil {
+		return fmt.Errorf("[unused_test.setup]: %w", err)
+	}
+
+	return nil
+}
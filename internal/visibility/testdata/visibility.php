@@ -0,0 +1,105 @@
+<?php
+
+namespace Visibility\TestData;
+
+trait Greets
+{
+    private function greet(): string
+    {
+        return 'hello';
+    }
+}
+
+class Base
+{
+    use Greets;
+
+    public const PUBLIC = 1;
+
+    protected const PROTECTED = 2;
+
+    private const PRIVATE = 3;
+
+    public int $public = 0;
+
+    protected int $protected = 0;
+
+    private int $private = 0;
+
+    public static int $count = 0;
+
+    public function run(): void
+    {
+        $this->secret();
+        $this->greet();
+        $this->private = self::PRIVATE;
+
+        $child = new Child();
+        $child->secret();
+        $child->shared();
+    }
+
+    public static function create(): static
+    {
+        return new static();
+    }
+
+    public function instance(): void
+    {
+    }
+
+    protected function shared(): void
+    {
+    }
+
+    private function secret(): void
+    {
+    }
+}
+
+class Child extends Base
+{
+    public function run(): void
+    {
+        parent::run();
+        $this->shared();
+        $this->secret();
+        $this->protected = static::PROTECTED;
+        $this->private = self::PRIVATE;
+        Base::instance();
+    }
+
+    public static function make(): void
+    {
+        self::instance();
+    }
+}
+
+class Magic
+{
+    private function hidden(): void
+    {
+    }
+
+    public function __call(string $name, array $arguments)
+    {
+    }
+}
+
+function outside(Base $base, Magic $magic): void
+{
+    $base->run();
+    $base->shared();
+    $base->secret();
+    $base->greet();
+    echo $base->public;
+    echo $base->protected;
+    echo Base::PUBLIC;
+    echo Base::PROTECTED;
+    echo $base->count;
+    echo Base::$public;
+    Base::create();
+    Base::instance();
+    $base::create();
+    $magic->hidden();
+}
@@ -0,0 +1,427 @@
+package visibility
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/laytan/php-parser/pkg/ast"
+	"github.com/laytan/php-parser/pkg/visitor"
+	"github.com/laytan/php-parser/pkg/visitor/traverser"
+	"github.com/laytan/phpls/internal/expr"
+	"github.com/laytan/phpls/internal/index"
+	"github.com/laytan/phpls/internal/symbol"
+	"github.com/laytan/phpls/internal/wrkspc"
+	"github.com/laytan/phpls/pkg/fqn"
+	"github.com/laytan/phpls/pkg/nodeident"
+	"github.com/laytan/phpls/pkg/nodescopes"
+	"github.com/laytan/phpls/pkg/phprivacy"
+)
+
+type rooter interface {
+	Root() *ast.Root
+	Path() string
+}
+
+type Kind int
+
+const (
+	KindMethod Kind = iota
+	KindProperty
+	KindClassConstant
+)
+
+// The magic methods that are called when an inaccessible member is accessed.
+var magicMethods = map[Kind][]string{
+	KindMethod:   {"__call", "__callStatic"},
+	KindProperty: {"__get", "__set", "__isset", "__unset"},
+}
+
+type Violation struct {
+	// The node of the member name.
+	Node ast.Vertex
+	Kind Kind
+	// The fully qualified name of the class declaring the member.
+	FQN    *fqn.FQN
+	Member string
+	// The privacy of the member.
+	Privacy phprivacy.Privacy
+	// Set when a static member is accessed as an instance member, or the
+	// other way around, instead of the member not being visible.
+	StaticMismatch bool
+	Static         bool
+}
+
+func (v *Violation) Message() string {
+	if v.StaticMismatch {
+		switch {
+		case v.Kind == KindMethod:
+			return fmt.Sprintf("Non-static method %s::%s() cannot be called statically.", v.FQN, v.Member)
+		case v.Static:
+			return fmt.Sprintf("Static property %s::$%s accessed as non-static.", v.FQN, v.Member)
+		default:
+			return fmt.Sprintf("Non-static property %s::$%s cannot be accessed statically.", v.FQN, v.Member)
+		}
+	}
+
+	switch v.Kind {
+	case KindMethod:
+		return fmt.Sprintf("Call to %s method %s::%s().", v.Privacy, v.FQN, v.Member)
+	case KindProperty:
+		return fmt.Sprintf("Access to %s property %s::$%s.", v.Privacy, v.FQN, v.Member)
+	default:
+		return fmt.Sprintf("Access to %s constant %s::%s.", v.Privacy, v.FQN, v.Member)
+	}
+}
+
+func (v *Violation) Code() string {
+	if v.StaticMismatch {
+		return "static-mismatch"
+	}
+
+	return "visibility"
+}
+
+func (v *Violation) Line() int {
+	return v.Node.GetPosition().StartLine
+}
+
+// Diagnose finds accesses of methods, properties and class constants that are
+// not visible from the scope they are accessed in, and static members that are
+// accessed as instance members or the other way around.
+//
+// Members are only checked when the class of the expression can be resolved.
+func Diagnose(root rooter) []*Violation {
+	t := &visibilityTraverser{
+		rooter:  root,
+		classes: []ast.Vertex{root.Root()},
+		blocks:  []ast.Vertex{root.Root()},
+	}
+	root.Root().Accept(traverser.NewTraverser(t))
+
+	return t.violations
+}
+
+type visibilityTraverser struct {
+	visitor.Null
+
+	rooter rooter
+
+	classes []ast.Vertex
+	blocks  []ast.Vertex
+	methods []*ast.StmtClassMethod
+
+	violations []*Violation
+}
+
+func (t *visibilityTraverser) EnterNode(node ast.Vertex) bool {
+	if nodescopes.IsScope(node.GetType()) {
+		t.blocks = append(t.blocks, node)
+	}
+
+	if nodescopes.IsClassLike(node.GetType()) {
+		t.classes = append(t.classes, node)
+	}
+
+	switch typedNode := node.(type) {
+	case *ast.StmtClassMethod:
+		t.methods = append(t.methods, typedNode)
+
+	case *ast.ExprMethodCall:
+		t.member(KindMethod, false, typedNode.Var, typedNode.Method)
+
+	case *ast.ExprNullsafeMethodCall:
+		t.member(KindMethod, false, typedNode.Var, typedNode.Method)
+
+	case *ast.ExprPropertyFetch:
+		t.member(KindProperty, false, typedNode.Var, typedNode.Prop)
+
+	case *ast.ExprNullsafePropertyFetch:
+		t.member(KindProperty, false, typedNode.Var, typedNode.Prop)
+
+	case *ast.ExprStaticCall:
+		t.member(KindMethod, true, typedNode.Class, typedNode.Call)
+
+	case *ast.ExprStaticPropertyFetch:
+		t.member(KindProperty, true, typedNode.Class, typedNode.Prop)
+
+	case *ast.ExprClassConstFetch:
+		if !strings.EqualFold(nodeident.Get(typedNode.Const), "class") {
+			t.member(KindClassConstant, true, typedNode.Class, typedNode.Const)
+		}
+	}
+
+	return true
+}
+
+func (t *visibilityTraverser) LeaveNode(node ast.Vertex) {
+	if nodescopes.IsScope(node.GetType()) {
+		t.blocks = t.blocks[:len(t.blocks)-1]
+	}
+
+	if nodescopes.IsClassLike(node.GetType()) {
+		t.classes = t.classes[:len(t.classes)-1]
+	}
+
+	if _, ok := node.(*ast.StmtClassMethod); ok {
+		t.methods = t.methods[:len(t.methods)-1]
+	}
+}
+
+func (t *visibilityTraverser) scopes() *expr.Scopes {
+	return &expr.Scopes{
+		Path:  t.rooter.Path(),
+		Root:  t.rooter.Root(),
+		Class: t.classes[len(t.classes)-1],
+		Block: t.blocks[len(t.blocks)-1],
+	}
+}
+
+// member checks the member of the class the receiver evaluates to.
+func (t *visibilityTraverser) member(kind Kind, static bool, receiver ast.Vertex, nameNode ast.Vertex) {
+	// The access of trait members depends on the class using the trait.
+	if t.classes[len(t.classes)-1].GetType() == ast.TypeStmtTrait {
+		return
+	}
+
+	name, ok := memberName(nameNode)
+	if !ok {
+		return
+	}
+
+	cls, ok := t.receiverClass(receiver)
+	if !ok {
+		return
+	}
+
+	iNode, ok := index.Current.Find(cls)
+	if !ok || !iNode.MatchesKind(ast.TypeStmtClass, ast.TypeStmtInterface) {
+		return
+	}
+
+	var clsRooter rooter = wrkspc.NewRooter(iNode.Path)
+	if iNode.Path == t.rooter.Path() {
+		clsRooter = t.rooter
+	}
+
+	classLike, err := symbol.NewClassLikeFromFQN(clsRooter, cls)
+	if err != nil {
+		return
+	}
+
+	scopes := t.scopes()
+	privacy, err := expr.DeterminePrivacy(scopes, cls)
+	if err != nil {
+		return
+	}
+
+	declaring, mod, accessible, ok := findMember(classLike, kind, name, privacy)
+	if !ok {
+		return
+	}
+
+	violation := &Violation{
+		Node:    nameNode,
+		Kind:    kind,
+		FQN:     declaring.GetFQN(),
+		Member:  name,
+		Privacy: mod.Privacy(),
+		Static:  mod.IsStatic(),
+	}
+
+	if kind != KindClassConstant && t.staticMismatch(kind, static, receiver, mod, scopes, declaring) {
+		violation.StaticMismatch = true
+		t.violations = append(t.violations, violation)
+		return
+	}
+
+	if accessible || t.accessibleFromDeclaring(scopes, declaring, mod) || hasMagic(classLike, kind) {
+		return
+	}
+
+	t.violations = append(t.violations, violation)
+}
+
+// staticMismatch returns whether a static member is accessed as an instance
+// member, or an instance member is accessed statically.
+func (t *visibilityTraverser) staticMismatch(
+	kind Kind,
+	static bool,
+	receiver ast.Vertex,
+	mod symbol.Modified,
+	scopes *expr.Scopes,
+	declaring *symbol.ClassLike,
+) bool {
+	if static == mod.IsStatic() {
+		return false
+	}
+
+	// Calling static methods on an instance is allowed.
+	if kind == KindMethod && !static {
+		return false
+	}
+
+	if kind == KindProperty {
+		return true
+	}
+
+	// A non-static method, called like a static method: parent::foo(), self::foo()
+	// and Foo::foo() are instance calls from an instance method of the class
+	// or its children.
+	if len(t.methods) == 0 || nodeident.HasModifier(t.methods[len(t.methods)-1].Modifiers, "static") {
+		return true
+	}
+
+	switch strings.ToLower(nodeident.Get(receiver)) {
+	case "self", "static", "parent":
+		return false
+	}
+
+	privacy, err := expr.DeterminePrivacy(scopes, declaring.GetFQN())
+	return err == nil && privacy == phprivacy.PrivacyPublic
+}
+
+// accessibleFromDeclaring returns whether the member is accessible because
+// the scope is the class declaring it, or inherits it, even if the class
+// accessed is another one.
+func (t *visibilityTraverser) accessibleFromDeclaring(
+	scopes *expr.Scopes,
+	declaring *symbol.ClassLike,
+	mod symbol.Modified,
+) bool {
+	privacy, err := expr.DeterminePrivacy(scopes, declaring.GetFQN())
+	if err != nil {
+		return true
+	}
+
+	return mod.CanBeAccessedFrom(privacy)
+}
+
+func (t *visibilityTraverser) receiverClass(receiver ast.Vertex) (*fqn.FQN, bool) {
+	if nodescopes.IsName(receiver.GetType()) {
+		ident := strings.ToLower(nodeident.Get(receiver))
+		switch ident {
+		case "parent":
+			cls, ok := t.classes[len(t.classes)-1].(*ast.StmtClass)
+			if !ok || cls.Extends == nil {
+				return nil, false
+			}
+
+			fallthrough
+
+		case "self", "static":
+			// Resolved like $this, see the class constant resolver in expr.
+			receiver = &ast.ExprVariable{
+				Position: receiver.GetPosition(),
+				Name: &ast.Identifier{
+					Position: receiver.GetPosition(),
+					Value:    []byte(ident),
+				},
+			}
+		}
+	}
+
+	_, cls, left := expr.Resolve(receiver, t.scopes())
+	if left != 0 || cls == nil {
+		return nil, false
+	}
+
+	return cls, true
+}
+
+// findMember finds the member in the class or the classes it inherits from,
+// regardless of its privacy, accessible is whether it can be accessed with the
+// given privacy, following the rules of PHP.
+func findMember(
+	cls *symbol.ClassLike,
+	kind Kind,
+	name string,
+	privacy phprivacy.Privacy,
+) (declaring *symbol.ClassLike, mod symbol.Modified, accessible bool, ok bool) {
+	if mod := declaredMember(cls, kind, name); mod != nil {
+		return cls, mod, mod.CanBeAccessedFrom(
+			expr.MemberPrivacy(privacy, cls.Kind(), true, true),
+		), true
+	}
+
+	isFirstClass := true
+	iter := cls.InheritsIter()
+	for inhCls, done, err := iter(); !done; inhCls, done, err = iter() {
+		if err != nil {
+			return nil, nil, false, false
+		}
+
+		if inhCls.Kind() == ast.TypeStmtClass {
+			isFirstClass = false
+		}
+
+		if mod := declaredMember(inhCls, kind, name); mod != nil {
+			return inhCls, mod, mod.CanBeAccessedFrom(
+				expr.MemberPrivacy(privacy, inhCls.Kind(), false, isFirstClass),
+			), true
+		}
+	}
+
+	return nil, nil, false, false
+}
+
+func declaredMember(cls *symbol.ClassLike, kind Kind, name string) symbol.Modified {
+	switch kind {
+	case KindMethod:
+		if m := cls.FindMethod(filterMethodName(name)); m != nil {
+			return m
+		}
+
+	case KindProperty:
+		if p := cls.FindProperty(symbol.FilterName[*symbol.Property]("$" + name)); p != nil {
+			return p
+		}
+
+	case KindClassConstant:
+		if c := cls.FindConstant(symbol.FilterName[*symbol.ClassConst](name)); c != nil {
+			return c
+		}
+	}
+
+	return nil
+}
+
+// hasMagic returns whether the class implements the magic methods that are
+// called when an inaccessible member is accessed.
+func hasMagic(cls *symbol.ClassLike, kind Kind) bool {
+	for _, magic := range magicMethods[kind] {
+		if cls.FindMethod(filterMethodName(magic)) != nil {
+			return true
+		}
+
+		iter := cls.InheritsIter()
+		for inhCls, done, err := iter(); !done; inhCls, done, err = iter() {
+			if err == nil && inhCls.FindMethod(filterMethodName(magic)) != nil {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Method names are case insensitive.
+func filterMethodName(name string) symbol.FilterFunc[*symbol.Method] {
+	return func(m *symbol.Method) bool {
+		return strings.EqualFold(m.Name(), name)
+	}
+}
+
+func memberName(node ast.Vertex) (string, bool) {
+	switch typedNode := node.(type) {
+	case *ast.Identifier:
+		return string(typedNode.Value), true
+
+	case *ast.ExprVariable:
+		// Static properties, $ is not part of the member name.
+		if ident, ok := typedNode.Name.(*ast.Identifier); ok {
+			return strings.TrimPrefix(string(ident.Value), "$"), true
+		}
+	}
+
+	return "", false
+}
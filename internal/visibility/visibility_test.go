@@ -0,0 +1,74 @@
+package visibility_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/laytan/phpls/internal/config"
+	"github.com/laytan/phpls/internal/index"
+	"github.com/laytan/phpls/internal/project"
+	"github.com/laytan/phpls/internal/visibility"
+	"github.com/laytan/phpls/internal/wrkspc"
+	"github.com/laytan/phpls/pkg/functional"
+	"github.com/laytan/phpls/pkg/pathutils"
+	"github.com/laytan/phpls/pkg/phpversion"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(
+		m,
+		// The cache size logger.
+		goleak.IgnoreTopFunction("github.com/laytan/phpls/internal/wrkspc.New.func1"),
+	)
+}
+
+func TestDiagnose(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.Join(pathutils.Root(), "internal", "visibility", "testdata")
+
+	err := setup(root, phpversion.EightOne())
+	require.NoError(t, err)
+
+	format := func(v *visibility.Violation) string {
+		return fmt.Sprintf("%d %s %s", v.Line(), v.Code(), v.Message())
+	}
+
+	expected := []string{
+		`66 visibility Call to private method \Visibility\TestData\Base::secret().`,
+		`68 visibility Access to private constant \Visibility\TestData\Base::PRIVATE.`,
+		`68 visibility Access to private property \Visibility\TestData\Base::$private.`,
+		`74 static-mismatch Non-static method \Visibility\TestData\Base::instance() cannot be called statically.`,
+		`92 visibility Call to protected method \Visibility\TestData\Base::shared().`,
+		`93 visibility Call to private method \Visibility\TestData\Base::secret().`,
+		`94 visibility Call to private method \Visibility\TestData\Greets::greet().`,
+		`96 visibility Access to protected property \Visibility\TestData\Base::$protected.`,
+		`98 visibility Access to protected constant \Visibility\TestData\Base::PROTECTED.`,
+		`99 static-mismatch Static property \Visibility\TestData\Base::$count accessed as non-static.`,
+		`100 static-mismatch Non-static property \Visibility\TestData\Base::$public cannot be accessed statically.`,
+		`102 static-mismatch Non-static method \Visibility\TestData\Base::instance() cannot be called statically.`,
+	}
+
+	violations := visibility.Diagnose(wrkspc.NewRooter(filepath.Join(root, "visibility.php")))
+	require.Equal(t, expected, functional.Map(violations, format))
+}
+
+func setup(root string, phpv *phpversion.PHPVersion) error {
+	config.Current = config.Default()
+	index.Current = index.New(phpv)
+	wrkspc.Current = wrkspc.New(
+		phpv,
+		root,
+		filepath.Join(pathutils.Root(), "third_party", "phpstorm-stubs"),
+	)
+
+	p := project.New()
+	if err := p.ParseWithoutProgress(); err != nil {
+		return fmt.Errorf("[visibility_test.setup]: %w", err)
+	}
+
+	return nil
+}
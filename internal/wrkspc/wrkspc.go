@@ -86,6 +86,10 @@ type Wrkspc interface {
 	FLexerOf(path string) lexer.Lexer
 
 	IsPhpFile(path string) bool
+
+	// ProjectFiles returns the paths of the PHP files in the project, the stubs
+	// are not included.
+	ProjectFiles() ([]string, error)
 }
 
 // fileExtensions should all start with a period.
@@ -346,6 +350,32 @@ func (w *wrkspc) walk(walker func(path string, d fs.DirEntry) error) error {
 	return finalErr
 }
 
+func (w *wrkspc) ProjectFiles() ([]string, error) {
+	root := w.Root()
+
+	var paths []string
+	if err := w.parser(root).Walk(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		doParse, err := w.shouldParse(d)
+		if err != nil {
+			return err
+		}
+
+		if doParse {
+			paths = append(paths, path)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf(ErrParseWalkFmt, err)
+	}
+
+	return paths, nil
+}
+
 func (w *wrkspc) IsPhpFile(path string) bool {
 	for _, extension := range w.fileExtensions {
 		if strings.HasSuffix(path, extension) {
@@ -0,0 +1,211 @@
+// Package baseline records existing diagnostics, so that only new ones are
+// reported.
+package baseline
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/laytan/go-lsp-protocol/pkg/lsp/protocol"
+	"github.com/laytan/phpls/pkg/position"
+)
+
+// Entry is a diagnostic in the baseline, Count is the amount of diagnostics
+// with the same key in the file.
+type Entry struct {
+	Path        string `json:"path"`
+	Analyzer    string `json:"analyzer"`
+	Code        string `json:"code,omitempty"`
+	Fingerprint string `json:"fingerprint"`
+	// The message is not used for matching, it is there for reviewing the baseline.
+	Message string `json:"message"`
+	Count   int    `json:"count"`
+}
+
+type key struct {
+	path        string
+	analyzer    string
+	code        string
+	fingerprint string
+}
+
+func (e *Entry) key() key {
+	return key{e.Path, e.Analyzer, e.Code, e.Fingerprint}
+}
+
+type file struct {
+	Entries []*Entry `json:"entries"`
+}
+
+type Baseline struct {
+	mu      sync.Mutex
+	entries map[key]*Entry
+}
+
+func New() *Baseline {
+	return &Baseline{entries: make(map[key]*Entry)}
+}
+
+// Load reads the baseline at the given path, an empty baseline is returned if
+// the file does not exist.
+func Load(path string) (*Baseline, error) {
+	b := New()
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return b, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading baseline %q: %w", path, err)
+	}
+
+	var f file
+	if err := json.Unmarshal(content, &f); err != nil {
+		return nil, fmt.Errorf("decoding baseline %q: %w", path, err)
+	}
+
+	for _, entry := range f.Entries {
+		b.entries[entry.key()] = entry
+	}
+
+	return b, nil
+}
+
+// Save writes the baseline to the path, sorted so changes are easy to review.
+func (b *Baseline) Save(path string) error {
+	b.mu.Lock()
+	f := file{Entries: make([]*Entry, 0, len(b.entries))}
+	for _, entry := range b.entries {
+		f.Entries = append(f.Entries, entry)
+	}
+	b.mu.Unlock()
+
+	sort.Slice(f.Entries, func(i, j int) bool {
+		a, b := f.Entries[i], f.Entries[j]
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+
+		if a.Analyzer != b.Analyzer {
+			return a.Analyzer < b.Analyzer
+		}
+
+		if a.Code != b.Code {
+			return a.Code < b.Code
+		}
+
+		return a.Fingerprint < b.Fingerprint
+	})
+
+	content, err := json.MarshalIndent(f, "", "    ")
+	if err != nil {
+		return fmt.Errorf("encoding baseline: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(content, '\n'), 0o644); err != nil { //nolint:gosec // Meant to be committed.
+		return fmt.Errorf("writing baseline %q: %w", path, err)
+	}
+
+	return nil
+}
+
+// Len returns the amount of diagnostics in the baseline.
+func (b *Baseline) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var total int
+	for _, entry := range b.entries {
+		total += entry.Count
+	}
+
+	return total
+}
+
+// Set replaces the entries of the path with the given diagnostics, content
+// is the content of the file the diagnostics are for.
+func (b *Baseline) Set(path string, content string, diagnostics []protocol.Diagnostic) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for k := range b.entries {
+		if k.path == path {
+			delete(b.entries, k)
+		}
+	}
+
+	for i := range diagnostics {
+		entry := newEntry(path, content, &diagnostics[i])
+		if existing, ok := b.entries[entry.key()]; ok {
+			existing.Count++
+			continue
+		}
+
+		entry.Count = 1
+		b.entries[entry.key()] = entry
+	}
+}
+
+// Filter returns the diagnostics that are not in the baseline. When a file has
+// more of the same diagnostic than the baseline has, the last ones are returned.
+func (b *Baseline) Filter(
+	path string,
+	content string,
+	diagnostics []protocol.Diagnostic,
+) []protocol.Diagnostic {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	used := make(map[key]int)
+	filtered := make([]protocol.Diagnostic, 0, len(diagnostics))
+	for i := range diagnostics {
+		k := newEntry(path, content, &diagnostics[i]).key()
+		if entry, ok := b.entries[k]; ok && used[k] < entry.Count {
+			used[k]++
+			continue
+		}
+
+		filtered = append(filtered, diagnostics[i])
+	}
+
+	return filtered
+}
+
+func newEntry(path string, content string, d *protocol.Diagnostic) *Entry {
+	var code string
+	if d.Code != nil {
+		code = fmt.Sprint(d.Code)
+	}
+
+	return &Entry{
+		Path:        path,
+		Analyzer:    strings.TrimPrefix(d.Source, "phpls-"),
+		Code:        code,
+		Fingerprint: Fingerprint(content, d),
+		Message:     d.Message,
+	}
+}
+
+// Fingerprint identifies the diagnostic by its message and the code on the
+// line it starts at, without line numbers, so it survives code being added or
+// removed around it.
+func Fingerprint(content string, d *protocol.Diagnostic) string {
+	start := position.FromLSPPosition(content, protocol.Position{Line: d.Range.Start.Line})
+	end := strings.IndexByte(content[start:], '\n')
+	if end == -1 {
+		end = len(content) - start
+	}
+
+	h := sha256.New()
+	_, _ = h.Write([]byte(d.Message))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(strings.TrimSpace(content[start : start+end])))
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
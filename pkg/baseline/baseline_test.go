@@ -0,0 +1,92 @@
+package baseline_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/laytan/go-lsp-protocol/pkg/lsp/protocol"
+	"github.com/laytan/phpls/pkg/baseline"
+	"github.com/stretchr/testify/require"
+)
+
+func diagnostic(line uint32, source string, code any, message string) protocol.Diagnostic {
+	return protocol.Diagnostic{
+		Range: protocol.Range{
+			Start: protocol.Position{Line: line},
+			End:   protocol.Position{Line: line, Character: 1},
+		},
+		Source:  source,
+		Code:    code,
+		Message: message,
+	}
+}
+
+func TestFilter(t *testing.T) {
+	t.Parallel()
+
+	content := "<?php\n\n$a = foo();\n$b = foo();\n"
+	b := baseline.New()
+	b.Set("src/a.php", content, []protocol.Diagnostic{
+		diagnostic(2, "phpls-phpstan", "function.notFound", "Function foo not found."),
+		diagnostic(3, "phpls-phpstan", "function.notFound", "Function foo not found."),
+		diagnostic(2, "phpls-phpcs", "Generic.Sniff", "Something"),
+	})
+	require.Equal(t, 3, b.Len())
+
+	// Lines added above the existing violations.
+	shifted := "<?php\n\nuse Foo;\n\n$a = foo();\n$c = foo();\n$b = foo();\n"
+	filtered := b.Filter("src/a.php", shifted, []protocol.Diagnostic{
+		diagnostic(4, "phpls-phpstan", "function.notFound", "Function foo not found."),
+		diagnostic(5, "phpls-phpstan", "function.notFound", "Function foo not found."),
+		diagnostic(6, "phpls-phpstan", "function.notFound", "Function foo not found."),
+		diagnostic(4, "phpls-phpcs", "Generic.Sniff", "Something"),
+		diagnostic(4, "phpls-phpcs", "Generic.Sniff", "Something else"),
+	})
+	require.Equal(t, []protocol.Diagnostic{
+		diagnostic(5, "phpls-phpstan", "function.notFound", "Function foo not found."),
+		diagnostic(4, "phpls-phpcs", "Generic.Sniff", "Something else"),
+	}, filtered)
+
+	// Other files are not affected.
+	other := []protocol.Diagnostic{diagnostic(2, "phpls-phpcs", "Generic.Sniff", "Something")}
+	require.Equal(t, other, b.Filter("src/b.php", content, other))
+}
+
+func TestSetReplaces(t *testing.T) {
+	t.Parallel()
+
+	content := "<?php\n\nfoo();\n"
+	b := baseline.New()
+	b.Set("a.php", content, []protocol.Diagnostic{diagnostic(2, "phpls-psalm", nil, "A")})
+	b.Set("b.php", content, []protocol.Diagnostic{diagnostic(2, "phpls-psalm", nil, "B")})
+	b.Set("a.php", content, nil)
+	require.Equal(t, 1, b.Len())
+}
+
+func TestSaveLoad(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "baseline.json")
+
+	b, err := baseline.Load(path)
+	require.NoError(t, err)
+	require.Equal(t, 0, b.Len())
+
+	content := "<?php\n\nfoo();\nbar();\n"
+	diagnostics := []protocol.Diagnostic{
+		diagnostic(3, "phpls-phpstan", "function.notFound", "Function bar not found."),
+		diagnostic(2, "phpls-phpstan", "function.notFound", "Function foo not found."),
+	}
+	b.Set("a.php", content, diagnostics)
+	require.NoError(t, b.Save(path))
+
+	loaded, err := baseline.Load(path)
+	require.NoError(t, err)
+	require.Equal(t, 2, loaded.Len())
+	require.Empty(t, loaded.Filter("a.php", content, diagnostics))
+
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0o600))
+	_, err = baseline.Load(path)
+	require.Error(t, err)
+}
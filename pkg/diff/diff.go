@@ -0,0 +1,240 @@
+// Package diff computes line based differences between two texts, using the
+// Myers algorithm, and converts them into LSP text edits.
+package diff
+
+import (
+	"strings"
+
+	"github.com/laytan/go-lsp-protocol/pkg/lsp/protocol"
+	"github.com/laytan/phpls/pkg/position"
+)
+
+// Hunk is a change of consecutive lines, the zero-based, end exclusive, line
+// range [OldStart, OldEnd) of the old text is replaced with [NewStart, NewEnd)
+// of the new text.
+type Hunk struct {
+	OldStart int
+	OldEnd   int
+	NewStart int
+	NewEnd   int
+}
+
+// Contains returns whether the hunk changes the given (zero-based) line of the
+// old text, an insertion contains the lines it is between.
+func (h Hunk) Contains(line int) bool {
+	if h.OldStart == h.OldEnd {
+		return line == h.OldStart || line == h.OldStart-1
+	}
+
+	return line >= h.OldStart && line < h.OldEnd
+}
+
+type Diff struct {
+	Hunks []Hunk
+
+	old        string
+	oldLines   []string
+	newLines   []string
+	lineStarts []int
+}
+
+// New computes the difference between the old and new text.
+func New(old string, updated string) *Diff {
+	d := &Diff{
+		old:      old,
+		oldLines: splitLines(old),
+		newLines: splitLines(updated),
+	}
+
+	d.Hunks = Lines(d.oldLines, d.newLines)
+
+	offset := 0
+	d.lineStarts = make([]int, 0, len(d.oldLines)+1)
+	for _, line := range d.oldLines {
+		d.lineStarts = append(d.lineStarts, offset)
+		offset += len(line)
+	}
+	d.lineStarts = append(d.lineStarts, offset)
+
+	return d
+}
+
+// Edit returns the text edit that applies the hunk to the old text.
+func (d *Diff) Edit(h Hunk) protocol.TextEdit {
+	return protocol.TextEdit{
+		Range: protocol.Range{
+			Start: position.ToLSPPosition(d.old, d.lineStarts[h.OldStart]),
+			End:   position.ToLSPPosition(d.old, d.lineStarts[h.OldEnd]),
+		},
+		NewText: strings.Join(d.newLines[h.NewStart:h.NewEnd], ""),
+	}
+}
+
+// Edits returns the text edits that turn the old text into the new text.
+func (d *Diff) Edits() []protocol.TextEdit {
+	edits := make([]protocol.TextEdit, 0, len(d.Hunks))
+	for _, h := range d.Hunks {
+		edits = append(edits, d.Edit(h))
+	}
+
+	return edits
+}
+
+// Lines returns the hunks that turn the old lines into the new lines.
+func Lines(old []string, updated []string) []Hunk {
+	// Common prefix and suffix are trimmed, these are usually most of the
+	// lines, and would otherwise blow up the trace of the algorithm.
+	prefix := 0
+	for prefix < len(old) && prefix < len(updated) && old[prefix] == updated[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(old)-prefix && suffix < len(updated)-prefix &&
+		old[len(old)-1-suffix] == updated[len(updated)-1-suffix] {
+		suffix++
+	}
+
+	a, b := old[prefix:len(old)-suffix], updated[prefix:len(updated)-suffix]
+	hunks := myers(a, b)
+	for i := range hunks {
+		hunks[i].OldStart += prefix
+		hunks[i].OldEnd += prefix
+		hunks[i].NewStart += prefix
+		hunks[i].NewEnd += prefix
+	}
+
+	return hunks
+}
+
+type opKind int
+
+const (
+	opEqual opKind = iota
+	opDelete
+	opInsert
+)
+
+// myers implements the algorithm described in "An O(ND) Difference Algorithm
+// and Its Variations" by Eugene W. Myers.
+func myers(a []string, b []string) []Hunk {
+	n, m := len(a), len(b)
+	if n == 0 && m == 0 {
+		return nil
+	}
+
+	total := n + m
+	offset := total + 1
+	v := make([]int, 2*total+2)
+	var trace [][]int
+
+Search:
+	for d := 0; d <= total; d++ {
+		trace = append(trace, append([]int(nil), v...))
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+
+			v[offset+k] = x
+			if x >= n && y >= m {
+				break Search
+			}
+		}
+	}
+
+	// Backtrack through the trace to get the operations, in reverse.
+	var ops []opKind
+	x, y := n, m
+	for d := len(trace) - 1; d > 0; d-- {
+		v := trace[d]
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			ops = append(ops, opEqual)
+			x--
+			y--
+		}
+
+		if x == prevX {
+			ops = append(ops, opInsert)
+		} else {
+			ops = append(ops, opDelete)
+		}
+
+		x, y = prevX, prevY
+	}
+
+	for x > 0 && y > 0 {
+		ops = append(ops, opEqual)
+		x--
+		y--
+	}
+
+	var hunks []Hunk
+	var current *Hunk
+	x, y = 0, 0
+	for i := len(ops) - 1; i >= 0; i-- {
+		if ops[i] == opEqual {
+			if current != nil {
+				hunks = append(hunks, *current)
+				current = nil
+			}
+
+			x++
+			y++
+			continue
+		}
+
+		if current == nil {
+			current = &Hunk{OldStart: x, OldEnd: x, NewStart: y, NewEnd: y}
+		}
+
+		if ops[i] == opDelete {
+			x++
+			current.OldEnd = x
+		} else {
+			y++
+			current.NewEnd = y
+		}
+	}
+
+	if current != nil {
+		hunks = append(hunks, *current)
+	}
+
+	return hunks
+}
+
+// splitLines splits the text into lines, keeping the line endings.
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
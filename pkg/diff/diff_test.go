@@ -0,0 +1,102 @@
+package diff_test
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/laytan/phpls/pkg/diff"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLines(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name     string
+		old      string
+		updated  string
+		expected []diff.Hunk
+	}{
+		{
+			name:    "equal",
+			old:     "a\nb\n",
+			updated: "a\nb\n",
+		},
+		{
+			name:     "change",
+			old:      "a\nb\nc\n",
+			updated:  "a\nx\nc\n",
+			expected: []diff.Hunk{{OldStart: 1, OldEnd: 2, NewStart: 1, NewEnd: 2}},
+		},
+		{
+			name:     "insert",
+			old:      "a\nc\n",
+			updated:  "a\nb\nc\n",
+			expected: []diff.Hunk{{OldStart: 1, OldEnd: 1, NewStart: 1, NewEnd: 2}},
+		},
+		{
+			name:     "delete",
+			old:      "a\nb\nc\n",
+			updated:  "a\nc\n",
+			expected: []diff.Hunk{{OldStart: 1, OldEnd: 2, NewStart: 1, NewEnd: 1}},
+		},
+		{
+			name:    "multiple hunks",
+			old:     "a\nb\nc\nd\ne\n",
+			updated: "x\nb\nc\ne\ny\n",
+			expected: []diff.Hunk{
+				{OldStart: 0, OldEnd: 1, NewStart: 0, NewEnd: 1},
+				{OldStart: 3, OldEnd: 4, NewStart: 3, NewEnd: 3},
+				{OldStart: 5, OldEnd: 5, NewStart: 4, NewEnd: 5},
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			d := diff.New(scenario.old, scenario.updated)
+			require.Equal(t, scenario.expected, d.Hunks)
+		})
+	}
+}
+
+func TestLinesApply(t *testing.T) {
+	t.Parallel()
+
+	rnd := rand.New(rand.NewSource(1))
+	randomLines := func() []string {
+		lines := make([]string, rnd.Intn(20))
+		for i := range lines {
+			lines[i] = string(rune('a' + rnd.Intn(4)))
+		}
+
+		return lines
+	}
+
+	for i := 0; i < 500; i++ {
+		old, updated := randomLines(), randomLines()
+
+		var result []string
+		prev := 0
+		for _, h := range diff.Lines(old, updated) {
+			require.True(t, h.OldStart >= prev, "hunks should be ordered")
+			result = append(result, old[prev:h.OldStart]...)
+			result = append(result, updated[h.NewStart:h.NewEnd]...)
+			prev = h.OldEnd
+		}
+		result = append(result, old[prev:]...)
+
+		require.Equal(
+			t,
+			strings.Join(updated, ","),
+			strings.Join(result, ","),
+			"old: %v, updated: %v",
+			old,
+			updated,
+		)
+	}
+}
@@ -212,6 +212,65 @@ func TestFQNTraverser(t *testing.T) {
 			},
 			expect: "\\Testing\\Test",
 		},
+		"qualified name with use statement": {
+			code: `
+            <?php
+            namespace Test;
+            use Testing\Sub;
+            // Some comment.
+            `,
+			name: &ast.Name{
+				Parts: []ast.Vertex{
+					&ast.NamePart{Value: []byte("Sub")},
+					&ast.NamePart{Value: []byte("TestName")},
+				},
+				Position: &position.Position{
+					StartLine: 4,
+					StartPos:  70,
+					EndLine:   4,
+					EndPos:    71,
+				},
+			},
+			expect: "\\Testing\\Sub\\TestName",
+		},
+		"qualified name without use statement": {
+			code: `
+            <?php
+            namespace Test;
+            // Some comment.
+            `,
+			name: &ast.Name{
+				Parts: []ast.Vertex{
+					&ast.NamePart{Value: []byte("Sub")},
+					&ast.NamePart{Value: []byte("TestName")},
+				},
+				Position: &position.Position{
+					StartLine: 3,
+					StartPos:  50,
+					EndLine:   3,
+					EndPos:    51,
+				},
+			},
+			expect: "\\Test\\Sub\\TestName",
+		},
+		"group use statement": {
+			code: `
+            <?php
+            namespace Test;
+            use Testing\{One, Two as Alias};
+            // Some comment.
+            `,
+			name: &ast.Name{
+				Parts: []ast.Vertex{&ast.NamePart{Value: []byte("Alias")}},
+				Position: &position.Position{
+					StartLine: 4,
+					StartPos:  90,
+					EndLine:   4,
+					EndPos:    91,
+				},
+			},
+			expect: "\\Testing\\Two",
+		},
 	}
 
 	parser := parsing.New(phpversion.EightOne())
@@ -29,6 +29,17 @@ func NewTraverserHandlingKeywords(block ast.Vertex) *Traverser {
 	return &Traverser{block: block, namespaces: []*namespace{globalNamespace()}}
 }
 
+// IsResolvable returns whether the node is a name that can be resolved
+// statically, relative names (namespace\Foo) are not supported.
+func IsResolvable(node ast.Vertex) bool {
+	if node == nil {
+		return false
+	}
+
+	kind := node.GetType()
+	return kind == ast.TypeName || kind == ast.TypeNameFullyQualified
+}
+
 func (f *Traverser) ResultFor2(position *position.Position, name string) *FQN {
 	return f.ResultFor(&ast.Name{
 		Position: position,
@@ -68,32 +79,37 @@ func (f *Traverser) ResultFor(name ast.Vertex) *FQN {
 		}
 	}
 
+	// The first part of a qualified name is resolved against the use statements,
+	// the rest is relative to that.
 	parts := strings.Split(nv, "\\")
-	cn := parts[len(parts)-1]
+	first, rest := parts[0], strings.Join(parts[1:], "\\")
+	if rest != "" {
+		rest = PartSeperator + rest
+	}
 
-	// If any use statement ends with the class name, use that.
+	// If any use statement ends with the first part, use that.
 	for _, usage := range ns.uses {
 		useIdent := nodeident.Get(usage.Use)
 		if usage.Alias != nil {
-			if nodeident.Get(usage.Alias) == cn {
-				return New(PartSeperator + useIdent)
+			if nodeident.Get(usage.Alias) == first {
+				return New(PartSeperator + useIdent + rest)
 			}
 		}
 
 		useParts := strings.Split(useIdent, "\\")
 		un := useParts[len(useParts)-1]
-		if un == cn {
-			return New(PartSeperator + useIdent)
+		if un == first {
+			return New(PartSeperator + useIdent + rest)
 		}
 	}
 
-	// Else use namespace+class name.
+	// Else use namespace+name.
 	if ns.ns != "" {
-		return New(ns.ns + PartSeperator + cn)
+		return New(ns.ns + PartSeperator + nv)
 	}
 
-	// Else use class name.
-	return New(PartSeperator + cn)
+	// Else use name.
+	return New(PartSeperator + nv)
 }
 
 func (f *Traverser) EnterNode(node ast.Vertex) bool {
@@ -118,6 +134,33 @@ func (f *Traverser) EnterNode(node ast.Vertex) bool {
 
 		return false
 
+	case *ast.StmtGroupUseList:
+		// The uses in a group are relative to its prefix, add them as if they
+		// were separate use statements.
+		prefix := nodeident.Get(typedNode.Prefix)
+		currNs := f.namespaces[len(f.namespaces)-1]
+		for _, use := range typedNode.Uses {
+			typedUse, ok := use.(*ast.StmtUse)
+			if !ok {
+				continue
+			}
+
+			currNs.uses = append(currNs.uses, &ast.StmtUse{
+				Position: typedUse.Position,
+				Type:     typedUse.Type,
+				Use: &ast.Name{
+					Position: typedUse.Use.GetPosition(),
+					Parts: functional.Map(
+						strings.Split(prefix+PartSeperator+nodeident.Get(typedUse.Use), PartSeperator),
+						func(s string) ast.Vertex { return &ast.NamePart{Value: []byte(s)} },
+					),
+				},
+				Alias: typedUse.Alias,
+			})
+		}
+
+		return false
+
 	case *ast.StmtNamespace:
 		ns := &namespace{
 			ns:   nodeident.Get(typedNode),
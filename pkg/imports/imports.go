@@ -0,0 +1,355 @@
+// Package imports parses the use statements of a file and determines which of
+// the imported names are used.
+package imports
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/laytan/php-parser/pkg/ast"
+	"github.com/laytan/php-parser/pkg/visitor"
+	"github.com/laytan/php-parser/pkg/visitor/traverser"
+	"github.com/laytan/phpls/pkg/nodeident"
+	"github.com/laytan/phpls/pkg/phpdoxer"
+	"github.com/laytan/phpls/pkg/set"
+)
+
+var docCommentRgx = regexp.MustCompile(`(?s)/\*\*.*?\*/`)
+
+// Matches identifiers and (qualified) names in PHPDoc text.
+var docWordRgx = regexp.MustCompile(`\\?[\pL_][\\\pL\pN_]*`)
+
+// Kind is the kind of symbol an import imports.
+type Kind int
+
+const (
+	KindClass Kind = iota
+	KindFunction
+	KindConst
+)
+
+// Keyword returns the keyword, followed by a space, that is put after `use`
+// to import this kind of symbol.
+func (k Kind) Keyword() string {
+	switch k {
+	case KindFunction:
+		return "function "
+	case KindConst:
+		return "const "
+	default:
+		return ""
+	}
+}
+
+// Import is a single imported name of a use statement.
+type Import struct {
+	Kind Kind
+	// The imported name, without leading \.
+	Name  string
+	Alias string
+	// The use node this import comes from.
+	Node *ast.StmtUse
+}
+
+// Ident is the identifier the import is referred to by in code.
+func (i *Import) Ident() string {
+	if i.Alias != "" {
+		return i.Alias
+	}
+
+	parts := strings.Split(i.Name, `\`)
+	return parts[len(parts)-1]
+}
+
+// Namespace returns the namespace of the imported name.
+func (i *Import) Namespace() string {
+	ns, _, _ := cutLast(i.Name, `\`)
+	return ns
+}
+
+func (i *Import) String() string {
+	_, name, _ := cutLast(i.Name, `\`)
+	if i.Alias != "" && i.Alias != name {
+		return i.Name + " as " + i.Alias
+	}
+
+	return i.Name
+}
+
+// Scope is a part of the file with its own imports, the global scope
+// or a namespace.
+type Scope struct {
+	Stmts []ast.Vertex
+	// Byte offsets of the scope in the file content.
+	Start int
+	End   int
+}
+
+// Scopes splits the file into its import scopes, contentLen is the length of
+// the file content, used as the end of the last scope.
+func Scopes(root *ast.Root, contentLen int) []*Scope {
+	curr := &Scope{End: contentLen}
+	scopes := []*Scope{curr}
+	for _, stmt := range root.Stmts {
+		ns, ok := stmt.(*ast.StmtNamespace)
+		if !ok {
+			curr.Stmts = append(curr.Stmts, stmt)
+			continue
+		}
+
+		if curr.End == contentLen {
+			curr.End = ns.Position.StartPos
+		}
+
+		if ns.OpenCurlyBracketTkn != nil {
+			scopes = append(scopes, &Scope{
+				Stmts: ns.Stmts,
+				Start: ns.OpenCurlyBracketTkn.Position.EndPos,
+				End:   ns.CloseCurlyBracketTkn.Position.StartPos,
+			})
+			curr = &Scope{Start: ns.Position.EndPos, End: contentLen}
+			scopes = append(scopes, curr)
+			continue
+		}
+
+		curr = &Scope{Start: ns.Position.EndPos, End: contentLen}
+		scopes = append(scopes, curr)
+	}
+
+	return scopes
+}
+
+// Parse returns the imports of the statement, false if it is not a use statement.
+func Parse(stmt ast.Vertex) ([]*Import, bool) {
+	var imports []*Import
+	switch typedStmt := stmt.(type) {
+	case *ast.StmtUseList:
+		kind := parseImportKind(typedStmt.Type, KindClass)
+		for _, use := range typedStmt.Uses {
+			imports = append(imports, parseImport(use.(*ast.StmtUse), "", kind))
+		}
+
+	case *ast.StmtGroupUseList:
+		kind := parseImportKind(typedStmt.Type, KindClass)
+		prefix := strings.TrimPrefix(nodeident.Get(typedStmt.Prefix), `\`)
+		for _, use := range typedStmt.Uses {
+			imports = append(imports, parseImport(use.(*ast.StmtUse), prefix, kind))
+		}
+
+	default:
+		return nil, false
+	}
+
+	return imports, true
+}
+
+func parseImport(use *ast.StmtUse, prefix string, kind Kind) *Import {
+	name := strings.TrimPrefix(nodeident.Get(use.Use), `\`)
+	if prefix != "" {
+		name = prefix + `\` + name
+	}
+
+	imp := &Import{
+		Kind: parseImportKind(use.Type, kind),
+		Name: name,
+		Node: use,
+	}
+
+	if use.Alias != nil {
+		imp.Alias = nodeident.Get(use.Alias)
+	}
+
+	return imp
+}
+
+func parseImportKind(typ ast.Vertex, fallback Kind) Kind {
+	if typ == nil {
+		return fallback
+	}
+
+	switch strings.ToLower(nodeident.Get(typ)) {
+	case "function":
+		return KindFunction
+	case "const":
+		return KindConst
+	default:
+		return fallback
+	}
+}
+
+// Usage keeps the identifiers used in a scope, class and function
+// identifiers are case-insensitive in PHP, constants are case-sensitive.
+type Usage struct {
+	classes   *set.Set[string]
+	functions *set.Set[string]
+	constants *set.Set[string]
+}
+
+// Uses returns whether the import is used.
+func (u *Usage) Uses(imp *Import) bool {
+	switch imp.Kind {
+	case KindFunction:
+		return u.functions.Has(strings.ToLower(imp.Ident()))
+	case KindConst:
+		return u.constants.Has(imp.Ident())
+	default:
+		return u.classes.Has(strings.ToLower(imp.Ident()))
+	}
+}
+
+// Usage collects the identifiers used in the scope, content is the file content
+// used to find the PHPDoc in the scope.
+func (s *Scope) Usage(content string) *Usage {
+	v := &usageVisitor{
+		usage: &Usage{
+			classes:   set.New[string](),
+			functions: set.New[string](),
+			constants: set.New[string](),
+		},
+		functionNames: set.New[ast.Vertex](),
+		constantNames: set.New[ast.Vertex](),
+	}
+
+	tv := traverser.NewTraverser(v)
+	for _, stmt := range s.Stmts {
+		switch stmt.(type) {
+		case *ast.StmtUseList, *ast.StmtGroupUseList:
+			continue
+		default:
+			stmt.Accept(tv)
+		}
+	}
+
+	for _, doc := range docCommentRgx.FindAllString(content[s.Start:s.End], -1) {
+		v.usage.addDoc(doc)
+	}
+
+	return v.usage
+}
+
+// addDoc adds the class names used in the PHPDoc to the usage, these are
+// resolved against the imports the same way as the names in code.
+func (u *Usage) addDoc(doc string) {
+	nodes, err := phpdoxer.ParseDoc(doc)
+	if err != nil {
+		return
+	}
+
+	for _, node := range nodes {
+		var typ phpdoxer.Type
+		switch typedNode := node.(type) {
+		case *phpdoxer.NodeReturn:
+			typ = typedNode.Type
+		case *phpdoxer.NodeVar:
+			typ = typedNode.Type
+		case *phpdoxer.NodeParam:
+			typ = typedNode.Type
+		case *phpdoxer.NodeThrows:
+			typ = typedNode.Type
+		case *phpdoxer.NodeTemplate:
+			typ = typedNode.Of
+		case *phpdoxer.NodeExtends:
+			typ = typedNode.Type
+		case *phpdoxer.NodeImplements:
+			typ = typedNode.Type
+		case *phpdoxer.NodeUse:
+			typ = typedNode.Type
+		case *phpdoxer.NodeMixin:
+			typ = typedNode.Type
+		case *phpdoxer.NodeProperty:
+			typ = typedNode.Type
+		case *phpdoxer.NodeMethod:
+			typ = typedNode.Return
+			for _, param := range typedNode.Params {
+				u.addType(param.Type)
+			}
+		case *phpdoxer.NodeUnknown:
+			// Tags like @see and @psalm-type, any word might be a name and it
+			// is better to keep an import than to remove one that is used.
+			for _, word := range docWordRgx.FindAllString(typedNode.Value, -1) {
+				if strings.HasPrefix(word, `\`) {
+					continue
+				}
+
+				u.addName(word, u.classes)
+				u.addName(word, u.functions)
+				u.addName(word, u.constants)
+			}
+		}
+
+		u.addType(typ)
+	}
+}
+
+// addType adds the class names used in the type to the usage.
+func (u *Usage) addType(typ phpdoxer.Type) {
+	phpdoxer.Walk(typ, func(t phpdoxer.Type) bool {
+		switch typedType := t.(type) {
+		case *phpdoxer.TypeClassLike:
+			if !typedType.FullyQualified {
+				u.addName(typedType.Name, u.classes)
+			}
+		case *phpdoxer.TypeConstant:
+			// A class in all caps is parsed as a constant.
+			if typedType.Class == nil {
+				u.addName(typedType.Const, u.classes)
+				u.constants.Add(typedType.Const)
+			}
+		}
+
+		return true
+	})
+}
+
+// addName adds the name to the usage, a qualified name uses the import of its
+// first part (use Foo\Bar; new Bar\Baz()).
+func (u *Usage) addName(name string, kind *set.Set[string]) {
+	first, _, qualified := strings.Cut(name, `\`)
+	if qualified {
+		u.classes.Add(strings.ToLower(first))
+		return
+	}
+
+	if kind == u.constants {
+		kind.Add(name)
+		return
+	}
+
+	kind.Add(strings.ToLower(name))
+}
+
+type usageVisitor struct {
+	visitor.Null
+
+	usage         *Usage
+	functionNames *set.Set[ast.Vertex]
+	constantNames *set.Set[ast.Vertex]
+}
+
+func (v *usageVisitor) ExprFunctionCall(n *ast.ExprFunctionCall) {
+	v.functionNames.Add(n.Function)
+}
+
+func (v *usageVisitor) ExprConstFetch(n *ast.ExprConstFetch) {
+	v.constantNames.Add(n.Const)
+}
+
+// NameName is not called for fully qualified or namespace relative names, those don't use imports.
+func (v *usageVisitor) NameName(n *ast.Name) {
+	switch {
+	case v.functionNames.Has(n):
+		v.usage.addName(nodeident.Get(n), v.usage.functions)
+	case v.constantNames.Has(n):
+		v.usage.addName(nodeident.Get(n), v.usage.constants)
+	default:
+		v.usage.addName(nodeident.Get(n), v.usage.classes)
+	}
+}
+
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+
+	return "", s, false
+}
@@ -29,10 +29,52 @@ func (t *Tracker) Start(
 	ctx context.Context,
 	title, message string,
 	token protocol.ProgressToken,
+) (*WorkDone, error) {
+	return t.start(ctx, title, message, token, nil)
+}
+
+// StartCancellable starts work that the user can cancel, the returned context
+// is cancelled when Cancel is called with the token of the work.
+func (t *Tracker) StartCancellable(
+	ctx context.Context,
+	title, message string,
+	token protocol.ProgressToken,
+) (*WorkDone, context.Context, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	wd, err := t.start(ctx, title, message, token, cancel)
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+
+	return wd, ctx, nil
+}
+
+// Cancel cancels the work with the given token, returns false if there is no
+// cancellable work with the token in progress.
+func (t *Tracker) Cancel(token protocol.ProgressToken) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	wd, ok := t.inProgress[token]
+	if !ok || wd.cancel == nil {
+		return false
+	}
+
+	wd.cancel()
+	return true
+}
+
+func (t *Tracker) start(
+	ctx context.Context,
+	title, message string,
+	token protocol.ProgressToken,
+	cancel context.CancelFunc,
 ) (*WorkDone, error) {
 	wd := &WorkDone{
 		client: t.client,
 		token:  token,
+		cancel: cancel,
 	}
 
 	if wd.token == nil {
@@ -62,9 +104,10 @@ func (t *Tracker) Start(
 	err := wd.client.Progress(ctx, &protocol.ProgressParams{
 		Token: wd.token,
 		Value: &protocol.WorkDoneProgressBegin{
-			Kind:    "begin",
-			Message: message,
-			Title:   title,
+			Kind:        "begin",
+			Message:     message,
+			Title:       title,
+			Cancellable: cancel != nil,
 		},
 	})
 	if err != nil {
@@ -90,6 +133,37 @@ func (t *Tracker) Track(
 		return nil, fmt.Errorf("starting progress track: %w", err)
 	}
 
+	return track(ctx, progress, done, total, interval), nil
+}
+
+// TrackCancellable is Track for work that the user can cancel, see StartCancellable.
+// The token is optional, a token given by the client for the request can be passed.
+func (t *Tracker) TrackCancellable(
+	ctx context.Context,
+	token protocol.ProgressToken,
+	done, total func() float64,
+	title string,
+	interval time.Duration,
+) (workCtx context.Context, stop func(err error) error, err error) {
+	progress, workCtx, err := t.StartCancellable(
+		ctx,
+		title,
+		"Started",
+		token,
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("starting progress track: %w", err)
+	}
+
+	return workCtx, track(ctx, progress, done, total, interval), nil
+}
+
+func track(
+	ctx context.Context,
+	progress *WorkDone,
+	done, total func() float64,
+	interval time.Duration,
+) (stop func(err error) error) {
 	var reportErr error
 
 	timer := time.NewTicker(interval)
@@ -136,7 +210,7 @@ func (t *Tracker) Track(
 		}
 
 		return nil
-	}, nil
+	}
 }
 
 // WorkDone represents a unit of work that is reported to the client via the
@@ -146,6 +220,8 @@ type WorkDone struct {
 	token  protocol.ProgressToken
 
 	cleanup func()
+	// Set for cancellable work.
+	cancel context.CancelFunc
 }
 
 func (wd *WorkDone) Token() protocol.ProgressToken {
@@ -186,5 +262,9 @@ func (wd *WorkDone) End(ctx context.Context, message string) error {
 		wd.cleanup()
 	}
 
+	if wd.cancel != nil {
+		wd.cancel()
+	}
+
 	return nil
 }
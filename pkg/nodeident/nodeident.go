@@ -56,6 +56,9 @@ func Get(n ast.Vertex) string {
 	case *ast.StmtInterface:
 		return Get(n.Name)
 
+	case *ast.StmtEnum:
+		return Get(n.Name)
+
 	case *ast.Name:
 		return strings.Join(functional.Map(n.Parts, Get), "\\")
 
@@ -139,3 +142,15 @@ func Get(n ast.Vertex) string {
 		return ""
 	}
 }
+
+// HasModifier returns whether the modifiers contain the given modifier,
+// case-insensitively like PHP.
+func HasModifier(modifiers []ast.Vertex, modifier string) bool {
+	for _, mod := range modifiers {
+		if strings.EqualFold(Get(mod), modifier) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -13,6 +13,8 @@ import (
 	astErrors "github.com/laytan/php-parser/pkg/errors"
 	"github.com/laytan/php-parser/pkg/lexer"
 	astParser "github.com/laytan/php-parser/pkg/parser"
+	"github.com/laytan/php-parser/pkg/position"
+	"github.com/laytan/php-parser/pkg/token"
 	"github.com/laytan/php-parser/pkg/version"
 	"github.com/laytan/phpls/pkg/phpversion"
 )
@@ -27,12 +29,19 @@ var ErrNoContent = errors.New(
 	"No AST could be parsed, there were unrecoverable syntax errors or the file was empty",
 )
 
+// ErrPanic is returned when the parser panics, which it does on some invalid code.
+var ErrPanic = errors.New("The parser panicked")
+
 // Parser is responsible for and a central place for
 // file system access and parsing file content into
 // IR for use everywhere else.
 type Parser interface {
 	Parse(content []byte) (*ast.Root, error)
 
+	// ParseWithErrors parses the content, also returning the syntax errors that
+	// were recovered from.
+	ParseWithErrors(content []byte) (*ast.Root, []*astErrors.Error, error)
+
 	Lexer(content []byte) (lexer.Lexer, error)
 
 	Read(path string) (string, error)
@@ -51,7 +60,7 @@ func New(phpv *phpversion.PHPVersion) Parser {
 				Major: uint64(phpv.Major),
 				Minor: uint64(phpv.Minor),
 			},
-			// TODO: return these errors.
+			// Use ParseWithErrors to get these errors.
 			ErrorHandlerFunc: func(e *astErrors.Error) {
 				what.Happens(e.String())
 			},
@@ -60,7 +69,7 @@ func New(phpv *phpversion.PHPVersion) Parser {
 }
 
 func (p *parser) Parse(content []byte) (*ast.Root, error) {
-	a, err := astParser.Parse(content, p.config)
+	a, err := parse(content, p.config)
 	if err != nil || a == nil {
 		if err == nil {
 			return nil, ErrNoContent
@@ -72,6 +81,71 @@ func (p *parser) Parse(content []byte) (*ast.Root, error) {
 	return a.(*ast.Root), nil
 }
 
+func (p *parser) ParseWithErrors(content []byte) (*ast.Root, []*astErrors.Error, error) {
+	var errs []*astErrors.Error
+	config := p.config
+	config.ErrorHandlerFunc = func(e *astErrors.Error) {
+		errs = append(errs, e)
+	}
+
+	a, err := parse(content, config)
+	if err != nil || a == nil {
+		if err == nil {
+			return nil, errs, ErrNoContent
+		}
+
+		if errors.Is(err, ErrPanic) {
+			errs = append(errs, p.panicError(content))
+		}
+
+		return nil, errs, fmt.Errorf(ErrASTFmt, err)
+	}
+
+	return a.(*ast.Root), errs, nil
+}
+
+// parse is astParser.Parse, recovering from the parser panicking.
+func parse(content []byte, config conf.Config) (root ast.Vertex, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrPanic, r)
+		}
+	}()
+
+	return astParser.Parse(content, config) //nolint:wrapcheck // Wrapped by callers.
+}
+
+// panicError locates the point the parser panicked at, which is right after the
+// last token the lexer produces before it panics as well.
+func (p *parser) panicError(content []byte) *astErrors.Error {
+	var last *token.Token
+	func() {
+		defer func() { _ = recover() }()
+
+		l, err := lexer.New(content, p.config)
+		if err != nil {
+			return
+		}
+
+		for tok := l.Lex(); tok != nil && tok.ID != 0; tok = l.Lex() {
+			last = tok
+		}
+	}()
+
+	if last == nil || last.Position == nil {
+		return astErrors.NewError(
+			"syntax error: unable to parse the code",
+			&position.Position{StartLine: 1, EndLine: 1},
+		)
+	}
+
+	pos := *last.Position
+	return astErrors.NewError(
+		fmt.Sprintf("syntax error: unable to parse the code after %q", last.Value),
+		&pos,
+	)
+}
+
 func (p *parser) Lexer(content []byte) (lexer.Lexer, error) {
 	res, err := lexer.New(content, p.config)
 	if err != nil {
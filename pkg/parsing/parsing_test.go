@@ -0,0 +1,117 @@
+package parsing_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/laytan/php-parser/pkg/position"
+	"github.com/laytan/phpls/pkg/parsing"
+	"github.com/laytan/phpls/pkg/pathutils"
+	"github.com/laytan/phpls/pkg/phpversion"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWithErrors(t *testing.T) {
+	t.Parallel()
+
+	testdata := func(name string) string {
+		content, err := os.ReadFile(filepath.Join(pathutils.Root(), "pkg", "parsing", "testdata", name))
+		require.NoError(t, err)
+
+		return string(content)
+	}
+
+	scenarios := []struct {
+		name     string
+		input    string
+		panics   bool
+		messages []string
+		position *position.Position
+	}{
+		{
+			name:  "empty",
+			input: "",
+		},
+		{
+			name:     "one line incomplete",
+			input:    "<?php echo ?>",
+			messages: []string{"syntax error: unexpected ';'"},
+			position: &position.Position{
+				StartLine: 1,
+				EndLine:   1,
+				StartCol:  11,
+				EndCol:    13,
+				StartPos:  11,
+				EndPos:    13,
+			},
+		},
+		{
+			name: "multi line weird whiles",
+			input: `
+<?php
+    do
+     }   echo 'dowhile test';
+    while (false);
+?>`,
+			panics:   true,
+			messages: []string{`syntax error: unable to parse the code after "do"`},
+			position: &position.Position{
+				StartLine: 3,
+				EndLine:   3,
+				StartCol:  4,
+				EndCol:    6,
+				StartPos:  11,
+				EndPos:    13,
+			},
+		},
+		{
+			name:     "syntax_errors.php",
+			input:    testdata("syntax_errors.php"),
+			messages: []string{"syntax error: unexpected $end"},
+		},
+		{
+			name:     "bad_whiles.php",
+			input:    testdata("bad_whiles.php"),
+			panics:   true,
+			messages: []string{`syntax error: unable to parse the code after ";"`},
+			position: &position.Position{
+				StartLine: 10,
+				EndLine:   10,
+				StartCol:  23,
+				EndCol:    24,
+				StartPos:  163,
+				EndPos:    164,
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, errs, err := parsing.New(phpversion.EightOne()).ParseWithErrors([]byte(scenario.input))
+			if scenario.panics {
+				require.ErrorIs(t, err, parsing.ErrPanic)
+			}
+
+			var messages []string
+			for _, e := range errs {
+				messages = append(messages, e.Msg)
+			}
+			require.Equal(t, scenario.messages, messages)
+
+			if scenario.position != nil {
+				require.Equal(t, scenario.position, errs[0].Pos)
+			}
+		})
+	}
+}
+
+func TestParsePanic(t *testing.T) {
+	t.Parallel()
+
+	_, err := parsing.New(phpversion.EightOne()).Parse([]byte("<?php\ndo\n}"))
+	require.ErrorIs(t, err, parsing.ErrPanic)
+}
@@ -1,6 +1,7 @@
 package phpcbf
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
@@ -8,11 +9,8 @@ import (
 	"log"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"sync"
-
-	"github.com/laytan/go-lsp-protocol/pkg/lsp/protocol"
-	"github.com/laytan/phpls/internal/wrkspc"
-	"github.com/laytan/phpls/pkg/strutil"
 )
 
 // Instance is a wrapper around the 'phpcbf' cli for formatting code.
@@ -99,16 +97,8 @@ func (p *Instance) Format(code []byte) ([]byte, error) {
 		return nil, fmt.Errorf("reading stdout: %w", err)
 	}
 
-	var exitErr *exec.ExitError
-	err = p.cmd.Wait()
-	if err != nil {
-		if !errors.As(err, &exitErr) {
-			return nil, fmt.Errorf("returned non exitError error: %w", err)
-		}
-
-		if !exitErr.Success() && exitErr.ExitCode() != 1 {
-			return nil, fmt.Errorf("%w: %s", exitErr, out)
-		}
+	if err := checkExit(p.cmd.Wait(), out); err != nil {
+		return nil, err
 	}
 
 	if stdinErr != nil {
@@ -118,28 +108,49 @@ func (p *Instance) Format(code []byte) ([]byte, error) {
 	return out, nil
 }
 
-// TODO: should probably not be in this package.
-func (p *Instance) FormatFileEdits(path string) ([]protocol.TextEdit, error) {
-	code := wrkspc.Current.FContentOf(path)
-	lines := len(strutil.Lines(code))
-	formatted, err := p.Format([]byte(code))
-	if err != nil {
-		return nil, fmt.Errorf("formatting %q code: %w", path, err)
-	}
-
-	return []protocol.TextEdit{{
-		Range: protocol.Range{
-			Start: protocol.Position{
-				Line:      0,
-				Character: 0,
-			},
-			End: protocol.Position{
-				Line:      uint32(lines + 1),
-				Character: 0,
-			},
-		},
-		NewText: string(formatted),
-	}}, nil
+// FormatSniffs formats the code, only fixing violations of the given sniffs
+// (for example Generic.Files.LineLength).
+//
+// Because the sniffs are arguments to phpcbf, this does not use the
+// preemptively started process.
+func (p *Instance) FormatSniffs(code []byte, sniffs ...string) ([]byte, error) {
+	if p.executable == "" {
+		return nil, fmt.Errorf("no phpcbf executable found")
+	}
+
+	cmd := exec.Command( // nolint:gosec // p.executable is safe.
+		p.executable,
+		"-q",
+		"--sniffs="+strings.Join(sniffs, ","),
+		"-",
+	)
+	cmd.Stdin = bytes.NewReader(code)
+
+	out, err := cmd.Output()
+	if err := checkExit(err, out); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// checkExit checks the error of a phpcbf command, exit code 1 means code was
+// fixed, which is not an error.
+func checkExit(err error, out []byte) error {
+	if err == nil {
+		return nil
+	}
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return fmt.Errorf("returned non exitError error: %w", err)
+	}
+
+	if !exitErr.Success() && exitErr.ExitCode() != 1 {
+		return fmt.Errorf("%w: %s", exitErr, out)
+	}
+
+	return nil
 }
 
 func (p *Instance) reset() {
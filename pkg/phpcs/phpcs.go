@@ -8,6 +8,7 @@ import (
 	"io"
 	"log"
 	"os/exec"
+	"strings"
 	"sync"
 	"syscall"
 )
@@ -85,6 +86,18 @@ type ReportMessage struct {
 	Column   int
 }
 
+// SniffCode returns the code of the sniff that reported a message, this is the
+// source of the message without the message code:
+// Generic.Files.LineLength.TooLong becomes Generic.Files.LineLength.
+func SniffCode(source string) string {
+	parts := strings.Split(source, ".")
+	if len(parts) <= 3 {
+		return source
+	}
+
+	return strings.Join(parts[:3], ".")
+}
+
 var ErrCancelled = errors.New("cancelled")
 
 func (p *Instance) Check(ctx context.Context, code []byte) (*Report, error) {
@@ -0,0 +1,112 @@
+// Package phpcsfixer wraps the 'php-cs-fixer' cli.
+package phpcsfixer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+type Report struct {
+	Files []*ReportFile `json:"files"`
+}
+
+type ReportFile struct {
+	Name          string   `json:"name"`
+	AppliedFixers []string `json:"appliedFixers"`
+}
+
+type Result struct {
+	// The fixed code, equal to the input if nothing was fixed.
+	Fixed []byte
+	// The names of the rules that changed the code.
+	AppliedFixers []string
+}
+
+var ErrCancelled = errors.New("cancelled")
+
+// Fix writes the content to a temporary file next to the path, so that the
+// configuration (.php-cs-fixer.php) applies to it, and fixes it.
+//
+// If rules are given, only those are applied instead of the configured rules.
+func Fix(
+	ctx context.Context,
+	executable string,
+	path string,
+	content []byte,
+	rules ...string,
+) (*Result, error) {
+	// The random part is put before the name so php-cs-fixer sees a .php file.
+	dir, name := filepath.Split(path)
+	fh, err := os.CreateTemp(dir, ".php-cs-fixer-tmp.*."+name)
+	if err != nil {
+		return nil, fmt.Errorf("creating temp file for php-cs-fixer: %w", err)
+	}
+
+	defer func() {
+		p := fh.Name()
+		if err := fh.Close(); err != nil {
+			log.Println(fmt.Errorf("[ERROR]: php-cs-fixer closing temp file %q: %w", p, err))
+		}
+
+		if err := os.Remove(p); err != nil {
+			log.Println(fmt.Errorf("[ERROR]: php-cs-fixer removing temp file %q: %w", p, err))
+		}
+	}()
+
+	if _, err := fh.Write(content); err != nil {
+		return nil, fmt.Errorf("writing to temp file %q: %w", fh.Name(), err)
+	}
+
+	args := []string{
+		"fix",
+		"--using-cache=no",
+		"--format=json",
+		"--no-interaction",
+		"--show-progress=none",
+	}
+
+	// The rules were applied using the configuration, so risky rules are allowed.
+	if len(rules) > 0 {
+		args = append(args, "--rules="+strings.Join(rules, ","), "--allow-risky=yes")
+	}
+
+	args = append(args, fh.Name())
+
+	cmd := exec.CommandContext(ctx, executable, args...)
+	out, err := cmd.Output()
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		if exitErr.ExitCode() == -1 {
+			return nil, ErrCancelled
+		}
+
+		return nil, fmt.Errorf("running php-cs-fixer: %w: %s", err, exitErr.Stderr)
+	} else if err != nil {
+		return nil, fmt.Errorf("running php-cs-fixer: %w", err)
+	}
+
+	var report Report
+	if err := json.Unmarshal(out, &report); err != nil {
+		return nil, fmt.Errorf("decoding php-cs-fixer output %q: %w", string(out), err)
+	}
+
+	fixed, err := os.ReadFile(fh.Name())
+	if err != nil {
+		return nil, fmt.Errorf("reading fixed temp file %q: %w", fh.Name(), err)
+	}
+
+	result := &Result{Fixed: fixed}
+	for _, file := range report.Files {
+		result.AppliedFixers = append(result.AppliedFixers, file.AppliedFixers...)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,84 @@
+package phpcsfixer_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/laytan/phpls/pkg/pathutils"
+	"github.com/laytan/phpls/pkg/phpcsfixer"
+	"github.com/stretchr/testify/require"
+)
+
+var testdata = filepath.Join(pathutils.Root(), "pkg", "phpcsfixer", "testdata")
+
+func TestFix(t *testing.T) {
+	t.Parallel()
+
+	executable := filepath.Join(testdata, "php-cs-fixer.sh")
+	code := "<?php\n\n$a = 1;  \necho \"a\";\n"
+
+	scenarios := []struct {
+		name    string
+		code    string
+		rules   []string
+		fixed   string
+		applied []string
+	}{
+		{
+			name:    "configured rules",
+			code:    code,
+			fixed:   "<?php\n\n$a = 1;\necho 'a';\n",
+			applied: []string{"no_trailing_whitespace", "single_quote"},
+		},
+		{
+			name:    "given rules",
+			code:    code,
+			rules:   []string{"single_quote"},
+			fixed:   "<?php\n\n$a = 1;  \necho 'a';\n",
+			applied: []string{"single_quote"},
+		},
+		{
+			name:  "nothing to fix",
+			code:  "<?php\n\necho 'a';\n",
+			fixed: "<?php\n\necho 'a';\n",
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			res, err := phpcsfixer.Fix(
+				context.Background(),
+				executable,
+				filepath.Join(dir, "test.php"),
+				[]byte(scenario.code),
+				scenario.rules...,
+			)
+			require.NoError(t, err)
+			require.Equal(t, scenario.fixed, string(res.Fixed))
+			require.Equal(t, scenario.applied, res.AppliedFixers)
+
+			// The temporary file is removed.
+			entries, err := os.ReadDir(dir)
+			require.NoError(t, err)
+			require.Empty(t, entries)
+		})
+	}
+}
+
+func TestFixError(t *testing.T) {
+	t.Parallel()
+
+	_, err := phpcsfixer.Fix(
+		context.Background(),
+		filepath.Join(testdata, "php-cs-fixer_error.sh"),
+		filepath.Join(t.TempDir(), "test.php"),
+		[]byte("<?php\n"),
+	)
+	require.ErrorContains(t, err, `The rules contain unknown fixers: "unknown_rule".`)
+}
@@ -0,0 +1,31 @@
+#!/bin/sh
+# Mimics php-cs-fixer fixing the file, the last argument, with the
+# no_trailing_whitespace and single_quote rules, or only the given --rules.
+rules="no_trailing_whitespace,single_quote"
+for arg; do
+	case "$arg" in
+	--rules=*) rules="${arg#--rules=}" ;;
+	esac
+	path="$arg"
+done
+
+applied=""
+case ",$rules," in *,no_trailing_whitespace,*)
+	if grep -q '[[:space:]]$' "$path"; then
+		sed -i 's/[[:space:]]*$//' "$path"
+		applied="$applied,\"no_trailing_whitespace\""
+	fi
+esac
+
+case ",$rules," in *,single_quote,*)
+	if grep -q '"' "$path"; then
+		sed -i "s/\"/'/g" "$path"
+		applied="$applied,\"single_quote\""
+	fi
+esac
+
+if [ -z "$applied" ]; then
+	echo '{"files":[],"time":{"total":0.011},"memory":14}'
+else
+	printf '{"files":[{"name":"%s","appliedFixers":[%s]}],"time":{"total":0.011},"memory":14}\n' "$path" "${applied#,}"
+fi
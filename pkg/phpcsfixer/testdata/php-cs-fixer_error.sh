@@ -0,0 +1,4 @@
+#!/bin/sh
+# Mimics php-cs-fixer failing because of an invalid configuration.
+echo "The rules contain unknown fixers: \"unknown_rule\"." >&2
+exit 16
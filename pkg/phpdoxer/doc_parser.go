@@ -3,13 +3,14 @@ package phpdoxer
 import (
 	"regexp"
 	"strings"
+	"unicode"
 
 	"github.com/laytan/phpls/pkg/phpversion"
 	"github.com/laytan/phpls/pkg/strutil"
 )
 
 var (
-	groupRgx      = regexp.MustCompile(`@(\w+)\s*([^@]*)`)
+	groupRgx      = regexp.MustCompile(`@([\w-]+)\s*([^@]*)`)
 	emptyLineRgx  = regexp.MustCompile(`[^*/\s]`)
 	whitespaceRgx = regexp.MustCompile(`\n(\s+)`)
 )
@@ -163,6 +164,9 @@ func parseGroup(g *group) (Node, error) {
 		} else if isStrVariable(typeOrNameRest) {
 			nameStr = typeOrNameRest
 			typeNode, _ = ParseType(typeOrName)
+			if typeNode == nil {
+				typeNode = &TypeUnknown{Value: typeOrName}
+			}
 			descStr = desc
 		} else {
 			descStr = value
@@ -225,6 +229,94 @@ func parseGroup(g *group) (Node, error) {
 		}
 		return result, nil
 
+	case "deprecated":
+		version, description := splitTypeAndRest(value)
+		if phpv, ok := phpversion.FromString(version); ok {
+			result = &NodeDeprecated{
+				Version:     phpv,
+				Description: description,
+			}
+			return result, nil
+		}
+
+		result = &NodeDeprecated{
+			Description: value,
+		}
+		return result, nil
+
+	case "template", "template-covariant":
+		name, rest := splitTypeAndRest(value)
+		template := &NodeTemplate{
+			Name:        name,
+			Covariant:   g.at == "template-covariant",
+			Description: rest,
+		}
+
+		if of, ok := strings.CutPrefix(rest, "of "); ok {
+			typeStr, description := splitTypeAndRest(of)
+			template.Of, _ = ParseType(typeStr)
+			if template.Of == nil {
+				template.Of = &TypeUnknown{Value: typeStr}
+			}
+
+			template.Description = description
+		}
+
+		result = template
+		return result, nil
+
+	case "extends", "template-extends":
+		typeNode, description := parseInheritedType(value)
+		result = &NodeExtends{Type: typeNode, Description: description}
+		return result, nil
+
+	case "implements", "template-implements":
+		typeNode, description := parseInheritedType(value)
+		result = &NodeImplements{Type: typeNode, Description: description}
+		return result, nil
+
+	case "use", "template-use":
+		typeNode, description := parseInheritedType(value)
+		result = &NodeUse{Type: typeNode, Description: description}
+		return result, nil
+
+	case "mixin":
+		typeNode, description := parseInheritedType(value)
+		result = &NodeMixin{Type: typeNode, Description: description}
+		return result, nil
+
+	case "property", "property-read", "property-write":
+		property := &NodeProperty{
+			ReadOnly:  g.at == "property-read",
+			WriteOnly: g.at == "property-write",
+		}
+
+		typeOrName, rest := splitTypeAndRest(value)
+		if !isStrVariable(typeOrName) {
+			property.Type, _ = ParseType(typeOrName)
+			if property.Type == nil {
+				property.Type = &TypeUnknown{Value: typeOrName}
+			}
+
+			typeOrName, rest = splitTypeAndRest(rest)
+		}
+
+		property.Name, property.Description = typeOrName, rest
+		result = property
+		return result, nil
+
+	case "method":
+		if method, ok := parseMethod(value); ok {
+			result = method
+			return result, nil
+		}
+
+		result = &NodeUnknown{
+			At:    g.at,
+			Value: value,
+		}
+		return result, nil
+
 	default:
 		result = &NodeUnknown{
 			At:    g.at,
@@ -234,6 +326,93 @@ func parseGroup(g *group) (Node, error) {
 	}
 }
 
+// parseMethod parses the value of a @method tag, following PHPStan:
+// "@method static foo()" is a method returning static, not a static method.
+func parseMethod(value string) (*NodeMethod, bool) {
+	method := &NodeMethod{}
+
+	if rest, ok := strings.CutPrefix(value, "static "); ok && !strings.HasPrefix(rest, "(") {
+		method.Static = true
+		value = rest
+	}
+
+	returnOrSignature, rest := splitTypeAndRest(value)
+	signature := returnOrSignature
+	if !isMethodSignature(returnOrSignature) {
+		signature, rest = splitTypeAndRest(rest)
+		if !isMethodSignature(signature) {
+			return nil, false
+		}
+
+		method.Return, _ = ParseType(returnOrSignature)
+		if method.Return == nil {
+			method.Return = &TypeUnknown{Value: returnOrSignature}
+		}
+	} else if method.Static {
+		method.Static = false
+		method.Return = &TypeClassLike{Name: "static"}
+	}
+
+	name, params, _ := strings.Cut(signature, "(")
+	method.Name = name
+	method.Description = rest
+
+	params = strings.TrimSpace(strings.TrimSuffix(params, ")"))
+	if params == "" {
+		return method, true
+	}
+
+	for _, param := range splitGenerics(params) {
+		param, def, _ := strings.Cut(strings.TrimSpace(param), "=")
+		typeOrName, name := splitTypeAndRest(param)
+
+		methodParam := &MethodParam{
+			Name:    strings.TrimSpace(name),
+			Default: strings.TrimSpace(def),
+		}
+
+		if isStrVariable(typeOrName) {
+			methodParam.Name = typeOrName
+		} else {
+			methodParam.Type, _ = ParseType(typeOrName)
+			if methodParam.Type == nil {
+				methodParam.Type = &TypeUnknown{Value: typeOrName}
+			}
+		}
+
+		method.Params = append(method.Params, methodParam)
+	}
+
+	return method, true
+}
+
+// isMethodSignature returns whether the value is a method name followed by
+// its parameters, like "foo(int $bar)".
+func isMethodSignature(value string) bool {
+	name, _, ok := strings.Cut(value, "(")
+	if !ok || name == "" || !strings.HasSuffix(value, ")") {
+		return false
+	}
+
+	for _, r := range name {
+		if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func parseInheritedType(value string) (Type, string) {
+	typeStr, description := splitTypeAndRest(value)
+	typeNode, _ := ParseType(typeStr)
+	if typeNode == nil {
+		typeNode = &TypeUnknown{Value: typeStr}
+	}
+
+	return typeNode, description
+}
+
 func cleanGroupValue(value string) string {
 	lines := strutil.Lines(value)
 	outLines := make([]string, 0, len(lines))
@@ -255,14 +434,58 @@ func cleanGroupValue(value string) string {
 	return strings.Join(outLines, "\n")
 }
 
+// splitTypeAndRest splits the value at the first whitespace that is not part of
+// the type, types can contain whitespace inside brackets (array<int, string>)
+// or around operators (Foo | Bar, callable(): void).
 func splitTypeAndRest(value string) (docType string, rest string) {
-	split := strings.Fields(value)
-	if len(split) == 0 {
-		return value, ""
+	value = strings.TrimSpace(value)
+
+	depth := 0
+	for i, r := range value {
+		switch r {
+		case '<', '(', '{', '[':
+			depth++
+			continue
+		case '>', ')', '}', ']':
+			depth--
+			continue
+		}
+
+		if depth > 0 || !unicode.IsSpace(r) {
+			continue
+		}
+
+		before := strings.TrimRightFunc(value[:i], unicode.IsSpace)
+		after := strings.TrimLeftFunc(value[i:], unicode.IsSpace)
+		if continuesType(before, after) {
+			continue
+		}
+
+		return value[:i], after
 	}
 
-	rest = strings.TrimSpace(strings.TrimPrefix(value, split[0]))
-	return split[0], rest
+	return value, ""
+}
+
+// continuesType returns whether the type continues after the whitespace
+// between before and after, for example: "Foo | Bar" or "callable(): void".
+func continuesType(before string, after string) bool {
+	switch {
+	case strings.HasSuffix(before, "|"), strings.HasSuffix(before, "&"):
+		return true
+	case strings.HasSuffix(before, ":"):
+		return strings.Contains(before, "(")
+	case strings.HasPrefix(after, "|"):
+		return true
+	case strings.HasPrefix(after, "&"):
+		// A by reference parameter: "array &$foo" or "int &...$foo".
+		rest := strings.TrimPrefix(after, "&")
+		return !strings.HasPrefix(rest, "$") && !strings.HasPrefix(rest, "...")
+	case strings.HasPrefix(after, ":"):
+		return strings.HasSuffix(before, ")")
+	default:
+		return false
+	}
 }
 
 func isStrVariable(value string) bool {
@@ -6,6 +6,7 @@ import (
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/laytan/phpls/pkg/phpdoxer"
+	"github.com/laytan/phpls/pkg/phpversion"
 	"github.com/stretchr/testify/require"
 )
 
@@ -72,6 +73,19 @@ func TestParseDoc(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "at return with whitespace in type",
+			args: "// @return array<int, string> HelloWorld",
+			want: []phpdoxer.Node{
+				&phpdoxer.NodeReturn{
+					Type: &phpdoxer.TypeArray{
+						KeyType:  &phpdoxer.TypeInt{},
+						ItemType: &phpdoxer.TypeString{},
+					},
+					Description: "HelloWorld",
+				},
+			},
+		},
 		{
 			name: "at return with description on line below",
 			args: `
@@ -220,6 +234,201 @@ func TestParseDoc(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "deprecated",
+			args: `
+            /**
+             * @deprecated
+             */
+            `,
+			want: []phpdoxer.Node{
+				&phpdoxer.NodeDeprecated{},
+			},
+		},
+		{
+			name: "deprecated with version and description",
+			args: `
+            /**
+             * @deprecated 8.1 Use something else.
+             */
+            `,
+			want: []phpdoxer.Node{
+				&phpdoxer.NodeDeprecated{
+					Version:     &phpversion.PHPVersion{Major: 8, Minor: 1},
+					Description: "Use something else.",
+				},
+			},
+		},
+		{
+			name: "deprecated with description",
+			args: `
+            /**
+             * @deprecated Use something else.
+             */
+            `,
+			want: []phpdoxer.Node{
+				&phpdoxer.NodeDeprecated{
+					Description: "Use something else.",
+				},
+			},
+		},
+		{
+			name: "templates",
+			args: `
+            /**
+             * @template T
+             * @template-covariant TValue of \Foo\Bar The value.
+             */
+            `,
+			want: []phpdoxer.Node{
+				&phpdoxer.NodeTemplate{
+					Name: "T",
+				},
+				&phpdoxer.NodeTemplate{
+					Name:        "TValue",
+					Of:          &phpdoxer.TypeClassLike{Name: `\Foo\Bar`, FullyQualified: true},
+					Covariant:   true,
+					Description: "The value.",
+				},
+			},
+		},
+		{
+			name: "inherited templates",
+			args: `
+            /**
+             * @extends Collection<int, Foo>
+             * @implements Iterator<Foo> The iterator.
+             * @use Items<Foo>
+             */
+            `,
+			want: []phpdoxer.Node{
+				&phpdoxer.NodeExtends{
+					Type: &phpdoxer.TypeClassLike{
+						Name:        "Collection",
+						GenericOver: []phpdoxer.Type{&phpdoxer.TypeInt{}, &phpdoxer.TypeClassLike{Name: "Foo"}},
+					},
+				},
+				&phpdoxer.NodeImplements{
+					Type: &phpdoxer.TypeClassLike{
+						Name:        "Iterator",
+						GenericOver: []phpdoxer.Type{&phpdoxer.TypeClassLike{Name: "Foo"}},
+					},
+					Description: "The iterator.",
+				},
+				&phpdoxer.NodeUse{
+					Type: &phpdoxer.TypeClassLike{
+						Name:        "Items",
+						GenericOver: []phpdoxer.Type{&phpdoxer.TypeClassLike{Name: "Foo"}},
+					},
+				},
+			},
+		},
+		{
+			name: "mixins",
+			args: `
+            /**
+             * @mixin \Foo\Builder<static> Forwarded with __call.
+             * @mixin Bar
+             */
+            `,
+			want: []phpdoxer.Node{
+				&phpdoxer.NodeMixin{
+					Type: &phpdoxer.TypeClassLike{
+						Name:           `\Foo\Builder`,
+						FullyQualified: true,
+						GenericOver:    []phpdoxer.Type{&phpdoxer.TypeClassLike{Name: "static"}},
+					},
+					Description: "Forwarded with __call.",
+				},
+				&phpdoxer.NodeMixin{
+					Type: &phpdoxer.TypeClassLike{Name: "Bar"},
+				},
+			},
+		},
+		{
+			name: "properties",
+			args: `
+            /**
+             * @property string $name The name.
+             * @property-read int $id
+             * @property-write array<string, mixed> $attributes
+             * @property $untyped
+             */
+            `,
+			want: []phpdoxer.Node{
+				&phpdoxer.NodeProperty{
+					Type:        &phpdoxer.TypeString{},
+					Name:        "$name",
+					Description: "The name.",
+				},
+				&phpdoxer.NodeProperty{
+					Type:     &phpdoxer.TypeInt{},
+					Name:     "$id",
+					ReadOnly: true,
+				},
+				&phpdoxer.NodeProperty{
+					Type: &phpdoxer.TypeArray{
+						KeyType:  &phpdoxer.TypeString{},
+						ItemType: &phpdoxer.TypeMixed{},
+					},
+					Name:      "$attributes",
+					WriteOnly: true,
+				},
+				&phpdoxer.NodeProperty{
+					Name: "$untyped",
+				},
+			},
+		},
+		{
+			name: "methods",
+			args: `
+            /**
+             * @method Foo foo(int $bar, array<int, string> $baz = [], ...$rest) Does foo.
+             * @method static Builder where(string $column)
+             * @method static fresh()
+             * @method bar()
+             * @method invalid
+             */
+            `,
+			want: []phpdoxer.Node{
+				&phpdoxer.NodeMethod{
+					Return: &phpdoxer.TypeClassLike{Name: "Foo"},
+					Name:   "foo",
+					Params: []*phpdoxer.MethodParam{
+						{Type: &phpdoxer.TypeInt{}, Name: "$bar"},
+						{
+							Type: &phpdoxer.TypeArray{
+								KeyType:  &phpdoxer.TypeInt{},
+								ItemType: &phpdoxer.TypeString{},
+							},
+							Name:    "$baz",
+							Default: "[]",
+						},
+						{Name: "...$rest"},
+					},
+					Description: "Does foo.",
+				},
+				&phpdoxer.NodeMethod{
+					Static: true,
+					Return: &phpdoxer.TypeClassLike{Name: "Builder"},
+					Name:   "where",
+					Params: []*phpdoxer.MethodParam{
+						{Type: &phpdoxer.TypeString{}, Name: "$column"},
+					},
+				},
+				&phpdoxer.NodeMethod{
+					Return: &phpdoxer.TypeClassLike{Name: "static"},
+					Name:   "fresh",
+				},
+				&phpdoxer.NodeMethod{
+					Name: "bar",
+				},
+				&phpdoxer.NodeUnknown{
+					At:    "method",
+					Value: "invalid",
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
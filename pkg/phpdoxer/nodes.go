@@ -18,6 +18,14 @@ const (
 	KindSince
 	KindRemoved
 	KindThrows
+	KindDeprecated
+	KindTemplate
+	KindExtends
+	KindImplements
+	KindUse
+	KindProperty
+	KindMethod
+	KindMixin
 )
 
 type Node interface {
@@ -180,3 +188,211 @@ func (n *NodeThrows) String() string {
 func (n *NodeThrows) Kind() NodeKind {
 	return KindThrows
 }
+
+type NodeDeprecated struct {
+	NodeRange
+
+	// The version the element was deprecated in, nil if not given.
+	Version     *phpversion.PHPVersion
+	Description string
+}
+
+func (n *NodeDeprecated) String() string {
+	if n.Version == nil {
+		return strings.TrimSpace("@deprecated " + n.Description)
+	}
+
+	return strings.TrimSpace("@deprecated " + n.Version.String() + " " + n.Description)
+}
+
+func (n *NodeDeprecated) Kind() NodeKind {
+	return KindDeprecated
+}
+
+type NodeTemplate struct {
+	NodeRange
+
+	Name string
+	// The bound of the template, nil if not given: "@template T of Foo".
+	Of          Type
+	Covariant   bool
+	Description string
+}
+
+func (n *NodeTemplate) String() string {
+	at := "@template"
+	if n.Covariant {
+		at += "-covariant"
+	}
+
+	res := at + " " + n.Name
+	if n.Of != nil {
+		res += " of " + n.Of.String()
+	}
+
+	return strings.TrimSpace(res + " " + n.Description)
+}
+
+func (n *NodeTemplate) Kind() NodeKind {
+	return KindTemplate
+}
+
+// NodeExtends binds the templates of the extended class: "@extends Foo<Bar>".
+type NodeExtends struct {
+	NodeRange
+
+	Type        Type
+	Description string
+}
+
+func (n *NodeExtends) String() string {
+	return strings.TrimSpace(fmt.Sprintf("@extends %s %s", n.Type, n.Description))
+}
+
+func (n *NodeExtends) Kind() NodeKind {
+	return KindExtends
+}
+
+// NodeImplements binds the templates of an implemented interface: "@implements Foo<Bar>".
+type NodeImplements struct {
+	NodeRange
+
+	Type        Type
+	Description string
+}
+
+func (n *NodeImplements) String() string {
+	return strings.TrimSpace(fmt.Sprintf("@implements %s %s", n.Type, n.Description))
+}
+
+func (n *NodeImplements) Kind() NodeKind {
+	return KindImplements
+}
+
+// NodeUse binds the templates of a used trait: "@use Foo<Bar>".
+type NodeUse struct {
+	NodeRange
+
+	Type        Type
+	Description string
+}
+
+func (n *NodeUse) String() string {
+	return strings.TrimSpace(fmt.Sprintf("@use %s %s", n.Type, n.Description))
+}
+
+func (n *NodeUse) Kind() NodeKind {
+	return KindUse
+}
+
+// NodeMixin delegates the members of another class to the class: "@mixin Foo".
+type NodeMixin struct {
+	NodeRange
+
+	Type        Type
+	Description string
+}
+
+func (n *NodeMixin) String() string {
+	return strings.TrimSpace(fmt.Sprintf("@mixin %s %s", n.Type, n.Description))
+}
+
+func (n *NodeMixin) Kind() NodeKind {
+	return KindMixin
+}
+
+// NodeProperty declares a virtual property on a class: "@property Foo $foo",
+// "@property-read Foo $foo" or "@property-write Foo $foo".
+type NodeProperty struct {
+	NodeRange
+
+	// The type of the property, nil if not given.
+	Type Type
+	// The name of the property, including the $.
+	Name        string
+	ReadOnly    bool
+	WriteOnly   bool
+	Description string
+}
+
+func (n *NodeProperty) String() string {
+	at := "@property"
+	switch {
+	case n.ReadOnly:
+		at += "-read"
+	case n.WriteOnly:
+		at += "-write"
+	}
+
+	if n.Type == nil {
+		return strings.TrimSpace(fmt.Sprintf("%s %s %s", at, n.Name, n.Description))
+	}
+
+	return strings.TrimSpace(fmt.Sprintf("%s %s %s %s", at, n.Type, n.Name, n.Description))
+}
+
+func (n *NodeProperty) Kind() NodeKind {
+	return KindProperty
+}
+
+// NodeMethod declares a virtual method on a class: "@method static Foo foo(int $bar)".
+type NodeMethod struct {
+	NodeRange
+
+	Static bool
+	// The return type of the method, nil if not given.
+	Return      Type
+	Name        string
+	Params      []*MethodParam
+	Description string
+}
+
+// MethodParam is a parameter of a virtual method.
+type MethodParam struct {
+	// The type of the parameter, nil if not given.
+	Type Type
+	// The name of the parameter, including the $ and any & or ... prefix.
+	Name string
+	// The default value of the parameter, empty if not given.
+	Default string
+}
+
+func (p *MethodParam) String() string {
+	res := p.Name
+	if p.Type != nil {
+		res = p.Type.String() + " " + res
+	}
+
+	if p.Default != "" {
+		res += " = " + p.Default
+	}
+
+	return res
+}
+
+// Signature returns the method declaration, like "foo(int $bar)".
+func (n *NodeMethod) Signature() string {
+	params := make([]string, 0, len(n.Params))
+	for _, param := range n.Params {
+		params = append(params, param.String())
+	}
+
+	return fmt.Sprintf("%s(%s)", n.Name, strings.Join(params, ", "))
+}
+
+func (n *NodeMethod) String() string {
+	res := "@method"
+	if n.Static {
+		res += " static"
+	}
+
+	if n.Return != nil {
+		res += " " + n.Return.String()
+	}
+
+	return strings.TrimSpace(res + " " + n.Signature() + " " + n.Description)
+}
+
+func (n *NodeMethod) Kind() NodeKind {
+	return KindMethod
+}
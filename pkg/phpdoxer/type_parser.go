@@ -840,7 +840,7 @@ func parseGenericClass(value string) (bool, Type, error) {
 	name := match[genClsRgxNameG]
 	fullyQualified := name[0:1] == namespaceSeperator
 
-	rawGenOver := strings.Split(match[genClsRgxGenOverG], typeSeperator)
+	rawGenOver := splitGenerics(match[genClsRgxGenOverG])
 	genOver := make([]Type, 0, len(rawGenOver))
 	for _, v := range rawGenOver {
 		parsed, err := ParseType(v)
@@ -858,6 +858,28 @@ func parseGenericClass(value string) (bool, Type, error) {
 	}, nil
 }
 
+// splitGenerics splits the generics of a class at the separators that are not
+// nested inside another type, like: "int, array<int, string>".
+func splitGenerics(value string) []string {
+	var res []string
+	depth, start := 0, 0
+	for i, r := range value {
+		switch r {
+		case '<', '(', '{':
+			depth++
+		case '>', ')', '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				res = append(res, value[start:i])
+				start = i + 1
+			}
+		}
+	}
+
+	return append(res, value[start:])
+}
+
 func parseNullable(value string) (bool, Type, error) {
 	match := nullableRegex.FindStringSubmatch(value)
 	if len(match) < nullableeTypG+1 {
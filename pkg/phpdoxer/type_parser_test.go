@@ -669,6 +669,21 @@ func TestParse(t *testing.T) {
 			},
 			wantEqualStrings: true,
 		},
+		{
+			name: "generic class nested",
+			args: `Collection<int, array<int, Foo>>`,
+			want: &phpdoxer.TypeClassLike{
+				Name: "Collection",
+				GenericOver: []phpdoxer.Type{
+					&phpdoxer.TypeInt{},
+					&phpdoxer.TypeArray{
+						KeyType:  &phpdoxer.TypeInt{},
+						ItemType: &phpdoxer.TypeClassLike{Name: "Foo"},
+					},
+				},
+			},
+			wantEqualStrings: true,
+		},
 		{
 			name: "short nullable",
 			args: "?bool",
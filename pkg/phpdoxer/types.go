@@ -7,7 +7,6 @@ import (
 	"github.com/laytan/phpls/pkg/functional"
 )
 
-// TODO: support phpstan's generics: https://phpstan.org/writing-php-code/phpdoc-types#generics.
 // TODO: support phpstan's conditional return types: https://phpstan.org/writing-php-code/phpdoc-types#conditional-return-types.
 
 const (
@@ -0,0 +1,181 @@
+package phpdoxer
+
+// Walk calls visit with the given type and recursively with every type it is
+// composed of, depth first. If visit returns false, the children of that type
+// are not walked.
+func Walk(typ Type, visit func(Type) bool) {
+	if typ == nil || !visit(typ) {
+		return
+	}
+
+	switch typed := typ.(type) {
+	case *TypeClassLike:
+		for _, gen := range typed.GenericOver {
+			Walk(gen, visit)
+		}
+	case *TypeArray:
+		Walk(typed.KeyType, visit)
+		Walk(typed.ItemType, visit)
+	case *TypeIterable:
+		Walk(typed.KeyType, visit)
+		Walk(typed.ItemType, visit)
+	case *TypeCallable:
+		for _, param := range typed.Parameters {
+			Walk(param.Type, visit)
+		}
+		Walk(typed.Return, visit)
+	case *TypePrecedence:
+		Walk(typed.Type, visit)
+	case *TypeUnion:
+		Walk(typed.Left, visit)
+		Walk(typed.Right, visit)
+	case *TypeIntersection:
+		Walk(typed.Left, visit)
+		Walk(typed.Right, visit)
+	case *TypeKeyOf:
+		walkClassLike(typed.Class, visit)
+	case *TypeValueOf:
+		walkClassLike(typed.Class, visit)
+	case *TypeArrayShape:
+		for _, value := range typed.Values {
+			Walk(value, visit)
+		}
+	case *TypeArrayShapeValue:
+		Walk(typed.Type, visit)
+	case *TypeConstant:
+		walkClassLike(typed.Class, visit)
+	case *TypeIntMaskOf:
+		Walk(typed.Type, visit)
+	case *TypeConditionalReturn:
+		if typed.Condition != nil {
+			Walk(typed.Condition.Right, visit)
+		}
+		Walk(typed.IfTrue, visit)
+		Walk(typed.IfFalse, visit)
+	case *TypeGenericTemplate:
+		walkClassLike(typed.Of, visit)
+	}
+}
+
+// ClassLikes returns all the class-like types the given type is composed of.
+func ClassLikes(typ Type) (res []*TypeClassLike) {
+	Walk(typ, func(t Type) bool {
+		if cls, ok := t.(*TypeClassLike); ok {
+			res = append(res, cls)
+		}
+
+		return true
+	})
+
+	return res
+}
+
+// The class fields are typed *TypeClassLike, which can be nil, passing a nil
+// *TypeClassLike as a Type would result in a non-nil interface.
+func walkClassLike(cls *TypeClassLike, visit func(Type) bool) {
+	if cls != nil {
+		Walk(cls, visit)
+	}
+}
+
+// Replace returns a copy of the given type where every class-like it is
+// composed of is replaced by the result of replace, class-likes are kept if
+// replace returns nil. Constants without a class are passed as class-likes,
+// because single letter names like T are parsed as constants.
+//
+// The generics of a class-like are replaced before the class-like itself.
+func Replace(typ Type, replace func(*TypeClassLike) Type) Type {
+	switch typed := typ.(type) {
+	case *TypeClassLike:
+		cls := &TypeClassLike{Name: typed.Name, FullyQualified: typed.FullyQualified}
+		for _, gen := range typed.GenericOver {
+			cls.GenericOver = append(cls.GenericOver, Replace(gen, replace))
+		}
+
+		if res := replace(cls); res != nil {
+			return res
+		}
+
+		return cls
+	case *TypeConstant:
+		if typed.Class != nil {
+			return typed
+		}
+
+		if res := replace(&TypeClassLike{Name: typed.Const}); res != nil {
+			return res
+		}
+
+		return typed
+	case *TypeString:
+		if typed.GenericOver == nil {
+			return typed
+		}
+
+		// Only a class-like can be the generic of a class-string.
+		res, ok := Replace(typed.GenericOver, replace).(*TypeClassLike)
+		if !ok {
+			return &TypeString{Constraint: typed.Constraint}
+		}
+
+		return &TypeString{Constraint: typed.Constraint, GenericOver: res}
+	case *TypeArray:
+		return &TypeArray{
+			KeyType:  replaceOptional(typed.KeyType, replace),
+			ItemType: replaceOptional(typed.ItemType, replace),
+			NonEmpty: typed.NonEmpty,
+		}
+	case *TypeIterable:
+		return &TypeIterable{
+			KeyType:  replaceOptional(typed.KeyType, replace),
+			ItemType: replaceOptional(typed.ItemType, replace),
+		}
+	case *TypeCallable:
+		if typed.Return == nil {
+			return typed
+		}
+
+		callable := &TypeCallable{Return: Replace(typed.Return, replace)}
+		for _, param := range typed.Parameters {
+			p := *param
+			p.Type = Replace(param.Type, replace)
+			callable.Parameters = append(callable.Parameters, &p)
+		}
+
+		return callable
+	case *TypePrecedence:
+		return &TypePrecedence{Type: Replace(typed.Type, replace)}
+	case *TypeUnion:
+		return &TypeUnion{Left: Replace(typed.Left, replace), Right: Replace(typed.Right, replace)}
+	case *TypeIntersection:
+		return &TypeIntersection{
+			Left:  Replace(typed.Left, replace),
+			Right: Replace(typed.Right, replace),
+		}
+	case *TypeArrayShape:
+		shape := &TypeArrayShape{}
+		for _, value := range typed.Values {
+			v := *value
+			v.Type = Replace(value.Type, replace)
+			shape.Values = append(shape.Values, &v)
+		}
+
+		return shape
+	case *TypeConditionalReturn:
+		return &TypeConditionalReturn{
+			Condition: typed.Condition,
+			IfTrue:    Replace(typed.IfTrue, replace),
+			IfFalse:   Replace(typed.IfFalse, replace),
+		}
+	default:
+		return typ
+	}
+}
+
+func replaceOptional(typ Type, replace func(*TypeClassLike) Type) Type {
+	if typ == nil {
+		return nil
+	}
+
+	return Replace(typ, replace)
+}
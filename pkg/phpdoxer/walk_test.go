@@ -0,0 +1,135 @@
+package phpdoxer_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/laytan/phpls/pkg/functional"
+	"github.com/laytan/phpls/pkg/phpdoxer"
+)
+
+func TestClassLikes(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		args string
+		want []string
+	}{
+		{
+			name: "none",
+			args: "array<int, string>",
+		},
+		{
+			name: "single",
+			args: "Foo",
+			want: []string{"Foo"},
+		},
+		{
+			name: "union",
+			args: "Foo|\\Bar\\Baz|null",
+			want: []string{"Foo", "\\Bar\\Baz"},
+		},
+		{
+			name: "generics",
+			args: "Collection<int, Foo>",
+			want: []string{"Collection", "Foo"},
+		},
+		{
+			name: "nested",
+			args: "array{foo: Foo, bar?: Bar[]}",
+			want: []string{"Foo", "Bar"},
+		},
+		{
+			name: "callable",
+			args: "callable(Foo, int): Bar",
+			want: []string{"Foo", "Bar"},
+		},
+		{
+			name: "key of",
+			args: "key-of<Foo::BAR>",
+			want: []string{"Foo"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			typ, err := phpdoxer.ParseType(tt.args)
+			if err != nil {
+				t.Fatalf("ParseType(%q) error = %v", tt.args, err)
+			}
+
+			got := functional.Map(
+				phpdoxer.ClassLikes(typ),
+				func(cls *phpdoxer.TypeClassLike) string { return cls.Name },
+			)
+			if len(got) == 0 && len(tt.want) == 0 {
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ClassLikes(%q) = %v, want %v", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReplace(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		args string
+		want string
+	}{
+		{
+			name: "class-like",
+			args: "T",
+			want: `\Foo`,
+		},
+		{
+			name: "nested",
+			args: "array<int, TValue>|Collection<TKey, TValue>|null",
+			want: `array<int, \Foo>|Collection<int, \Foo>|null`,
+		},
+		{
+			name: "class string",
+			args: "class-string<TValue>",
+			want: `class-string<\Foo>`,
+		},
+		{
+			name: "unbound",
+			args: "Bar",
+			want: "Bar",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			typ, err := phpdoxer.ParseType(tt.args)
+			if err != nil {
+				t.Fatalf("ParseType(%q) error = %v", tt.args, err)
+			}
+
+			got := phpdoxer.Replace(typ, func(cls *phpdoxer.TypeClassLike) phpdoxer.Type {
+				switch cls.Name {
+				case "T", "TValue":
+					return &phpdoxer.TypeClassLike{Name: `\Foo`, FullyQualified: true}
+				case "TKey":
+					return &phpdoxer.TypeInt{}
+				default:
+					return nil
+				}
+			})
+			if got.String() != tt.want {
+				t.Errorf("Replace(%q) = %v, want %v", tt.args, got, tt.want)
+			}
+
+			if typ.String() != tt.args {
+				t.Errorf("Replace(%q) modified the given type to %v", tt.args, typ)
+			}
+		})
+	}
+}
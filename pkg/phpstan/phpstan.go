@@ -9,6 +9,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sync"
 )
 
 type Report struct {
@@ -27,10 +28,58 @@ type ReportMessage struct {
 	Msg       string `json:"message"`
 	Ln        int    `json:"line"`
 	Ignorable bool
+	// The error identifier, for example method.notFound, empty on older versions.
+	Identifier string `json:"identifier"`
+	// A hint on how to solve the error, empty if there is none.
+	Tip string `json:"tip"`
 }
 
 var ErrCancelled = errors.New("cancelled")
 
+// tmpLocks guards the temporary file of each path, the previous (cancelled)
+// run might still be removing it. Entries are removed when no run uses them.
+var (
+	tmpLocks   = map[string]*tmpLock{}
+	tmpLocksMu sync.Mutex
+)
+
+type tmpLock struct {
+	sync.Mutex
+	// The amount of runs holding or waiting on the lock.
+	refs int
+}
+
+// lockTmp locks the temporary file at path, the returned function unlocks it.
+func lockTmp(path string) (unlock func()) {
+	tmpLocksMu.Lock()
+	lock, ok := tmpLocks[path]
+	if !ok {
+		lock = &tmpLock{}
+		tmpLocks[path] = lock
+	}
+	lock.refs++
+	tmpLocksMu.Unlock()
+
+	lock.Lock()
+	return func() {
+		lock.Unlock()
+
+		tmpLocksMu.Lock()
+		defer tmpLocksMu.Unlock()
+
+		lock.refs--
+		if lock.refs == 0 {
+			delete(tmpLocks, path)
+		}
+	}
+}
+
+// Analyze writes the content to a temporary file next to the path and
+// analyzes it.
+//
+// The temporary file has the same name every run, phpstan invalidates its
+// result cache when the analysed paths change, so a stable name lets
+// subsequent runs reuse the results of the files that did not change.
 func Analyze(
 	ctx context.Context,
 	executable string,
@@ -38,28 +87,27 @@ func Analyze(
 	content []byte,
 ) ([]*ReportMessage, error) {
 	dir, name := filepath.Split(path)
-	fh, err := os.CreateTemp(dir, ".phpstan-tmp."+name)
-	if err != nil {
-		return nil, fmt.Errorf("creating temp file for phpstan: %w", err)
+	tmp := filepath.Join(dir, ".phpstan-tmp."+name)
+
+	unlock := lockTmp(tmp)
+	defer unlock()
+
+	// Cancelled while waiting on the previous run.
+	if ctx.Err() != nil {
+		return nil, ErrCancelled
 	}
 
-	if _, err := fh.Write(content); err != nil {
-		return nil, fmt.Errorf("writing to temp file %q: %w", fh.Name(), err)
+	if err := os.WriteFile(tmp, content, 0o600); err != nil {
+		return nil, fmt.Errorf("writing to temp file %q: %w", tmp, err)
 	}
 
 	defer func() {
-		go func() {
-			p := fh.Name()
-			if err := fh.Close(); err != nil {
-				log.Println(fmt.Errorf("[ERROR]: phpstan closing temp file %q: %w", p, err))
-			}
-			if err := os.Remove(fh.Name()); err != nil {
-				log.Println(fmt.Errorf("[ERROR]: phpstan removing temp file %q: %w", p, err))
-			}
-		}()
+		if err := os.Remove(tmp); err != nil {
+			log.Println(fmt.Errorf("[ERROR]: phpstan removing temp file %q: %w", tmp, err))
+		}
 	}()
 
-	return AnalyzePath(ctx, executable, fh.Name())
+	return AnalyzePath(ctx, executable, tmp)
 }
 
 func AnalyzePath(ctx context.Context, executable string, path string) ([]*ReportMessage, error) {
@@ -0,0 +1,6 @@
+#!/bin/sh
+# Mimics phpstan reporting the messages of report.json for the analyzed file,
+# the last argument.
+for path; do :; done
+sed "s#FILE_PATH#$path#g" "$(dirname "$0")/report.json"
+exit 1
@@ -0,0 +1,38 @@
+<?php
+
+namespace App;
+
+class User
+{
+    public function name(): string
+    {
+        return $this->nmae;
+    }
+
+    public function greet(Greeter $greeter): string
+    {
+        return $greeter->greetUser($this, undefined_function());
+    }
+
+    public function count(): int
+    {
+        $total = 0;
+        return $totl;
+    }
+
+    public function create(): static
+    {
+        return new Usr();
+    }
+
+    public function constant(): int
+    {
+        return self::MISSING;
+    }
+
+    public function unreachable(): int
+    {
+        return 1;
+        echo 'unreachable';
+    }
+}
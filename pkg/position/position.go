@@ -77,6 +77,41 @@ func (p *Position) ToIRPosition(content string) *position.Position {
 	}
 }
 
+// ToLSPPosition converts the byte offset into content to a LSP position.
+func ToLSPPosition(content string, offset int) protocol.Position {
+	if offset > len(content) {
+		offset = len(content)
+	}
+
+	line := strings.Count(content[:offset], "\n")
+	lineStart := strings.LastIndexByte(content[:offset], '\n') + 1
+	return protocol.Position{Line: uint32(line), Character: uint32(offset - lineStart)}
+}
+
+// FromLSPPosition converts the LSP position to a byte offset into content.
+func FromLSPPosition(content string, pos protocol.Position) int {
+	offset := 0
+	for i := uint32(0); i < pos.Line; i++ {
+		next := strings.IndexByte(content[offset:], '\n')
+		if next == -1 {
+			return len(content)
+		}
+
+		offset += next + 1
+	}
+
+	lineEnd := strings.IndexByte(content[offset:], '\n')
+	if lineEnd == -1 {
+		lineEnd = len(content) - offset
+	}
+
+	if int(pos.Character) > lineEnd {
+		return offset + lineEnd
+	}
+
+	return offset + int(pos.Character)
+}
+
 func PosToLoc(content string, pos uint) (row uint, col uint) {
 	log.Println(
 		"DEPRECATED: migrate from this to using the StartCol and EndCol provided by *position.Position",
@@ -7,6 +7,7 @@ import (
 	"runtime"
 	"testing"
 
+	"github.com/laytan/go-lsp-protocol/pkg/lsp/protocol"
 	"github.com/laytan/phpls/pkg/pathutils"
 	"github.com/laytan/phpls/pkg/position"
 	"github.com/stretchr/testify/require"
@@ -67,3 +68,20 @@ func TestPosition(t *testing.T) {
 		})
 	}
 }
+
+func TestLSPPosition(t *testing.T) {
+	t.Parallel()
+
+	content := "<?php\n\necho 'hello';\n"
+	expectations := map[int]protocol.Position{
+		0:  {Line: 0, Character: 0},
+		6:  {Line: 1, Character: 0},
+		12: {Line: 2, Character: 5},
+		21: {Line: 3, Character: 0},
+	}
+
+	for offset, pos := range expectations {
+		require.Equal(t, pos, position.ToLSPPosition(content, offset))
+		require.Equal(t, offset, position.FromLSPPosition(content, pos))
+	}
+}
@@ -0,0 +1,115 @@
+package psalm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+type Severity string
+
+const (
+	Error Severity = "error"
+	Info  Severity = "info"
+)
+
+// Issue is a single issue from psalm's JSON output.
+type Issue struct {
+	Severity Severity `json:"severity"`
+	// The issue type, for example UndefinedMethod.
+	Type     string `json:"type"`
+	Msg      string `json:"message"`
+	FilePath string `json:"file_path"`
+	// Byte offsets of the selected text in the file.
+	From int    `json:"from"`
+	To   int    `json:"to"`
+	Link string `json:"link"`
+}
+
+var ErrCancelled = errors.New("cancelled")
+
+// Analyze writes the content to a temporary file next to the path, so that
+// the psalm configuration applies, and analyzes it.
+func Analyze(
+	ctx context.Context,
+	executable string,
+	path string,
+	content []byte,
+) ([]*Issue, error) {
+	// The random part is put before the name so psalm sees a .php file.
+	dir, name := filepath.Split(path)
+	fh, err := os.CreateTemp(dir, ".psalm-tmp.*."+name)
+	if err != nil {
+		return nil, fmt.Errorf("creating temp file for psalm: %w", err)
+	}
+
+	if _, err := fh.Write(content); err != nil {
+		return nil, fmt.Errorf("writing to temp file %q: %w", fh.Name(), err)
+	}
+
+	defer func() {
+		go func() {
+			p := fh.Name()
+			if err := fh.Close(); err != nil {
+				log.Println(fmt.Errorf("[ERROR]: psalm closing temp file %q: %w", p, err))
+			}
+
+			if err := os.Remove(fh.Name()); err != nil {
+				log.Println(fmt.Errorf("[ERROR]: psalm removing temp file %q: %w", p, err))
+			}
+		}()
+	}()
+
+	return AnalyzePath(ctx, executable, fh.Name())
+}
+
+func AnalyzePath(ctx context.Context, executable string, path string) ([]*Issue, error) {
+	cmd := exec.CommandContext(
+		ctx,
+		executable,
+		"--output-format=json",
+		"--no-progress",
+		"--no-suggestions",
+		path,
+	)
+
+	out, err := cmd.Output()
+
+	// Psalm exits with 2 when issues are found.
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		if exitErr.ExitCode() == -1 {
+			return nil, ErrCancelled
+		}
+
+		if exitErr.ExitCode() != 2 {
+			return nil, fmt.Errorf("running psalm: %w: %s", err, exitErr.Stderr)
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("running psalm: %w", err)
+	}
+
+	return parse(out, path)
+}
+
+// parse decodes the JSON output, only keeping the issues in the given path.
+func parse(out []byte, path string) ([]*Issue, error) {
+	var issues []*Issue
+	if err := json.Unmarshal(out, &issues); err != nil {
+		return nil, fmt.Errorf("decoding psalm output %q: %w", string(out), err)
+	}
+
+	filtered := make([]*Issue, 0, len(issues))
+	for _, issue := range issues {
+		if issue.FilePath == path {
+			filtered = append(filtered, issue)
+		}
+	}
+
+	return filtered, nil
+}
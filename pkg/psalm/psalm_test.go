@@ -0,0 +1,99 @@
+package psalm_test
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/laytan/phpls/pkg/pathutils"
+	"github.com/laytan/phpls/pkg/psalm"
+	"github.com/stretchr/testify/require"
+)
+
+var testdata = filepath.Join(pathutils.Root(), "pkg", "psalm", "testdata")
+
+func TestAnalyze(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "test.php")
+	code := []byte("<?php\n\n$unused = 1;\nundefined_function();\n")
+
+	issues, err := psalm.Analyze(
+		context.Background(),
+		filepath.Join(testdata, "psalm_issues.sh"),
+		path,
+		code,
+	)
+	require.NoError(t, err)
+
+	// The issues in other files are filtered out.
+	require.Len(t, issues, 2)
+
+	// Analyzed in a temporary file next to the path that still ends in .php.
+	tmp := issues[0].FilePath
+	require.Equal(t, filepath.Dir(path), filepath.Dir(tmp))
+	require.True(t, strings.HasPrefix(filepath.Base(tmp), ".psalm-tmp."), tmp)
+	require.True(t, strings.HasSuffix(tmp, ".test.php"), tmp)
+
+	require.Equal(t, &psalm.Issue{
+		Severity: psalm.Info,
+		Type:     "UnusedVariable",
+		Msg:      "$unused is never referenced or the value is not used",
+		FilePath: tmp,
+		From:     7,
+		To:       14,
+		Link:     "https://psalm.dev/024",
+	}, issues[0])
+
+	require.Equal(t, &psalm.Issue{
+		Severity: psalm.Error,
+		Type:     "UndefinedFunction",
+		Msg:      "Function undefined_function does not exist",
+		FilePath: tmp,
+		From:     20,
+		To:       40,
+		Link:     "https://psalm.dev/021",
+	}, issues[1])
+}
+
+func TestAnalyzePathExitCodes(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(testdata, "test.php")
+
+	t.Run("issues found", func(t *testing.T) {
+		t.Parallel()
+
+		issues, err := psalm.AnalyzePath(
+			context.Background(),
+			filepath.Join(testdata, "psalm_issues.sh"),
+			path,
+		)
+		require.NoError(t, err)
+		require.Len(t, issues, 2)
+	})
+
+	t.Run("no issues", func(t *testing.T) {
+		t.Parallel()
+
+		issues, err := psalm.AnalyzePath(
+			context.Background(),
+			filepath.Join(testdata, "psalm_clean.sh"),
+			path,
+		)
+		require.NoError(t, err)
+		require.Empty(t, issues)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := psalm.AnalyzePath(
+			context.Background(),
+			filepath.Join(testdata, "psalm_error.sh"),
+			path,
+		)
+		require.ErrorContains(t, err, "Could not locate a config XML file")
+	})
+}
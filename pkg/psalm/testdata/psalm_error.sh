@@ -0,0 +1,4 @@
+#!/bin/sh
+# Mimics psalm failing to run, for example without a configuration file.
+echo "Could not locate a config XML file" >&2
+exit 1
@@ -0,0 +1,230 @@
+// Package report parses the common output formats of static analysis tools
+// into problems.
+package report
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Problem is a single problem reported by a tool.
+// Lines and columns are 1-based, a zero column means the whole line.
+type Problem struct {
+	File      string
+	Line      int
+	Column    int
+	EndLine   int
+	EndColumn int
+	Severity  string
+	Code      string
+	Message   string
+}
+
+var ErrNoPattern = errors.New("regex format requires a pattern")
+
+type checkstyle struct {
+	Files []struct {
+		Name   string `xml:"name,attr"`
+		Errors []struct {
+			Line     int    `xml:"line,attr"`
+			Column   int    `xml:"column,attr"`
+			Severity string `xml:"severity,attr"`
+			Message  string `xml:"message,attr"`
+			Source   string `xml:"source,attr"`
+		} `xml:"error"`
+	} `xml:"file"`
+}
+
+// ParseCheckstyle parses checkstyle XML output.
+func ParseCheckstyle(out []byte) ([]*Problem, error) {
+	var report checkstyle
+	if err := xml.Unmarshal(out, &report); err != nil {
+		return nil, fmt.Errorf("decoding checkstyle output %q: %w", string(out), err)
+	}
+
+	var problems []*Problem
+	for _, file := range report.Files {
+		for _, e := range file.Errors {
+			problems = append(problems, &Problem{
+				File:     file.Name,
+				Line:     e.Line,
+				Column:   e.Column,
+				Severity: e.Severity,
+				Code:     e.Source,
+				Message:  e.Message,
+			})
+		}
+	}
+
+	return problems, nil
+}
+
+type sarif struct {
+	Runs []struct {
+		Results []struct {
+			RuleID  string `json:"ruleId"`
+			Level   string `json:"level"`
+			Message struct {
+				Text string `json:"text"`
+			} `json:"message"`
+			Locations []struct {
+				PhysicalLocation struct {
+					ArtifactLocation struct {
+						URI string `json:"uri"`
+					} `json:"artifactLocation"`
+					Region struct {
+						StartLine   int `json:"startLine"`
+						StartColumn int `json:"startColumn"`
+						EndLine     int `json:"endLine"`
+						EndColumn   int `json:"endColumn"`
+					} `json:"region"`
+				} `json:"physicalLocation"`
+			} `json:"locations"`
+		} `json:"results"`
+	} `json:"runs"`
+}
+
+// ParseSarif parses SARIF output, results without a location are skipped.
+// SARIF end columns are exclusive, they are converted to be inclusive like the
+// other formats.
+func ParseSarif(out []byte) ([]*Problem, error) {
+	var report sarif
+	if err := json.Unmarshal(out, &report); err != nil {
+		return nil, fmt.Errorf("decoding sarif output %q: %w", string(out), err)
+	}
+
+	var problems []*Problem
+	for _, run := range report.Runs {
+		for _, result := range run.Results {
+			if len(result.Locations) == 0 {
+				continue
+			}
+
+			loc := result.Locations[0].PhysicalLocation
+			problem := &Problem{
+				File:     loc.ArtifactLocation.URI,
+				Line:     loc.Region.StartLine,
+				Column:   loc.Region.StartColumn,
+				EndLine:  loc.Region.EndLine,
+				Severity: result.Level,
+				Code:     result.RuleID,
+				Message:  result.Message.Text,
+			}
+
+			if loc.Region.EndColumn > 0 {
+				problem.EndColumn = loc.Region.EndColumn - 1
+			}
+
+			problems = append(problems, problem)
+		}
+	}
+
+	return problems, nil
+}
+
+type jsonLine struct {
+	File      string `json:"file"`
+	Line      int    `json:"line"`
+	Column    int    `json:"column"`
+	EndLine   int    `json:"end_line"`
+	EndColumn int    `json:"end_column"`
+	Severity  string `json:"severity"`
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+}
+
+// ParseJSONLines parses output with a JSON object per line, lines that are not
+// JSON objects are skipped.
+func ParseJSONLines(out []byte) ([]*Problem, error) {
+	var problems []*Problem
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 || line[0] != '{' {
+			continue
+		}
+
+		var l jsonLine
+		if err := json.Unmarshal(line, &l); err != nil {
+			continue
+		}
+
+		problems = append(problems, &Problem{
+			File:      l.File,
+			Line:      l.Line,
+			Column:    l.Column,
+			EndLine:   l.EndLine,
+			EndColumn: l.EndColumn,
+			Severity:  l.Severity,
+			Code:      l.Code,
+			Message:   l.Message,
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading json lines output: %w", err)
+	}
+
+	return problems, nil
+}
+
+// ParseRegex parses each line of the output with the pattern, using the named
+// groups file, line, column, end_line, end_column, severity, code and message.
+// Lines that don't match, or have no line number, are skipped.
+func ParseRegex(pattern string, out []byte) ([]*Problem, error) {
+	if pattern == "" {
+		return nil, ErrNoPattern
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("compiling pattern %q: %w", pattern, err)
+	}
+
+	var problems []*Problem
+	for _, line := range strings.Split(string(out), "\n") {
+		match := re.FindStringSubmatch(strings.TrimRight(line, "\r"))
+		if match == nil {
+			continue
+		}
+
+		problem := &Problem{}
+		for i, name := range re.SubexpNames() {
+			value := strings.TrimSpace(match[i])
+			switch name {
+			case "file":
+				problem.File = value
+			case "line":
+				problem.Line, _ = strconv.Atoi(value)
+			case "column":
+				problem.Column, _ = strconv.Atoi(value)
+			case "end_line":
+				problem.EndLine, _ = strconv.Atoi(value)
+			case "end_column":
+				problem.EndColumn, _ = strconv.Atoi(value)
+			case "severity":
+				problem.Severity = value
+			case "code":
+				problem.Code = value
+			case "message":
+				problem.Message = value
+			}
+		}
+
+		if problem.Line == 0 {
+			continue
+		}
+
+		problems = append(problems, problem)
+	}
+
+	return problems, nil
+}
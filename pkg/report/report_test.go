@@ -0,0 +1,134 @@
+package report_test
+
+import (
+	"testing"
+
+	"github.com/laytan/phpls/pkg/report"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCheckstyle(t *testing.T) {
+	t.Parallel()
+
+	out := `<?xml version="1.0" encoding="UTF-8"?>
+<checkstyle version="3.7.2">
+<file name="/app/src/Foo.php">
+ <error line="3" column="5" severity="error" message="Missing doc comment" source="Squiz.Commenting.FunctionComment.Missing"/>
+ <error line="10" severity="warning" message="Line too long"/>
+</file>
+<file name="/app/src/Bar.php">
+</file>
+</checkstyle>`
+
+	problems, err := report.ParseCheckstyle([]byte(out))
+	require.NoError(t, err)
+	require.Equal(t, []*report.Problem{
+		{
+			File:     "/app/src/Foo.php",
+			Line:     3,
+			Column:   5,
+			Severity: "error",
+			Code:     "Squiz.Commenting.FunctionComment.Missing",
+			Message:  "Missing doc comment",
+		},
+		{
+			File:     "/app/src/Foo.php",
+			Line:     10,
+			Severity: "warning",
+			Message:  "Line too long",
+		},
+	}, problems)
+
+	_, err = report.ParseCheckstyle([]byte("not xml"))
+	require.Error(t, err)
+}
+
+func TestParseSarif(t *testing.T) {
+	t.Parallel()
+
+	out := `{
+  "version": "2.1.0",
+  "runs": [{
+    "tool": {"driver": {"name": "tool"}},
+    "results": [
+      {
+        "ruleId": "unused-variable",
+        "level": "note",
+        "message": {"text": "Unused variable $a"},
+        "locations": [{
+          "physicalLocation": {
+            "artifactLocation": {"uri": "file:///app/src/Foo.php"},
+            "region": {"startLine": 4, "startColumn": 9, "endLine": 4, "endColumn": 11}
+          }
+        }]
+      },
+      {
+        "ruleId": "no-location",
+        "message": {"text": "Project level problem"}
+      }
+    ]
+  }]
+}`
+
+	problems, err := report.ParseSarif([]byte(out))
+	require.NoError(t, err)
+	require.Equal(t, []*report.Problem{
+		{
+			File:      "file:///app/src/Foo.php",
+			Line:      4,
+			Column:    9,
+			EndLine:   4,
+			EndColumn: 10,
+			Severity:  "note",
+			Code:      "unused-variable",
+			Message:   "Unused variable $a",
+		},
+	}, problems)
+}
+
+func TestParseJSONLines(t *testing.T) {
+	t.Parallel()
+
+	out := `Starting analysis
+{"file": "src/Foo.php", "line": 2, "column": 1, "severity": "error", "code": "E1", "message": "Bad"}
+
+{"file": "src/Foo.php", "line": 5, "end_line": 6, "end_column": 3, "message": "Spans lines"}
+{broken
+`
+
+	problems, err := report.ParseJSONLines([]byte(out))
+	require.NoError(t, err)
+	require.Equal(t, []*report.Problem{
+		{File: "src/Foo.php", Line: 2, Column: 1, Severity: "error", Code: "E1", Message: "Bad"},
+		{File: "src/Foo.php", Line: 5, EndLine: 6, EndColumn: 3, Message: "Spans lines"},
+	}, problems)
+}
+
+func TestParseRegex(t *testing.T) {
+	t.Parallel()
+
+	pattern := `^(?P<file>[^:]+):(?P<line>\d+):(?P<column>\d+)?:? (?P<severity>\w+) (?P<message>.+?)(?: \[(?P<code>[^\]]+)\])?$`
+	out := "src/Foo.php:3:7: error Undefined variable $b [undefined-variable]\r\n" +
+		"Summary: 2 problems\n" +
+		"src/Foo.php:9: warning Unused import\n"
+
+	problems, err := report.ParseRegex(pattern, []byte(out))
+	require.NoError(t, err)
+	require.Equal(t, []*report.Problem{
+		{
+			File:     "src/Foo.php",
+			Line:     3,
+			Column:   7,
+			Severity: "error",
+			Code:     "undefined-variable",
+			Message:  "Undefined variable $b",
+		},
+		{File: "src/Foo.php", Line: 9, Severity: "warning", Message: "Unused import"},
+	}, problems)
+
+	_, err = report.ParseRegex("", []byte(out))
+	require.ErrorIs(t, err, report.ErrNoPattern)
+
+	_, err = report.ParseRegex("(", []byte(out))
+	require.Error(t, err)
+}
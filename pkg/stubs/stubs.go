@@ -2,16 +2,26 @@ package stubs
 
 import (
 	"fmt"
+	"io/fs"
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"sync/atomic"
 
+	"github.com/laytan/php-parser/pkg/conf"
+	"github.com/laytan/php-parser/pkg/parser"
+	"github.com/laytan/php-parser/pkg/version"
 	"github.com/laytan/phpls/pkg/phpversion"
 	"github.com/laytan/phpls/pkg/stubs/stubtransform"
 	thirdparty "github.com/laytan/phpls/third_party"
+	"golang.org/x/sync/errgroup"
 )
 
+// The version to parse the stubs with, same as stubtransform.
+var parserVersion = &version.Version{Major: 8, Minor: 1}
+
 // Generate `total.go`.
 //go:generate go run total_gen.go
 
@@ -58,3 +68,76 @@ func Generate(
 
 	return stubsPath, nil
 }
+
+// Availability parses all the stubs and returns the PHP versions the
+// built-in symbols that are not available in every version are available in,
+// keyed by their lowercased FQN.
+func Availability() (map[string]*stubtransform.Availability, error) {
+	var mu sync.Mutex
+	result := make(map[string]*stubtransform.Availability)
+
+	g := errgroup.Group{}
+	g.SetLimit(stubtransform.MaxConcurrency)
+
+	if err := fs.WalkDir(thirdparty.Stubs, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("walkdir error: %w", err)
+		}
+
+		relPath := strings.TrimPrefix(path, "phpstorm-stubs")
+		if _, ok := stubtransform.NonStubs[relPath]; ok {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if d.IsDir() || !strings.HasSuffix(path, ".php") {
+			return nil
+		}
+
+		g.Go(func() error {
+			content, err := fs.ReadFile(thirdparty.Stubs, path)
+			if err != nil {
+				return fmt.Errorf("reading %s: %w", path, err)
+			}
+
+			root, err := parser.Parse(content, conf.Config{Version: parserVersion})
+			if err != nil {
+				return fmt.Errorf("parsing %s: %w", path, err)
+			}
+
+			availabilities := stubtransform.NewAvailabilities()
+			root.Accept(availabilities)
+
+			mu.Lock()
+			defer mu.Unlock()
+			for key, availability := range availabilities.Result {
+				if existing, ok := result[key]; ok {
+					availability = existing.Union(availability)
+				}
+
+				result[key] = availability
+			}
+
+			return nil
+		})
+
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("walking stubs: %w", err)
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, fmt.Errorf("waiting for stubs to be parsed: %w", err)
+	}
+
+	for key, availability := range result {
+		if !availability.IsBounded() {
+			delete(result, key)
+		}
+	}
+
+	return result, nil
+}
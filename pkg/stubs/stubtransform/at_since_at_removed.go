@@ -3,11 +3,9 @@ package stubtransform
 import (
 	"bytes"
 
-	"appliedgo.net/what"
 	"github.com/laytan/php-parser/pkg/ast"
 	"github.com/laytan/php-parser/pkg/token"
 	"github.com/laytan/php-parser/pkg/visitor"
-	"github.com/laytan/phpls/pkg/phpdoxer"
 	"github.com/laytan/phpls/pkg/phpversion"
 )
 
@@ -97,35 +95,30 @@ func (r *AtSinceAtRemoved) filterStmts(nodes []ast.Vertex) []ast.Vertex {
 }
 
 func (r *AtSinceAtRemoved) shouldRemoveFunction(n *ast.StmtFunction) bool {
-	return r.shouldRemove(n.FunctionTkn.FreeFloating) ||
-		r.shouldRemove(r.attrGroupFreefloatings(n.AttrGroups))
+	return r.shouldRemove(functionDocTokens(n))
 }
 
 func (r *AtSinceAtRemoved) shouldRemoveClass(n *ast.StmtClass) bool {
-	return r.shouldRemove(n.ClassTkn.FreeFloating) ||
-		r.shouldRemove(r.attrGroupFreefloatings(n.AttrGroups)) ||
-		r.shouldRemove(r.identifiersFreefloatings(n.Modifiers))
+	return r.shouldRemove(classDocTokens(n))
 }
 
 func (r *AtSinceAtRemoved) shouldRemoveInterface(n *ast.StmtInterface) bool {
-	return r.shouldRemove(n.InterfaceTkn.FreeFloating) ||
-		r.shouldRemove(r.attrGroupFreefloatings(n.AttrGroups))
+	return r.shouldRemove(interfaceDocTokens(n))
 }
 
 func (r *AtSinceAtRemoved) shouldRemoveTrait(n *ast.StmtTrait) bool {
-	return r.shouldRemove(n.TraitTkn.FreeFloating) ||
-		r.shouldRemove(r.attrGroupFreefloatings(n.AttrGroups))
+	return r.shouldRemove(traitDocTokens(n))
 }
 
 func (r *AtSinceAtRemoved) shouldRemoveMethod(n *ast.StmtClassMethod) bool {
 	return r.shouldRemove(n.FunctionTkn.FreeFloating) ||
-		r.shouldRemove(r.attrGroupFreefloatings(n.AttrGroups)) ||
-		r.shouldRemove(r.identifiersFreefloatings(n.Modifiers))
+		r.shouldRemove(attrGroupFreefloatings(n.AttrGroups)) ||
+		r.shouldRemove(identifiersFreefloatings(n.Modifiers))
 }
 
 func (r *AtSinceAtRemoved) shouldRemovePropertyList(n *ast.StmtPropertyList) bool {
-	return r.shouldRemove(r.attrGroupFreefloatings(n.AttrGroups)) ||
-		r.shouldRemove(r.identifiersFreefloatings(n.Modifiers))
+	return r.shouldRemove(attrGroupFreefloatings(n.AttrGroups)) ||
+		r.shouldRemove(identifiersFreefloatings(n.Modifiers))
 }
 
 func (r *AtSinceAtRemoved) shouldRemoveConstList(n *ast.StmtConstList) bool {
@@ -133,7 +126,7 @@ func (r *AtSinceAtRemoved) shouldRemoveConstList(n *ast.StmtConstList) bool {
 }
 
 func (r *AtSinceAtRemoved) shouldRemoveClassConstList(n *ast.StmtClassConstList) bool {
-	return r.shouldRemove(r.identifiersFreefloatings(n.Modifiers)) ||
+	return r.shouldRemove(identifiersFreefloatings(n.Modifiers)) ||
 		r.shouldRemove(n.ConstTkn.FreeFloating)
 }
 
@@ -152,21 +145,11 @@ func (r *AtSinceAtRemoved) shouldRemoveExpression(n *ast.StmtExpression) bool {
 
 // Handles `define()` function calls (constants).
 func (r *AtSinceAtRemoved) shouldRemoveFunctionCall(fnCall *ast.ExprFunctionCall) bool {
-	if fnName, ok := fnCall.Function.(*ast.Name); ok {
-		if len(fnName.Parts) != 1 {
-			return false
-		}
-
-		if fnNameStr, ok := fnName.Parts[0].(*ast.NamePart); ok {
-			if !bytes.Equal(fnNameStr.Value, []byte("define")) {
-				return false
-			}
-
-			return r.shouldRemove(fnNameStr.StringTkn.FreeFloating)
-		}
+	if !isDefine(fnCall) {
+		return false
 	}
 
-	return false
+	return r.shouldRemove(defineDocTokens(fnCall))
 }
 
 // Basic handling of superglobals.
@@ -181,37 +164,41 @@ func (r *AtSinceAtRemoved) shouldRemoveAssignment(assign *ast.ExprAssign) bool {
 }
 
 func (r *AtSinceAtRemoved) shouldRemove(freefloatings []*token.Token) bool {
-	for _, t := range freefloatings {
-		if t.ID == token.T_DOC_COMMENT || t.ID == token.T_COMMENT {
-			nodes, err := phpdoxer.ParseDoc(string(t.Value))
-			if err != nil {
-				what.Happens("parse doc %s: %w", string(t.Value), err.Error())
-				continue
-			}
-
-			for _, docNode := range nodes {
-				switch typedDocNode := docNode.(type) {
-				case *phpdoxer.NodeSince:
-					if typedDocNode.Version.IsHigherThan(r.version) {
-						return true
-					}
-
-				case *phpdoxer.NodeRemoved:
-					if r.version.Equals(typedDocNode.Version) || r.version.IsHigherThan(typedDocNode.Version) {
-						return true
-					}
-
-				default:
-					continue
-				}
-			}
-		}
+	return !DocAvailability(freefloatings).AvailableIn(r.version)
+}
+
+func functionDocTokens(n *ast.StmtFunction) []*token.Token {
+	return append(attrGroupFreefloatings(n.AttrGroups), n.FunctionTkn.FreeFloating...)
+}
+
+func classDocTokens(n *ast.StmtClass) []*token.Token {
+	freefloatings := append(attrGroupFreefloatings(n.AttrGroups), n.ClassTkn.FreeFloating...)
+	return append(freefloatings, identifiersFreefloatings(n.Modifiers)...)
+}
+
+func interfaceDocTokens(n *ast.StmtInterface) []*token.Token {
+	return append(attrGroupFreefloatings(n.AttrGroups), n.InterfaceTkn.FreeFloating...)
+}
+
+func traitDocTokens(n *ast.StmtTrait) []*token.Token {
+	return append(attrGroupFreefloatings(n.AttrGroups), n.TraitTkn.FreeFloating...)
+}
+
+func isDefine(fnCall *ast.ExprFunctionCall) bool {
+	fnName, ok := fnCall.Function.(*ast.Name)
+	if !ok || len(fnName.Parts) != 1 {
+		return false
 	}
 
-	return false
+	fnNameStr, ok := fnName.Parts[0].(*ast.NamePart)
+	return ok && bytes.Equal(fnNameStr.Value, []byte("define"))
+}
+
+func defineDocTokens(fnCall *ast.ExprFunctionCall) []*token.Token {
+	return fnCall.Function.(*ast.Name).Parts[0].(*ast.NamePart).StringTkn.FreeFloating
 }
 
-func (r *AtSinceAtRemoved) attrGroupFreefloatings(n []ast.Vertex) []*token.Token {
+func attrGroupFreefloatings(n []ast.Vertex) []*token.Token {
 	freefloatings := []*token.Token{}
 	for _, atr := range n {
 		if atrGroup, ok := atr.(*ast.AttributeGroup); ok {
@@ -222,7 +209,7 @@ func (r *AtSinceAtRemoved) attrGroupFreefloatings(n []ast.Vertex) []*token.Token
 	return freefloatings
 }
 
-func (r *AtSinceAtRemoved) identifiersFreefloatings(n []ast.Vertex) []*token.Token {
+func identifiersFreefloatings(n []ast.Vertex) []*token.Token {
 	freefloatings := []*token.Token{}
 	for _, atr := range n {
 		if ident, ok := atr.(*ast.Identifier); ok {
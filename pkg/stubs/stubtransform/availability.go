@@ -0,0 +1,309 @@
+package stubtransform
+
+import (
+	"bytes"
+	"strings"
+
+	"appliedgo.net/what"
+	"github.com/laytan/php-parser/pkg/ast"
+	"github.com/laytan/php-parser/pkg/token"
+	"github.com/laytan/php-parser/pkg/visitor"
+	"github.com/laytan/phpls/pkg/phpdoxer"
+	"github.com/laytan/phpls/pkg/phpversion"
+)
+
+// Availability is the range of PHP versions a stub element is available in,
+// a nil bound means it is unbounded on that side.
+type Availability struct {
+	// The first version the element is available in (@since or the from of the attribute).
+	Since *phpversion.PHPVersion
+	// The first version the element is not available in anymore (@removed).
+	Removed *phpversion.PHPVersion
+	// The last version the element is available in (the to of the attribute).
+	To *phpversion.PHPVersion
+}
+
+func (a *Availability) AvailableIn(version *phpversion.PHPVersion) bool {
+	if a.Since != nil && a.Since.IsHigherThan(version) {
+		return false
+	}
+
+	if a.Removed != nil && !a.Removed.IsHigherThan(version) {
+		return false
+	}
+
+	if a.To != nil && version.IsHigherThan(a.To) {
+		return false
+	}
+
+	return true
+}
+
+func (a *Availability) IsBounded() bool {
+	return a.Since != nil || a.Removed != nil || a.To != nil
+}
+
+// Intersect narrows the availability to the versions that are also in the other.
+func (a *Availability) Intersect(other *Availability) {
+	if other.Since != nil && (a.Since == nil || other.Since.IsHigherThan(a.Since)) {
+		a.Since = other.Since
+	}
+
+	if other.Removed != nil && (a.Removed == nil || a.Removed.IsHigherThan(other.Removed)) {
+		a.Removed = other.Removed
+	}
+
+	if other.To != nil && (a.To == nil || a.To.IsHigherThan(other.To)) {
+		a.To = other.To
+	}
+}
+
+// Union returns the availability that covers the versions of both.
+func (a *Availability) Union(b *Availability) *Availability {
+	res := &Availability{}
+	if a.Since != nil && b.Since != nil {
+		res.Since = a.Since
+		if a.Since.IsHigherThan(b.Since) {
+			res.Since = b.Since
+		}
+	}
+
+	if a.Removed != nil && b.Removed != nil {
+		res.Removed = a.Removed
+		if b.Removed.IsHigherThan(a.Removed) {
+			res.Removed = b.Removed
+		}
+	}
+
+	if a.To != nil && b.To != nil {
+		res.To = a.To
+		if b.To.IsHigherThan(a.To) {
+			res.To = b.To
+		}
+	}
+
+	return res
+}
+
+// DocAvailability returns the availability described by the @since and
+// @removed tags in the given doc comments.
+func DocAvailability(freefloatings []*token.Token) *Availability {
+	availability := &Availability{}
+	for _, t := range freefloatings {
+		if t.ID != token.T_DOC_COMMENT && t.ID != token.T_COMMENT {
+			continue
+		}
+
+		nodes, err := phpdoxer.ParseDoc(string(t.Value))
+		if err != nil {
+			what.Happens("parse doc %s: %w", string(t.Value), err.Error())
+			continue
+		}
+
+		for _, docNode := range nodes {
+			switch typedDocNode := docNode.(type) {
+			case *phpdoxer.NodeSince:
+				availability.Intersect(&Availability{Since: typedDocNode.Version})
+
+			case *phpdoxer.NodeRemoved:
+				availability.Intersect(&Availability{Removed: typedDocNode.Version})
+			}
+		}
+	}
+
+	return availability
+}
+
+// elementAvailable returns the availability described by the first
+// PhpStormStubsElementAvailable attribute, and the index of its attribute group.
+func elementAvailable(
+	targetter *targetter,
+	attrGroups []ast.Vertex,
+) (availability *Availability, attrGroupIndex int, ok bool) {
+	for attrI, attrGroup := range attrGroups {
+	Attributes:
+		for _, attr := range attrGroup.(*ast.AttributeGroup).Attrs {
+			if len(attr.(*ast.Attribute).Args) == 0 {
+				continue
+			}
+
+			if !targetter.MatchName(attr.(*ast.Attribute).Name) {
+				continue
+			}
+
+			availability := &Availability{}
+			for i, arg := range attr.(*ast.Attribute).Args {
+				if i > 1 {
+					break
+				}
+
+				var n []byte
+				var v *phpversion.PHPVersion
+				if argName, ok := arg.(*ast.Argument).Name.(*ast.Identifier); ok {
+					n = argName.Value
+				}
+
+				if exprStr, ok := arg.(*ast.Argument).Expr.(*ast.ScalarString); ok {
+					versionStr := strings.Trim(string(exprStr.Value), `'"`)
+					if version, ok := phpversion.FromString(versionStr); ok {
+						v = version
+					}
+				}
+
+				if v == nil {
+					continue Attributes
+				}
+
+				if bytes.Equal(n, []byte("from")) || i == 0 {
+					availability.Since = v
+				}
+
+				if bytes.Equal(n, []byte("to")) || i == 1 {
+					availability.To = v
+				}
+			}
+
+			return availability, attrI, true
+		}
+	}
+
+	return nil, 0, false
+}
+
+// Availabilities collects the availability of the functions, classes,
+// interfaces, traits and constants in the stubs, keyed by their lowercased FQN.
+//
+// Symbols available in every version are included too, because they can be
+// declared multiple times, use IsBounded to filter them.
+type Availabilities struct {
+	visitor.Null
+	targetter *targetter
+	namespace string
+
+	Result map[string]*Availability
+}
+
+func NewAvailabilities() *Availabilities {
+	return &Availabilities{
+		targetter: newElementAvailableTargetter(),
+		Result:    make(map[string]*Availability),
+	}
+}
+
+func (a *Availabilities) Root(n *ast.Root) {
+	a.collectStmts(n.Stmts)
+}
+
+func (a *Availabilities) StmtNamespace(n *ast.StmtNamespace) {
+	exit := a.targetter.EnterNamespace(n)
+	defer exit()
+
+	a.namespace = ""
+	if n.Name != nil {
+		a.namespace = string(bytes.Join(nameParts(n.Name), []byte(`\`)))
+	}
+
+	a.collectStmts(n.Stmts)
+}
+
+func (a *Availabilities) StmtUse(n *ast.StmtUseList) {
+	for _, s := range n.Uses {
+		s.Accept(a)
+	}
+}
+
+func (a *Availabilities) StmtUseDeclaration(n *ast.StmtUse) {
+	a.targetter.EnterUse(n)
+}
+
+func (a *Availabilities) collectStmts(nodes []ast.Vertex) {
+	for _, stmt := range nodes {
+		switch typedStmt := stmt.(type) {
+		case *ast.StmtUseList, *ast.StmtNamespace:
+			stmt.Accept(a)
+
+		case *ast.StmtFunction:
+			a.add(typedStmt.Name, functionDocTokens(typedStmt), typedStmt.AttrGroups)
+
+		case *ast.StmtClass:
+			a.add(typedStmt.Name, classDocTokens(typedStmt), typedStmt.AttrGroups)
+
+		case *ast.StmtInterface:
+			a.add(typedStmt.Name, interfaceDocTokens(typedStmt), typedStmt.AttrGroups)
+
+		case *ast.StmtTrait:
+			a.add(typedStmt.Name, traitDocTokens(typedStmt), typedStmt.AttrGroups)
+
+		case *ast.StmtConstList:
+			for _, constant := range typedStmt.Consts {
+				a.add(constant.(*ast.StmtConstant).Name, typedStmt.ConstTkn.FreeFloating, nil)
+			}
+
+		case *ast.StmtExpression:
+			fnCall, ok := typedStmt.Expr.(*ast.ExprFunctionCall)
+			if !ok || !isDefine(fnCall) || len(fnCall.Args) == 0 {
+				continue
+			}
+
+			arg, ok := fnCall.Args[0].(*ast.Argument)
+			if !ok {
+				continue
+			}
+
+			if name, ok := arg.Expr.(*ast.ScalarString); ok {
+				a.addName(
+					strings.Trim(string(name.Value), `'"`),
+					defineDocTokens(fnCall),
+					nil,
+				)
+			}
+		}
+	}
+}
+
+func (a *Availabilities) add(name ast.Vertex, freefloatings []*token.Token, attrGroups []ast.Vertex) {
+	ident, ok := name.(*ast.Identifier)
+	if !ok {
+		return
+	}
+
+	qualified := string(ident.Value)
+	if a.namespace != "" {
+		qualified = a.namespace + `\` + qualified
+	}
+
+	a.addName(qualified, freefloatings, attrGroups)
+}
+
+func (a *Availabilities) addName(qualified string, freefloatings []*token.Token, attrGroups []ast.Vertex) {
+	availability := DocAvailability(freefloatings)
+	if attrAvailability, _, ok := elementAvailable(a.targetter, attrGroups); ok {
+		availability.Intersect(attrAvailability)
+	}
+
+	key := strings.ToLower(`\` + qualified)
+
+	// Some stubs are declared multiple times for different versions, the
+	// symbol is available in the union of them.
+	if existing, ok := a.Result[key]; ok {
+		availability = existing.Union(availability)
+	}
+
+	a.Result[key] = availability
+}
+
+func nameParts(n ast.Vertex) [][]byte {
+	name, ok := n.(*ast.Name)
+	if !ok {
+		return nil
+	}
+
+	parts := make([][]byte, 0, len(name.Parts))
+	for _, part := range name.Parts {
+		if namePart, ok := part.(*ast.NamePart); ok {
+			parts = append(parts, namePart.Value)
+		}
+	}
+
+	return parts
+}
@@ -0,0 +1,78 @@
+package stubtransform_test
+
+import (
+	"testing"
+
+	"github.com/laytan/php-parser/pkg/conf"
+	"github.com/laytan/php-parser/pkg/parser"
+	"github.com/laytan/php-parser/pkg/version"
+	"github.com/laytan/phpls/pkg/phpversion"
+	"github.com/laytan/phpls/pkg/stubs/stubtransform"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAvailabilities(t *testing.T) {
+	t.Parallel()
+
+	input := `<?php
+    namespace Test;
+
+    use JetBrains\PhpStorm\Internal\PhpStormStubsElementAvailable;
+
+    /**
+     * @since 8.0
+     */
+    function added() {}
+
+    /**
+     * @removed 8.0
+     */
+    class Removed {}
+
+    #[PhpStormStubsElementAvailable(from: '7.1', to: '7.4')]
+    function versioned(int $a) {}
+
+    #[PhpStormStubsElementAvailable(from: '8.0')]
+    function versioned(int|string $a) {}
+
+    function always() {}
+
+    /**
+     * @since 7.2
+     */
+    const CONSTANT = 1;
+
+    /**
+     * @since 7.3
+     */
+    define('DEFINED', 1);
+    `
+
+	root, err := parser.Parse([]byte(input), conf.Config{Version: &version.Version{Major: 8, Minor: 1}})
+	require.NoError(t, err)
+
+	availabilities := stubtransform.NewAvailabilities()
+	root.Accept(availabilities)
+
+	v := func(major, minor uint8) *phpversion.PHPVersion {
+		return &phpversion.PHPVersion{Major: major, Minor: minor}
+	}
+
+	require.Equal(t, map[string]*stubtransform.Availability{
+		`\test\added`:     {Since: v(8, 0)},
+		`\test\removed`:   {Removed: v(8, 0)},
+		`\test\versioned`: {Since: v(7, 1)},
+		`\test\always`:    {},
+		`\test\constant`:  {Since: v(7, 2)},
+		`\defined`:        {Since: v(7, 3)},
+	}, availabilities.Result)
+
+	result := availabilities.Result
+	require.True(t, result[`\test\added`].AvailableIn(v(8, 1)))
+	require.False(t, result[`\test\added`].AvailableIn(v(7, 4)))
+	require.True(t, result[`\test\removed`].AvailableIn(v(7, 4)))
+	require.False(t, result[`\test\removed`].AvailableIn(v(8, 0)))
+	require.True(t, result[`\test\versioned`].AvailableIn(v(8, 2)))
+	require.False(t, result[`\test\versioned`].AvailableIn(v(7, 0)))
+	require.False(t, result[`\test\always`].IsBounded())
+}
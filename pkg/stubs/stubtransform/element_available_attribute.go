@@ -3,7 +3,6 @@ package stubtransform
 import (
 	"bytes"
 	"log"
-	"strings"
 
 	"github.com/laytan/php-parser/pkg/ast"
 	"github.com/laytan/php-parser/pkg/token"
@@ -27,17 +26,21 @@ func NewElementAvailableAttribute(
 	logger Logger,
 ) *ElementAvailableAttribute {
 	return &ElementAvailableAttribute{
-		version: version,
-		logger:  logger,
-		targetter: newTargetter([][]byte{
-			[]byte("JetBrains"),
-			[]byte("PhpStorm"),
-			[]byte("Internal"),
-			[]byte("PhpStormStubsElementAvailable"),
-		}),
+		version:   version,
+		logger:    logger,
+		targetter: newElementAvailableTargetter(),
 	}
 }
 
+func newElementAvailableTargetter() *targetter {
+	return newTargetter([][]byte{
+		[]byte("JetBrains"),
+		[]byte("PhpStorm"),
+		[]byte("Internal"),
+		[]byte("PhpStormStubsElementAvailable"),
+	})
+}
+
 func (e *ElementAvailableAttribute) Root(n *ast.Root) {
 	n.Stmts = e.filterStmts(n.Stmts)
 }
@@ -200,61 +203,20 @@ func (e *ElementAvailableAttribute) getRemovedParamNames(
 func (e *ElementAvailableAttribute) shouldRemove(
 	attrGroups []ast.Vertex,
 ) (bool, []ast.Vertex) {
-	for attrI, attrGroup := range attrGroups {
-	Attributes:
-		for _, attr := range attrGroup.(*ast.AttributeGroup).Attrs {
-			if len(attr.(*ast.Attribute).Args) == 0 {
-				continue
-			}
-
-			if !e.targetter.MatchName(attr.(*ast.Attribute).Name) {
-				continue
-			}
-
-			for i, arg := range attr.(*ast.Attribute).Args {
-				if i > 1 {
-					break
-				}
-
-				var n []byte
-				var v *phpversion.PHPVersion
-				if argName, ok := arg.(*ast.Argument).Name.(*ast.Identifier); ok {
-					n = argName.Value
-				}
-
-				if exprStr, ok := arg.(*ast.Argument).Expr.(*ast.ScalarString); ok {
-					versionStr := strings.Trim(string(exprStr.Value), `'"`)
-					if version, ok := phpversion.FromString(versionStr); ok {
-						v = version
-					}
-				}
-
-				if v == nil {
-					continue Attributes
-				}
-
-				if bytes.Equal(n, []byte("from")) || i == 0 {
-					if v.IsHigherThan(e.version) {
-						return true, attrGroups
-					}
-				}
-
-				if bytes.Equal(n, []byte("to")) || i == 1 {
-					if e.version.IsHigherThan(v) {
-						return true, attrGroups
-					}
-				}
-			}
+	availability, attrI, ok := elementAvailable(e.targetter, attrGroups)
+	if !ok {
+		return false, attrGroups
+	}
 
-			e.logRemoval()
-			attrGroups = slices.Delete(attrGroups, attrI, attrI+1)
-			if len(attrGroups) == 0 {
-				return false, nil
-			}
-			return false, attrGroups
-		}
+	if !availability.AvailableIn(e.version) {
+		return true, attrGroups
 	}
 
+	e.logRemoval()
+	attrGroups = slices.Delete(attrGroups, attrI, attrI+1)
+	if len(attrGroups) == 0 {
+		return false, nil
+	}
 	return false, attrGroups
 }
 